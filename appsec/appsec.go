@@ -25,6 +25,26 @@ import (
 
 var appsecDisabledLog sync.Once
 
+// BlockedOutboundRequestError is the error returned by the traced HTTP and gRPC
+// clients of contrib/net/http and contrib/google.golang.org/grpc when an
+// outbound request performed by a monitored request handler is blocked, for
+// instance because its destination URL was built out of user input to perform
+// a Server-Side Request Forgery (SSRF). When such an error is returned, the
+// caller must immediately abort its execution and the request handler's. The
+// blocking response will be automatically sent by the APM tracer middleware on
+// use according to your blocking configuration.
+// Use errors.As to check whether an error is of this type.
+type BlockedOutboundRequestError = sharedsec.BlockedOutboundRequestError
+
+// BlockedSQLQueryError is the error returned by the traced database/sql
+// connections of contrib/database/sql when a SQL query run by a monitored
+// request handler is blocked because user input was injected into it. When
+// such an error is returned, the caller must immediately abort its execution
+// and the request handler's. The blocking response will be automatically sent
+// by the APM tracer middleware on use according to your blocking configuration.
+// Use errors.As to check whether an error is of this type.
+type BlockedSQLQueryError = sharedsec.BlockedSQLQueryError
+
 // MonitorParsedHTTPBody runs the security monitoring rules on the given *parsed*
 // HTTP request body and returns if the HTTP request is suspicious and configured to be blocked.
 // The given context must be the HTTP request context as returned
@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+// BlockRequestConfig is the configuration of the blocking response sent by
+// BlockRequest. This configuration can be set by combining one or several
+// BlockRequestOption with a call to BlockRequest().
+type BlockRequestConfig struct {
+	// StatusCode is the HTTP status code of the blocking response.
+	// Defaults to 403.
+	StatusCode int
+	// GRPCStatusCode is the gRPC status code of the blocking response.
+	// Defaults to 10 (Aborted).
+	GRPCStatusCode int
+	// Template is the type of the HTTP blocking response body, either "json",
+	// "html" or "auto" to choose it out of the request's Accept header. The
+	// body is the blocking template configured with the environment variables
+	// DD_APPSEC_HTTP_BLOCKED_TEMPLATE_JSON and
+	// DD_APPSEC_HTTP_BLOCKED_TEMPLATE_HTML, or the default ones.
+	// Defaults to "auto".
+	Template string
+}
+
+// BlockRequestOption represents a function that can be provided as a parameter to BlockRequest.
+type BlockRequestOption func(*BlockRequestConfig)
+
+// WithBlockingStatusCode returns the option setting the HTTP status code of the blocking response.
+func WithBlockingStatusCode(code int) BlockRequestOption {
+	return func(cfg *BlockRequestConfig) {
+		cfg.StatusCode = code
+	}
+}
+
+// WithBlockingGRPCStatusCode returns the option setting the gRPC status code of the blocking response.
+func WithBlockingGRPCStatusCode(code int) BlockRequestOption {
+	return func(cfg *BlockRequestConfig) {
+		cfg.GRPCStatusCode = code
+	}
+}
+
+// WithBlockingTemplate returns the option setting the type of the HTTP blocking response body, either "json", "html"
+// or "auto".
+func WithBlockingTemplate(template string) BlockRequestOption {
+	return func(cfg *BlockRequestConfig) {
+		cfg.Template = template
+	}
+}
+
+// BlockRequest blocks the HTTP or gRPC request whose context is given, so that
+// business logic decisions, such as fraud detection, can block a request the
+// same way the security rules do. An error is always returned when the request
+// is blocked and the caller must immediately abort its execution and the
+// request handler's. The blocking response will be automatically sent by the
+// APM tracer middleware on use, and the service entry span is tagged as
+// blocked and kept.
+// The given context must be the request context of a request handler
+// monitored by an APM tracer middleware. Calls to this function are ignored
+// otherwise.
+// This function always returns nil when appsec is disabled.
+func BlockRequest(ctx context.Context, opts ...BlockRequestOption) error {
+	cfg := BlockRequestConfig{
+		StatusCode:     http.StatusForbidden,
+		GRPCStatusCode: 10,
+		Template:       "auto",
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return applyAction(ctx, sharedsec.NewBlockRequestAction(cfg.StatusCode, cfg.GRPCStatusCode, cfg.Template))
+}
+
+// RedirectRequest redirects the HTTP request whose context is given to the
+// given URL with a 303 See Other response. gRPC requests are blocked instead.
+// An error is always returned when the request is redirected and the caller
+// must immediately abort its execution and the request handler's. The
+// redirection response will be automatically sent by the APM tracer middleware
+// on use, and the service entry span is tagged as blocked and kept.
+// The given context must be the request context of a request handler
+// monitored by an APM tracer middleware. Calls to this function are ignored
+// otherwise.
+// This function always returns nil when appsec is disabled.
+func RedirectRequest(ctx context.Context, url string) error {
+	return applyAction(ctx, sharedsec.NewRedirectRequestAction(http.StatusSeeOther, url))
+}
+
+func applyAction(ctx context.Context, action *sharedsec.Action) error {
+	if !appsec.Enabled() {
+		appsecDisabledLog.Do(func() { log.Warn("appsec: not enabled. Requests won't be blocked.") })
+		return nil
+	}
+	err := sharedsec.BlockRequest(ctx, action)
+	if err != nil {
+		if span := getRootSpan(ctx); span != nil {
+			span.SetTag(ext.SamplingPriority, ext.PriorityUserKeep)
+		}
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/appsec"
+	httptrace "github.com/nowfred/dd-trace-go/contrib/net/http"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	privateAppsec "github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRequest(t *testing.T) {
+	t.Run("early-return/appsec-disabled", func(t *testing.T) {
+		require.NoError(t, appsec.BlockRequest(context.Background()))
+		require.NoError(t, appsec.RedirectRequest(context.Background(), "/"))
+	})
+
+	privateAppsec.Start()
+	defer privateAppsec.Stop()
+	if !privateAppsec.Enabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+
+	t.Run("unmonitored", func(t *testing.T) {
+		require.NoError(t, appsec.BlockRequest(context.Background()))
+	})
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/block", func(w http.ResponseWriter, r *http.Request) {
+		if err := appsec.BlockRequest(r.Context(), appsec.WithBlockingStatusCode(http.StatusTeapot), appsec.WithBlockingTemplate("json")); err != nil {
+			return
+		}
+		w.Write([]byte("Hello World!\n"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		if err := appsec.RedirectRequest(r.Context(), "/blocked"); err != nil {
+			return
+		}
+		w.Write([]byte("Hello World!\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	for _, tc := range []struct {
+		path     string
+		status   int
+		location string
+	}{
+		{path: "/block", status: http.StatusTeapot},
+		{path: "/redirect", status: http.StatusSeeOther, location: "/blocked"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			res, err := client.Get(srv.URL + tc.path)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, tc.status, res.StatusCode)
+			require.Equal(t, tc.location, res.Header.Get("Location"))
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			require.Equal(t, ext.PriorityUserKeep, spans[0].Tag(ext.SamplingPriority))
+			// Redirections are also reported as blocked requests
+			require.Equal(t, true, spans[0].Tag("appsec.blocked"))
+		})
+	}
+}
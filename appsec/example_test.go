@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/appsec"
 	echotrace "github.com/nowfred/dd-trace-go/contrib/labstack/echo.v4"
@@ -79,3 +80,47 @@ func ExampleSetUser() {
 		w.Write([]byte("User monitored using AppSec SetUser SDK\n"))
 	})
 }
+
+// Protect the files opened by an HTTP request handler against path traversals
+func ExampleOpen() {
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		// Use the SDK to open the file, which is blocked when its path was
+		// built out of user input to perform a path traversal
+		f, err := appsec.Open(r.Context(), "/var/www/"+r.URL.Query().Get("name"))
+		if err != nil {
+			// Abort the handler: the blocking response is sent by the middleware
+			// when the error is a *appsec.BlockedFileOpenError
+			return
+		}
+		defer f.Close()
+		io.Copy(w, f)
+	})
+	http.ListenAndServe(":8080", mux)
+}
+
+func isFraudulent(r *http.Request) bool {
+	return r.Header.Get("fraud-score") == "high"
+}
+
+// Block requests according to business logic decisions
+func ExampleBlockRequest() {
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/checkout", func(w http.ResponseWriter, r *http.Request) {
+		if isFraudulent(r) {
+			// Abort the handler: the blocking response is sent by the middleware
+			appsec.BlockRequest(r.Context(), appsec.WithBlockingStatusCode(http.StatusForbidden))
+			return
+		}
+		w.Write([]byte("Order placed\n"))
+	})
+	http.ListenAndServe(":8080", mux)
+}
+
+// Block abusive clients by IP address without remote configuration
+func ExampleDenyIP() {
+	// Block the requests of a client IP address for one hour
+	appsec.DenyIP("203.0.113.7", time.Hour)
+	// Never block the requests of an internal network by IP address
+	appsec.AllowIP("10.0.0.0/8", 0)
+}
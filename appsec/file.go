@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"context"
+	"os"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+// BlockedFileOpenError is the error returned by MonitorFileOpen, Open and
+// OpenFile when opening a file is blocked because its path was built out of
+// user input to perform a path traversal or a Local File Inclusion (LFI).
+// When such an error is returned, the caller must immediately abort its
+// execution and the request handler's. The blocking response will be
+// automatically sent by the APM tracer middleware on use according to your
+// blocking configuration.
+// Use errors.As to check whether an error is of this type.
+type BlockedFileOpenError = sharedsec.BlockedFileOpenError
+
+// MonitorFileOpen runs the security monitoring rules on the given file path
+// the request handler is about to open, and returns an error when the file
+// must not be opened. The given context must be the HTTP or gRPC request
+// context in order to check the path against the request's parameters, and
+// the attack is reported on the request's service entry span. Calls to this
+// function are ignored if AppSec is disabled or the given context is not a
+// monitored request context.
+// This function always returns nil when appsec is disabled.
+func MonitorFileOpen(ctx context.Context, path string) error {
+	if !appsec.Enabled() {
+		appsecDisabledLog.Do(func() { log.Warn("appsec: not enabled. File opening checks won't be performed.") })
+		return nil
+	}
+	return sharedsec.MonitorFileOpen(ctx, path)
+}
+
+// OpenFile is os.OpenFile protected against path traversals and Local File
+// Inclusions (LFI) with MonitorFileOpen. The path of the file is checked before
+// opening it, and a *os.PathError wrapping a *BlockedFileOpenError is returned
+// when it must not be opened.
+func OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (*os.File, error) {
+	if err := MonitorFileOpen(ctx, name); err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	return os.OpenFile(name, flag, perm)
+}
+
+// Open is os.Open protected against path traversals and Local File Inclusions
+// (LFI) with MonitorFileOpen. Cf. OpenFile for more details.
+func Open(ctx context.Context, name string) (*os.File, error) {
+	return OpenFile(ctx, name, os.O_RDONLY, 0)
+}
@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/appsec"
+	httptrace "github.com/nowfred/dd-trace-go/contrib/net/http"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	privateAppsec "github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0o600))
+
+	// AppSec is disabled so the file is opened without any check
+	f, err := appsec.Open(context.Background(), path)
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+
+	_, err = appsec.OpenFile(context.Background(), filepath.Join(t.TempDir(), "missing.txt"), os.O_RDONLY, 0)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestOpenFileBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../internal/appsec/testdata/lfi.json")
+	privateAppsec.Start()
+	defer privateAppsec.Stop()
+	if !privateAppsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	var openErr error
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var f *os.File
+		if f, openErr = appsec.Open(r.Context(), "/var/www/"+r.URL.Query().Get("file")); openErr == nil {
+			f.Close()
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name    string
+		file    string
+		blocked bool
+	}{
+		{name: "block", file: "../../etc/passwd", blocked: true},
+		{name: "no-block", file: "index.html"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			res, err := http.Get(srv.URL + "/?file=" + url.QueryEscape(tc.file))
+			require.NoError(t, err)
+			res.Body.Close()
+
+			var blockedErr *appsec.BlockedFileOpenError
+			require.Equal(t, tc.blocked, errors.As(openErr, &blockedErr))
+			if !tc.blocked {
+				return
+			}
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			event, _ := spans[0].Tag("_dd.appsec.json").(string)
+			require.True(t, strings.Contains(event, "lfi-001-001"))
+		})
+	}
+}
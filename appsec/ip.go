@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"time"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+)
+
+// DenyIP blocks the requests of the given client IP address, or CIDR range
+// such as 192.168.0.0/16, for the given duration. The entry never expires when
+// ttl is zero. The denied IP addresses are blocked by the security rules along
+// with the ones denied through remote configuration, so that abusive clients
+// can be stopped without agent connectivity.
+// The local IP lists are kept in memory and applied whenever appsec is
+// enabled. An error is returned when the IP address or the ttl is invalid.
+func DenyIP(ip string, ttl time.Duration) error {
+	return appsec.DenyIP(ip, ttl)
+}
+
+// AllowIP never blocks the requests of the given client IP address, or CIDR
+// range such as 192.168.0.0/16, by IP address for the given duration, even if
+// denied through remote configuration, nor rate limits them. The entry never
+// expires when ttl is zero.
+// The local IP lists are kept in memory and applied whenever appsec is
+// enabled. An error is returned when the IP address or the ttl is invalid.
+func AllowIP(ip string, ttl time.Duration) error {
+	return appsec.AllowIP(ip, ttl)
+}
+
+// RemoveIP removes the given client IP address, or CIDR range, previously
+// added with DenyIP or AllowIP.
+func RemoveIP(ip string) error {
+	return appsec.RemoveIP(ip)
+}
@@ -7,6 +7,7 @@ package gqlgen
 
 import (
 	"math"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -15,8 +16,10 @@ import (
 const defaultServiceName = "graphql"
 
 type config struct {
-	serviceName   string
-	analyticsRate float64
+	serviceName      string
+	analyticsRate    float64
+	summarizeTrivial bool
+	trivialThreshold time.Duration
 }
 
 // An Option configures the gqlgen integration.
@@ -48,3 +51,16 @@ func WithServiceName(name string) Option {
 		t.serviceName = name
 	}
 }
+
+// WithTrivialResolversSummary aggregates the trivial resolvers, resolving
+// fields without a method nor a user-specified resolver, faster than the given
+// threshold into a single graphql.resolvers.trivial span per operation holding
+// their count and total duration. The trivial resolvers as slow as the
+// threshold, or failing, are still traced with their own span. This reduces
+// the number of spans of wide queries while keeping slow resolvers visible.
+func WithTrivialResolversSummary(threshold time.Duration) Option {
+	return func(t *config) {
+		t.summarizeTrivial = true
+		t.trivialThreshold = threshold
+	}
+}
@@ -46,6 +46,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -80,6 +81,9 @@ type gqlTracer struct {
 	cfg *config
 }
 
+// trivialResolversKey is the context key of the summary of the trivial resolvers of the operation.
+type trivialResolversKey struct{}
+
 // NewTracer creates a graphql.HandlerExtension instance that can be used with
 // a graphql.handler.Server.
 // Options can be passed in for further configuration.
@@ -103,6 +107,20 @@ func (t *gqlTracer) Validate(_ graphql.ExecutableSchema) error {
 func (t *gqlTracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
 	opCtx := graphql.GetOperationContext(ctx)
 	span, ctx := t.createRootSpan(ctx, opCtx)
+	var trivial *graphqltrace.TrivialResolvers
+	if span != nil {
+		if opCtx.Operation != nil {
+			var fragments ast.FragmentDefinitionList
+			if opCtx.Doc != nil {
+				fragments = opCtx.Doc.Fragments
+			}
+			graphqltrace.OperationQueryMetrics(opCtx.Operation, fragments, opCtx.Variables).SetTags(span)
+		}
+		if t.cfg.summarizeTrivial {
+			trivial = graphqltrace.NewTrivialResolvers(t.cfg.trivialThreshold)
+			ctx = context.WithValue(ctx, trivialResolversKey{}, trivial)
+		}
+	}
 	ctx, req := graphqlsec.StartRequestOperation(ctx, nil /* root */, span, graphqlsec.RequestOperationArgs{
 		RawQuery:      opCtx.RawQuery,
 		OperationName: opCtx.OperationName,
@@ -120,8 +138,18 @@ func (t *gqlTracer) InterceptOperation(ctx context.Context, next graphql.Operati
 			var err error
 			if len(response.Errors) > 0 {
 				err = response.Errors
+				paths := make([][]any, len(response.Errors))
+				for i, e := range response.Errors {
+					paths[i] = errorPath(e.Path)
+				}
+				graphqltrace.SetErrorTags(span, paths)
 			}
 			defer span.Finish(tracer.WithError(err))
+			// The handler runs once per response of subscriptions and deferred payloads. Finish resets the
+			// summary, so that every response only reports its own trivial resolvers.
+			if trivial != nil {
+				trivial.Finish(span, tracer.Tag(ext.Component, componentName), tracer.ResourceName(graphqltrace.TrivialResolversSpanName))
+			}
 		}
 		query.Finish(graphqlsec.ExecutionOperationRes{
 			Data:  response.Data, // NB - This is raw data, but rather not parse it (possibly expensive).
@@ -138,29 +166,60 @@ func (t *gqlTracer) InterceptOperation(ctx context.Context, next graphql.Operati
 func (t *gqlTracer) InterceptField(ctx context.Context, next graphql.Resolver) (res any, err error) {
 	opCtx := graphql.GetOperationContext(ctx)
 	fieldCtx := graphql.GetFieldContext(ctx)
-	opts := []tracer.StartSpanOption{
-		tracer.Tag(tagGraphqlField, fieldCtx.Field.Name),
-		tracer.Tag(tagGraphqlOperationType, opCtx.Operation.Operation),
-		tracer.Tag(ext.Component, componentName),
-		tracer.ResourceName(fmt.Sprintf("%s.%s", fieldCtx.Object, fieldCtx.Field.Name)),
-		tracer.Measured(),
-	}
-	if !math.IsNaN(t.cfg.analyticsRate) {
-		opts = append(opts, tracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
+	isTrivial := !(fieldCtx.IsMethod || fieldCtx.IsResolver) // TODO: Is this accurate?
+	if trivial, ok := ctx.Value(trivialResolversKey{}).(*graphqltrace.TrivialResolvers); ok && isTrivial {
+		return t.interceptTrivialField(ctx, trivial, next, opCtx, fieldCtx)
 	}
-	span, ctx := tracer.StartSpanFromContext(ctx, fieldOp, opts...)
+	span, ctx := tracer.StartSpanFromContext(ctx, fieldOp, t.fieldSpanOptions(opCtx, fieldCtx)...)
 	defer func() { span.Finish(tracer.WithError(err)) }()
 	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), span, graphqlsec.ResolveOperationArgs{
 		Arguments: fieldCtx.Args,
 		TypeName:  fieldCtx.Object,
 		FieldName: fieldCtx.Field.Name,
-		Trivial:   !(fieldCtx.IsMethod || fieldCtx.IsResolver), // TODO: Is this accurate?
+		Trivial:   isTrivial,
 	})
 	defer func() { op.Finish(graphqlsec.ResolveOperationRes{Data: res, Error: err}) }()
 	res, err = next(ctx)
 	return
 }
 
+// interceptTrivialField resolves the trivial field without creating its span upfront. It is only traced with its own
+// span when it is slower than the threshold or fails, and summarized otherwise.
+func (t *gqlTracer) interceptTrivialField(ctx context.Context, trivial *graphqltrace.TrivialResolvers, next graphql.Resolver, opCtx *graphql.OperationContext, fieldCtx *graphql.FieldContext) (res any, err error) {
+	parent, _ := tracer.SpanFromContext(ctx)
+	start := time.Now()
+	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), parent, graphqlsec.ResolveOperationArgs{
+		Arguments: fieldCtx.Args,
+		TypeName:  fieldCtx.Object,
+		FieldName: fieldCtx.Field.Name,
+		Trivial:   true,
+	})
+	res, err = next(ctx)
+	op.Finish(graphqlsec.ResolveOperationRes{Data: res, Error: err})
+	finish := time.Now()
+	if err == nil && trivial.Add(start, finish) {
+		return res, err
+	}
+	opts := append(t.fieldSpanOptions(opCtx, fieldCtx), tracer.StartTime(start))
+	span, _ := tracer.StartSpanFromContext(ctx, fieldOp, opts...)
+	span.Finish(tracer.FinishTime(finish), tracer.WithError(err))
+	return res, err
+}
+
+func (t *gqlTracer) fieldSpanOptions(opCtx *graphql.OperationContext, fieldCtx *graphql.FieldContext) []tracer.StartSpanOption {
+	opts := []tracer.StartSpanOption{
+		tracer.Tag(tagGraphqlField, fieldCtx.Field.Name),
+		tracer.Tag(tagGraphqlOperationType, opCtx.Operation.Operation),
+		tracer.Tag(ext.Component, componentName),
+		tracer.ResourceName(fmt.Sprintf("%s.%s", fieldCtx.Object, fieldCtx.Field.Name)),
+		tracer.Measured(),
+	}
+	if !math.IsNaN(t.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
+	}
+	return opts
+}
+
 func (*gqlTracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
 	return next(ctx)
 }
@@ -208,6 +267,20 @@ func (t *gqlTracer) createRootSpan(ctx context.Context, opCtx *graphql.Operation
 	return rootSpan, ctx
 }
 
+// errorPath returns the elements of the given error path.
+func errorPath(path ast.Path) []any {
+	elems := make([]any, len(path))
+	for i, e := range path {
+		switch e := e.(type) {
+		case ast.PathName:
+			elems[i] = string(e)
+		case ast.PathIndex:
+			elems[i] = int(e)
+		}
+	}
+	return elems
+}
+
 func serverSpanName(octx *graphql.OperationContext) string {
 	nameV0 := "graphql.request"
 	if octx != nil && octx.Operation != nil {
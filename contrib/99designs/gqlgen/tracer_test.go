@@ -7,7 +7,9 @@ package gqlgen
 
 import (
 	"testing"
+	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/contrib/internal/lists"
 	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
@@ -39,6 +41,9 @@ func TestOptions(t *testing.T) {
 				assert.Equal(ext.SpanTypeGraphQL, root.Tag(ext.SpanType))
 				assert.Equal("99designs/gqlgen", root.Tag(ext.Component))
 				assert.Nil(root.Tag(ext.EventSampleRate))
+				assert.Equal(1, root.Tag(graphqltrace.MetricQueryDepth))
+				assert.Equal(1, root.Tag(graphqltrace.MetricQueryFields))
+				assert.Equal(1, root.Tag(graphqltrace.MetricQueryComplexity))
 			},
 		},
 		"WithServiceName": {
@@ -100,6 +105,7 @@ func TestError(t *testing.T) {
 	}
 	assert.NotNil(root)
 	assert.NotNil(root.Tag(ext.Error))
+	assert.Equal(1, root.Tag(graphqltrace.MetricErrorsCount))
 }
 
 func TestObfuscation(t *testing.T) {
@@ -149,6 +155,52 @@ func TestChildSpans(t *testing.T) {
 	assert.Nil(root.Tag(ext.Error))
 }
 
+func TestTrivialResolversSummary(t *testing.T) {
+	for name, tt := range map[string]struct {
+		threshold time.Duration
+		opNames   []string
+	}{
+		"summarized": {
+			threshold: time.Hour,
+			opNames:   []string{readOp, parsingOp, validationOp, graphqltrace.TrivialResolversSpanName, "graphql.query"},
+		},
+		"slow": {
+			threshold: 0,
+			opNames:   []string{readOp, parsingOp, validationOp, fieldOp, "graphql.query"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			c := newTestClient(t, testserver.New(), NewTracer(WithTrivialResolversSummary(tt.threshold)))
+			require.NoError(t, c.Post(`{ name }`, &testServerResponse{}))
+
+			spans := mt.FinishedSpans()
+			var root mocktracer.Span
+			var opNames []string
+			for _, span := range spans {
+				if span.ParentID() == 0 {
+					root = span
+				}
+				opNames = append(opNames, span.OperationName())
+			}
+			require.NotNil(t, root)
+			assert.ElementsMatch(t, tt.opNames, opNames)
+			for _, span := range spans {
+				switch span.OperationName() {
+				case graphqltrace.TrivialResolversSpanName:
+					assert.Equal(t, root.SpanID(), span.ParentID())
+					assert.Equal(t, 1, span.Tag(graphqltrace.MetricTrivialResolversCount))
+					assert.Equal(t, "99designs/gqlgen", span.Tag(ext.Component))
+				case fieldOp:
+					assert.Equal(t, root.SpanID(), span.ParentID())
+					assert.Equal(t, "Query.name", span.Tag(ext.ResourceName))
+				}
+			}
+		})
+	}
+}
+
 func TestNamingSchema(t *testing.T) {
 	genSpans := namingschematest.GenSpansFn(func(t *testing.T, serviceOverride string) []mocktracer.Span {
 		var opts []Option
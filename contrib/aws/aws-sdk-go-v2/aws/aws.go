@@ -104,12 +104,18 @@ func (mw *traceMiddleware) startTraceMiddleware(stack *middleware.Stack) error {
 			opts = append(opts, tracer.Tag(ext.EventSampleRate, mw.cfg.analyticsRate))
 		}
 		span, spanctx := tracer.StartSpanFromContext(ctx, spanName(serviceID, operation), opts...)
+		if mw.cfg.propagation || mw.cfg.dataStreamsEnabled {
+			mw.injectContext(spanctx, span, &in)
+		}
 
 		// Handle initialize and continue through the middleware chain.
 		out, metadata, err = next.HandleInitialize(spanctx, in)
 		if err != nil && (mw.cfg.errCheck == nil || mw.cfg.errCheck(err)) {
 			span.SetTag(ext.Error, err)
 		}
+		if err == nil && mw.cfg.dataStreamsEnabled {
+			setConsumeCheckpoints(in, out)
+		}
 		span.Finish()
 
 		return out, metadata, err
@@ -15,6 +15,11 @@ type config struct {
 	serviceName   string
 	analyticsRate float64
 	errCheck      func(err error) bool
+	// dataStreamsEnabled enables Data Streams monitoring for SQS, SNS and Kinesis.
+	dataStreamsEnabled bool
+	// propagation enables the injection of the trace context into SQS and SNS
+	// messages and EventBridge events.
+	propagation bool
 }
 
 // Option represents an option that can be passed to Dial.
@@ -26,6 +31,7 @@ func defaults(cfg *config) {
 	} else {
 		cfg.analyticsRate = math.NaN()
 	}
+	cfg.dataStreamsEnabled = internal.BoolEnv("DD_DATA_STREAMS_ENABLED", false)
 }
 
 // WithServiceName sets the given service name for the dialled connection.
@@ -68,3 +74,24 @@ func WithErrorCheck(fn func(err error) bool) Option {
 		cfg.errCheck = fn
 	}
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() Option {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
+
+// WithPropagation enables the injection of the trace context into the messages
+// sent with SQS SendMessage and SendMessageBatch, SNS Publish and PublishBatch,
+// and into the detail of the events sent with EventBridge PutEvents, so that
+// consumers can continue the trace. The context is stored in the "_datadog"
+// message attribute, which is not added to messages already holding the
+// maximum of 10 attributes, or in the "_datadog" field of the event detail.
+// See ExtractSpanContextFromSQSMessage and ExtractSpanContextFromSNSNotification
+// to retrieve it.
+func WithPropagation() Option {
+	return func(cfg *config) {
+		cfg.propagation = true
+	}
+}
@@ -0,0 +1,318 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/contrib/aws/internal/propagation"
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// maxEventSize is the maximum size of an EventBridge event entry.
+const maxEventSize = 256 * 1024
+
+// ExtractSpanContextFromSQSMessage retrieves the span context propagated with
+// msg, either as a message attribute or, when msg was delivered by an SNS
+// subscription, inside the SNS notification it holds. The message must have
+// been received with the "_datadog" message attribute requested, which is done
+// automatically by the middleware when WithPropagation or WithDataStreams is
+// used.
+func ExtractSpanContextFromSQSMessage(msg sqstypes.Message) (ddtrace.SpanContext, error) {
+	c, ok := sqsExtractCarrier(msg)
+	if !ok {
+		return nil, tracer.ErrSpanContextNotFound
+	}
+	return tracer.Extract(c)
+}
+
+// ExtractSpanContextFromSNSNotification retrieves the span context propagated
+// with an SNS notification, such as the JSON payload delivered to an HTTP
+// endpoint or the body of an SQS message received without raw message delivery.
+func ExtractSpanContextFromSNSNotification(notification string) (ddtrace.SpanContext, error) {
+	c, ok := propagation.ExtractFromSNSNotification(notification)
+	if !ok {
+		return nil, tracer.ErrSpanContextNotFound
+	}
+	return tracer.Extract(c)
+}
+
+// injectContext propagates the span context of span and, when enabled, the
+// Data Streams pathway with every message sent by the request. The input is
+// copied before being modified so that the caller's values are left untouched.
+func (mw *traceMiddleware) injectContext(ctx context.Context, span ddtrace.Span, in *middleware.InitializeInput) {
+	switch params := in.Parameters.(type) {
+	case *sqs.SendMessageInput:
+		p := *params
+		queue := sqsQueueName(p.QueueUrl)
+		c := mw.newCarrier(ctx, span, "sqs", queue, sqsMessageSize(aws.ToString(p.MessageBody), p.MessageAttributes))
+		p.MessageAttributes = sqsAttributesWithCarrier(p.MessageAttributes, c)
+		in.Parameters = &p
+	case *sqs.SendMessageBatchInput:
+		p := *params
+		queue := sqsQueueName(p.QueueUrl)
+		p.Entries = make([]sqstypes.SendMessageBatchRequestEntry, len(params.Entries))
+		for i, e := range params.Entries {
+			c := mw.newCarrier(ctx, span, "sqs", queue, sqsMessageSize(aws.ToString(e.MessageBody), e.MessageAttributes))
+			e.MessageAttributes = sqsAttributesWithCarrier(e.MessageAttributes, c)
+			p.Entries[i] = e
+		}
+		in.Parameters = &p
+	case *sns.PublishInput:
+		p := *params
+		topic := snsTopicName(p.TopicArn)
+		if topic == "" {
+			topic = snsTopicName(p.TargetArn)
+		}
+		c := mw.newCarrier(ctx, span, "sns", topic, snsMessageSize(aws.ToString(p.Message), p.MessageAttributes))
+		p.MessageAttributes = snsAttributesWithCarrier(p.MessageAttributes, c)
+		in.Parameters = &p
+	case *sns.PublishBatchInput:
+		p := *params
+		topic := snsTopicName(p.TopicArn)
+		p.PublishBatchRequestEntries = make([]snstypes.PublishBatchRequestEntry, len(params.PublishBatchRequestEntries))
+		for i, e := range params.PublishBatchRequestEntries {
+			c := mw.newCarrier(ctx, span, "sns", topic, snsMessageSize(aws.ToString(e.Message), e.MessageAttributes))
+			e.MessageAttributes = snsAttributesWithCarrier(e.MessageAttributes, c)
+			p.PublishBatchRequestEntries[i] = e
+		}
+		in.Parameters = &p
+	case *eventbridge.PutEventsInput:
+		if !mw.cfg.propagation {
+			return
+		}
+		p := *params
+		p.Entries = make([]eventbridgetypes.PutEventsRequestEntry, len(params.Entries))
+		for i, e := range params.Entries {
+			e.Detail = eventDetailWithContext(e.Detail, span)
+			p.Entries[i] = e
+		}
+		in.Parameters = &p
+	case *kinesis.PutRecordInput:
+		if !mw.cfg.dataStreamsEnabled {
+			return
+		}
+		p := *params
+		p.Data = kinesisDataWithPathway(ctx, p.Data, kinesisStreamName(p.StreamName, p.StreamARN), aws.ToString(p.PartitionKey))
+		in.Parameters = &p
+	case *kinesis.PutRecordsInput:
+		if !mw.cfg.dataStreamsEnabled {
+			return
+		}
+		p := *params
+		stream := kinesisStreamName(p.StreamName, p.StreamARN)
+		p.Records = make([]kinesistypes.PutRecordsRequestEntry, len(params.Records))
+		for i, r := range params.Records {
+			r.Data = kinesisDataWithPathway(ctx, r.Data, stream, aws.ToString(r.PartitionKey))
+			p.Records[i] = r
+		}
+		in.Parameters = &p
+	case *sqs.ReceiveMessageInput:
+		// The _datadog attribute is only returned when explicitly requested.
+		for _, name := range params.MessageAttributeNames {
+			if name == "All" || name == ".*" || name == propagation.AttributeName {
+				return
+			}
+		}
+		p := *params
+		p.MessageAttributeNames = append(append([]string(nil), params.MessageAttributeNames...), propagation.AttributeName)
+		in.Parameters = &p
+	}
+}
+
+// newCarrier returns a carrier holding the span context of span when
+// propagation is enabled, and the pathway resulting from a produce checkpoint
+// when Data Streams is enabled.
+func (mw *traceMiddleware) newCarrier(ctx context.Context, span ddtrace.Span, typ, topic string, payloadSize int64) tracer.TextMapCarrier {
+	c := tracer.TextMapCarrier{}
+	if mw.cfg.propagation {
+		if err := tracer.Inject(span.Context(), c); err != nil {
+			log.Debug("contrib/aws/aws-sdk-go-v2/aws: Failed to inject span context: %v", err)
+		}
+	}
+	if mw.cfg.dataStreamsEnabled {
+		propagation.SetProduceCheckpoint(ctx, c, typ, topic, payloadSize)
+	}
+	return c
+}
+
+// setConsumeCheckpoints sets a consume checkpoint for every message received
+// by the request described by in and out.
+func setConsumeCheckpoints(in middleware.InitializeInput, out middleware.InitializeOutput) {
+	switch res := out.Result.(type) {
+	case *sqs.ReceiveMessageOutput:
+		params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+		if !ok {
+			return
+		}
+		queue := sqsQueueName(params.QueueUrl)
+		for _, msg := range res.Messages {
+			c, _ := sqsExtractCarrier(msg)
+			propagation.SetConsumeCheckpoint(c, "sqs", queue, sqsMessageSize(aws.ToString(msg.Body), msg.MessageAttributes))
+		}
+	case *kinesis.GetRecordsOutput:
+		params, ok := in.Parameters.(*kinesis.GetRecordsInput)
+		if !ok {
+			return
+		}
+		stream := kinesisStreamName(nil, params.StreamARN)
+		for _, r := range res.Records {
+			c, _ := propagation.ExtractFromJSON(r.Data)
+			propagation.SetConsumeCheckpoint(c, "kinesis", stream, int64(len(r.Data)))
+		}
+	}
+}
+
+// sqsExtractCarrier returns the carrier propagated with msg, either as a
+// message attribute or inside the SNS notification wrapping it.
+func sqsExtractCarrier(msg sqstypes.Message) (tracer.TextMapCarrier, bool) {
+	if attr, ok := msg.MessageAttributes[propagation.AttributeName]; ok {
+		b := attr.BinaryValue
+		if attr.StringValue != nil {
+			b = []byte(*attr.StringValue)
+		}
+		c, err := propagation.Decode(b)
+		if err != nil {
+			log.Debug("contrib/aws/aws-sdk-go-v2/aws: Failed to decode %s message attribute: %v", propagation.AttributeName, err)
+			return nil, false
+		}
+		return c, true
+	}
+	return propagation.ExtractFromSNSNotification(aws.ToString(msg.Body))
+}
+
+func sqsAttributesWithCarrier(attrs map[string]sqstypes.MessageAttributeValue, c tracer.TextMapCarrier) map[string]sqstypes.MessageAttributeValue {
+	if len(c) == 0 {
+		return attrs
+	}
+	if len(attrs) >= propagation.MaxMessageAttributes {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Cannot propagate context, message already has %d attributes", len(attrs))
+		return attrs
+	}
+	v, err := propagation.Encode(c)
+	if err != nil {
+		return attrs
+	}
+	out := make(map[string]sqstypes.MessageAttributeValue, len(attrs)+1)
+	for k, a := range attrs {
+		out[k] = a
+	}
+	out[propagation.AttributeName] = sqstypes.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(string(v)),
+	}
+	return out
+}
+
+func snsAttributesWithCarrier(attrs map[string]snstypes.MessageAttributeValue, c tracer.TextMapCarrier) map[string]snstypes.MessageAttributeValue {
+	if len(c) == 0 {
+		return attrs
+	}
+	if len(attrs) >= propagation.MaxMessageAttributes {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Cannot propagate context, message already has %d attributes", len(attrs))
+		return attrs
+	}
+	v, err := propagation.Encode(c)
+	if err != nil {
+		return attrs
+	}
+	out := make(map[string]snstypes.MessageAttributeValue, len(attrs)+1)
+	for k, a := range attrs {
+		out[k] = a
+	}
+	// A binary attribute is used so that the value can be decoded the same way
+	// whether it reaches SQS wrapped in a notification or as a raw message.
+	out[propagation.AttributeName] = snstypes.MessageAttributeValue{
+		DataType:    aws.String("Binary"),
+		BinaryValue: v,
+	}
+	return out
+}
+
+// eventDetailWithContext returns detail with the span context of span set
+// under its _datadog field.
+func eventDetailWithContext(detail *string, span ddtrace.Span) *string {
+	if detail == nil {
+		return detail
+	}
+	c := tracer.TextMapCarrier{}
+	if err := tracer.Inject(span.Context(), c); err != nil {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Failed to inject span context: %v", err)
+		return detail
+	}
+	out, ok := propagation.InjectIntoJSON([]byte(*detail), c)
+	if !ok {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Cannot propagate context, event detail is not a JSON object")
+		return detail
+	}
+	if len(out) > maxEventSize {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Cannot propagate context, event detail would exceed %d bytes", maxEventSize)
+		return detail
+	}
+	return aws.String(string(out))
+}
+
+func kinesisDataWithPathway(ctx context.Context, data []byte, stream, partitionKey string) []byte {
+	if !propagation.CanInjectPathwayIntoJSON(data, propagation.MaxKinesisRecordSize-len(partitionKey)) {
+		log.Debug("contrib/aws/aws-sdk-go-v2/aws: Cannot propagate pathway, record data is not a JSON object or is too large")
+		return data
+	}
+	c := tracer.TextMapCarrier{}
+	propagation.SetProduceCheckpoint(ctx, c, "kinesis", stream, int64(len(data)))
+	if len(c) == 0 {
+		return data
+	}
+	out, _ := propagation.InjectIntoJSON(data, c)
+	return out
+}
+
+func sqsMessageSize(body string, attrs map[string]sqstypes.MessageAttributeValue) int64 {
+	size := int64(len(body))
+	for k, a := range attrs {
+		size += int64(len(k) + len(aws.ToString(a.StringValue)) + len(a.BinaryValue))
+	}
+	return size
+}
+
+func snsMessageSize(body string, attrs map[string]snstypes.MessageAttributeValue) int64 {
+	size := int64(len(body))
+	for k, a := range attrs {
+		size += int64(len(k) + len(aws.ToString(a.StringValue)) + len(a.BinaryValue))
+	}
+	return size
+}
+
+func sqsQueueName(queueURL *string) string {
+	parts := strings.Split(aws.ToString(queueURL), "/")
+	return parts[len(parts)-1]
+}
+
+func snsTopicName(arn *string) string {
+	parts := strings.Split(aws.ToString(arn), ":")
+	return parts[len(parts)-1]
+}
+
+func kinesisStreamName(name, arn *string) string {
+	if name != nil {
+		return *name
+	}
+	parts := strings.Split(aws.ToString(arn), "/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,311 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/contrib/aws/internal/propagation"
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingConfig returns a configuration sending requests to a mock server
+// which records the last request body.
+func newRecordingConfig(t *testing.T, body *[]byte, opts ...Option) aws.Config {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*body, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Amz-RequestId", "test_req")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	awsCfg := aws.Config{
+		Region:      "eu-west-1",
+		Credentials: aws.AnonymousCredentials{},
+		EndpointResolver: aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+			return aws.Endpoint{
+				PartitionID:   "aws",
+				URL:           server.URL,
+				SigningRegion: "eu-west-1",
+			}, nil
+		}),
+	}
+	AppendMiddleware(&awsCfg, opts...)
+	return awsCfg
+}
+
+func assertPathway(t *testing.T, c tracer.TextMapCarrier, edges ...string) {
+	t.Helper()
+	p, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), c))
+	require.True(t, ok)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), edges...)
+	expected, _ := datastreams.PathwayFromContext(ctx)
+	assert.NotEqual(t, uint64(0), expected.GetHash())
+	assert.Equal(t, expected.GetHash(), p.GetHash())
+}
+
+func TestDataStreamsSQSSendMessage(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := sqs.NewFromConfig(newRecordingConfig(t, &body, WithDataStreams()))
+	input := &sqs.SendMessageInput{
+		MessageBody: aws.String("foobar"),
+		QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	}
+	client.SendMessage(context.Background(), input)
+	assert.Nil(t, input.MessageAttributes, "caller input must not be modified")
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, propagation.AttributeName, form.Get("MessageAttribute.1.Name"))
+	assert.Equal(t, "String", form.Get("MessageAttribute.1.Value.DataType"))
+	c, err := propagation.Decode([]byte(form.Get("MessageAttribute.1.Value.StringValue")))
+	require.NoError(t, err)
+	assertPathway(t, c, "direction:out", "topic:MyQueueName", "type:sqs")
+}
+
+func TestDataStreamsSQSReceiveMessage(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := sqs.NewFromConfig(newRecordingConfig(t, &body, WithDataStreams()))
+	client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+		MessageAttributeNames: []string{"custom"},
+	})
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, "custom", form.Get("MessageAttributeName.1"))
+	assert.Equal(t, propagation.AttributeName, form.Get("MessageAttributeName.2"))
+}
+
+func TestDataStreamsKinesisPutRecord(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := kinesis.NewFromConfig(newRecordingConfig(t, &body, WithDataStreams()))
+	client.PutRecord(context.Background(), &kinesis.PutRecordInput{
+		StreamName:   aws.String("my-kinesis-stream"),
+		Data:         []byte(`{"hello":"world"}`),
+		PartitionKey: aws.String("1234"),
+	})
+
+	var req struct{ Data []byte }
+	require.NoError(t, json.Unmarshal(body, &req))
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(req.Data, &data))
+	assert.Equal(t, "world", data["hello"])
+	c, ok := propagation.ExtractFromJSON(req.Data)
+	require.True(t, ok)
+	assertPathway(t, c, "direction:out", "topic:my-kinesis-stream", "type:kinesis")
+}
+
+func TestPropagationSQSSendMessage(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := sqs.NewFromConfig(newRecordingConfig(t, &body, WithPropagation()))
+	client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		MessageBody: aws.String("foobar"),
+		QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	})
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	require.Equal(t, propagation.AttributeName, form.Get("MessageAttribute.1.Name"))
+	msg := types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+		propagation.AttributeName: {
+			DataType:    aws.String(form.Get("MessageAttribute.1.Value.DataType")),
+			StringValue: aws.String(form.Get("MessageAttribute.1.Value.StringValue")),
+		},
+	}}
+	spanctx, err := ExtractSpanContextFromSQSMessage(msg)
+	require.NoError(t, err)
+	assert.Equal(t, spans[0].TraceID(), spanctx.TraceID())
+	assert.Equal(t, spans[0].SpanID(), spanctx.SpanID())
+
+	t.Run("disabled", func(t *testing.T) {
+		client := sqs.NewFromConfig(newRecordingConfig(t, &body))
+		client.SendMessage(context.Background(), &sqs.SendMessageInput{
+			MessageBody: aws.String("foobar"),
+			QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+		})
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.Empty(t, form.Get("MessageAttribute.1.Name"))
+	})
+
+	t.Run("too many attributes", func(t *testing.T) {
+		attrs := make(map[string]types.MessageAttributeValue)
+		for i := 0; i < propagation.MaxMessageAttributes; i++ {
+			attrs[string(rune('a'+i))] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("v")}
+		}
+		client.SendMessage(context.Background(), &sqs.SendMessageInput{
+			MessageBody:       aws.String("foobar"),
+			MessageAttributes: attrs,
+			QueueUrl:          aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+		})
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.NotContains(t, form, "MessageAttribute.11.Name")
+		for k, v := range form {
+			assert.NotContains(t, v, propagation.AttributeName, k)
+		}
+	})
+}
+
+func TestPropagationSNSPublish(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := sns.NewFromConfig(newRecordingConfig(t, &body, WithPropagation()))
+	client.Publish(context.Background(), &sns.PublishInput{
+		Message:  aws.String("foobar"),
+		TopicArn: aws.String("arn:aws:sns:us-west-2:123456789012:MyTopicName"),
+	})
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	require.Equal(t, propagation.AttributeName, form.Get("MessageAttributes.entry.1.Name"))
+	assert.Equal(t, "Binary", form.Get("MessageAttributes.entry.1.Value.DataType"))
+
+	// build the notification delivered to subscribers
+	notification, err := json.Marshal(map[string]interface{}{
+		"Type":    "Notification",
+		"Message": "foobar",
+		"MessageAttributes": map[string]interface{}{
+			propagation.AttributeName: map[string]string{
+				"Type":  "Binary",
+				"Value": form.Get("MessageAttributes.entry.1.Value.BinaryValue"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	spanctx, err := ExtractSpanContextFromSNSNotification(string(notification))
+	require.NoError(t, err)
+	assert.Equal(t, spans[0].SpanID(), spanctx.SpanID())
+
+	// the same notification wrapped in an SQS message
+	spanctx, err = ExtractSpanContextFromSQSMessage(types.Message{Body: aws.String(string(notification))})
+	require.NoError(t, err)
+	assert.Equal(t, spans[0].SpanID(), spanctx.SpanID())
+
+	// the message received by SQS with raw message delivery
+	v, err := base64.StdEncoding.DecodeString(form.Get("MessageAttributes.entry.1.Value.BinaryValue"))
+	require.NoError(t, err)
+	spanctx, err = ExtractSpanContextFromSQSMessage(types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+		propagation.AttributeName: {DataType: aws.String("Binary"), BinaryValue: v},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, spans[0].SpanID(), spanctx.SpanID())
+}
+
+func TestPropagationEventBridgePutEvents(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	client := eventbridge.NewFromConfig(newRecordingConfig(t, &body, WithPropagation()))
+	input := &eventbridge.PutEventsInput{
+		Entries: []eventbridgetypes.PutEventsRequestEntry{
+			{Detail: aws.String(`{"hello":"world"}`), DetailType: aws.String("test"), Source: aws.String("test")},
+			{Detail: aws.String(`not json`), DetailType: aws.String("test"), Source: aws.String("test")},
+		},
+	}
+	client.PutEvents(context.Background(), input)
+	assert.Equal(t, `{"hello":"world"}`, *input.Entries[0].Detail, "caller input must not be modified")
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+
+	var req struct {
+		Entries []struct{ Detail string }
+	}
+	require.NoError(t, json.Unmarshal(body, &req))
+	require.Len(t, req.Entries, 2)
+	c, ok := propagation.ExtractFromJSON([]byte(req.Entries[0].Detail))
+	require.True(t, ok)
+	spanctx, err := tracer.Extract(c)
+	require.NoError(t, err)
+	assert.Equal(t, spans[0].SpanID(), spanctx.SpanID())
+	assert.Equal(t, `not json`, req.Entries[1].Detail)
+}
+
+func TestExtractSpanContextNotFound(t *testing.T) {
+	_, err := ExtractSpanContextFromSQSMessage(types.Message{Body: aws.String("hello")})
+	assert.Equal(t, tracer.ErrSpanContextNotFound, err)
+	_, err = ExtractSpanContextFromSNSNotification(`{"Type":"Notification","Message":"hello"}`)
+	assert.Equal(t, tracer.ErrSpanContextNotFound, err)
+}
+
+func TestSQSExtractCarrier(t *testing.T) {
+	want := tracer.TextMapCarrier{"dd-pathway-ctx-base64": "abc"}
+	v, err := propagation.Encode(want)
+	require.NoError(t, err)
+
+	t.Run("string", func(t *testing.T) {
+		c, ok := sqsExtractCarrier(types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+			propagation.AttributeName: {DataType: aws.String("String"), StringValue: aws.String(string(v))},
+		}})
+		require.True(t, ok)
+		assert.Equal(t, want, c)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		c, ok := sqsExtractCarrier(types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+			propagation.AttributeName: {DataType: aws.String("Binary"), BinaryValue: v},
+		}})
+		require.True(t, ok)
+		assert.Equal(t, want, c)
+	})
+
+	t.Run("sns", func(t *testing.T) {
+		body := `{"Type":"Notification","Message":"hello","MessageAttributes":{"_datadog":{"Type":"String","Value":` + string(mustMarshal(t, string(v))) + `}}}`
+		c, ok := sqsExtractCarrier(types.Message{Body: aws.String(body)})
+		require.True(t, ok)
+		assert.Equal(t, want, c)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		_, ok := sqsExtractCarrier(types.Message{Body: aws.String("hello")})
+		assert.False(t, ok)
+	})
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
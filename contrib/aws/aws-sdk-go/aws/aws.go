@@ -40,6 +40,8 @@ func init() {
 }
 
 const (
+	// BuildHandlerName is the name of the Datadog NamedHandler for the Build phase of an awsv1 request
+	BuildHandlerName = "github.com/nowfred/dd-trace-go/contrib/aws/aws-sdk-go/aws/handlers.Build"
 	// SendHandlerName is the name of the Datadog NamedHandler for the Send phase of an awsv1 request
 	SendHandlerName = "github.com/nowfred/dd-trace-go/contrib/aws/aws-sdk-go/aws/handlers.Send"
 	// CompleteHandlerName is the name of the Datadog NamedHandler for the Complete phase of an awsv1 request
@@ -60,6 +62,10 @@ func WrapSession(s *session.Session, opts ...Option) *session.Session {
 	log.Debug("contrib/aws/aws-sdk-go/aws: Wrapping Session: %#v", cfg)
 	h := &handlers{cfg: cfg}
 	s = s.Copy()
+	s.Handlers.Build.PushFrontNamed(request.NamedHandler{
+		Name: BuildHandlerName,
+		Fn:   h.Build,
+	})
 	s.Handlers.Send.PushFrontNamed(request.NamedHandler{
 		Name: SendHandlerName,
 		Fn:   h.Send,
@@ -71,6 +77,14 @@ func WrapSession(s *session.Session, opts ...Option) *session.Session {
 	return s
 }
 
+// Build runs before the request parameters are serialized, so that context
+// can be propagated with the messages being sent.
+func (h *handlers) Build(req *request.Request) {
+	if h.cfg.dataStreamsEnabled {
+		injectPathway(req)
+	}
+}
+
 func (h *handlers) Send(req *request.Request) {
 	if req.RetryCount != 0 {
 		return
@@ -118,6 +132,9 @@ func (h *handlers) Complete(req *request.Request) {
 	if req.Error != nil && (h.cfg.errCheck == nil || h.cfg.errCheck(req.Error)) {
 		span.SetTag(ext.Error, req.Error)
 	}
+	if req.Error == nil && h.cfg.dataStreamsEnabled {
+		setConsumeCheckpoints(req)
+	}
 	span.Finish()
 }
 
@@ -0,0 +1,241 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/contrib/aws/internal/propagation"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// injectPathway sets a produce checkpoint for every message sent by req and
+// propagates the resulting pathway with the message. The request parameters
+// are copied before being modified so that the caller's values are left
+// untouched.
+func injectPathway(req *request.Request) {
+	ctx := req.Context()
+	switch params := req.Params.(type) {
+	case *sqs.SendMessageInput:
+		p := *params
+		p.MessageAttributes = sqsAttributesWithPathway(ctx, p.MessageAttributes, sqsQueueName(p.QueueUrl), aws.StringValue(p.MessageBody))
+		req.Params = &p
+	case *sqs.SendMessageBatchInput:
+		p := *params
+		queue := sqsQueueName(p.QueueUrl)
+		p.Entries = make([]*sqs.SendMessageBatchRequestEntry, len(params.Entries))
+		for i, e := range params.Entries {
+			if e == nil {
+				continue
+			}
+			entry := *e
+			entry.MessageAttributes = sqsAttributesWithPathway(ctx, entry.MessageAttributes, queue, aws.StringValue(entry.MessageBody))
+			p.Entries[i] = &entry
+		}
+		req.Params = &p
+	case *sns.PublishInput:
+		p := *params
+		topic := snsTopicName(p.TopicArn)
+		if topic == "" {
+			topic = snsTopicName(p.TargetArn)
+		}
+		p.MessageAttributes = snsAttributesWithPathway(ctx, p.MessageAttributes, topic, aws.StringValue(p.Message))
+		req.Params = &p
+	case *sns.PublishBatchInput:
+		p := *params
+		topic := snsTopicName(p.TopicArn)
+		p.PublishBatchRequestEntries = make([]*sns.PublishBatchRequestEntry, len(params.PublishBatchRequestEntries))
+		for i, e := range params.PublishBatchRequestEntries {
+			if e == nil {
+				continue
+			}
+			entry := *e
+			entry.MessageAttributes = snsAttributesWithPathway(ctx, entry.MessageAttributes, topic, aws.StringValue(entry.Message))
+			p.PublishBatchRequestEntries[i] = &entry
+		}
+		req.Params = &p
+	case *kinesis.PutRecordInput:
+		p := *params
+		p.Data = kinesisDataWithPathway(ctx, p.Data, kinesisStreamName(p.StreamName, p.StreamARN), aws.StringValue(p.PartitionKey))
+		req.Params = &p
+	case *kinesis.PutRecordsInput:
+		p := *params
+		stream := kinesisStreamName(p.StreamName, p.StreamARN)
+		p.Records = make([]*kinesis.PutRecordsRequestEntry, len(params.Records))
+		for i, r := range params.Records {
+			if r == nil {
+				continue
+			}
+			record := *r
+			record.Data = kinesisDataWithPathway(ctx, record.Data, stream, aws.StringValue(record.PartitionKey))
+			p.Records[i] = &record
+		}
+		req.Params = &p
+	case *sqs.ReceiveMessageInput:
+		// The _datadog attribute is only returned when explicitly requested.
+		for _, name := range aws.StringValueSlice(params.MessageAttributeNames) {
+			if name == "All" || name == ".*" || name == propagation.AttributeName {
+				return
+			}
+		}
+		p := *params
+		p.MessageAttributeNames = append(append([]*string(nil), params.MessageAttributeNames...), aws.String(propagation.AttributeName))
+		req.Params = &p
+	}
+}
+
+// setConsumeCheckpoints sets a consume checkpoint for every message received
+// by req.
+func setConsumeCheckpoints(req *request.Request) {
+	switch res := req.Data.(type) {
+	case *sqs.ReceiveMessageOutput:
+		params, ok := req.Params.(*sqs.ReceiveMessageInput)
+		if !ok {
+			return
+		}
+		queue := sqsQueueName(params.QueueUrl)
+		for _, msg := range res.Messages {
+			if msg == nil {
+				continue
+			}
+			c, _ := sqsExtractCarrier(msg)
+			propagation.SetConsumeCheckpoint(c, "sqs", queue, sqsMessageSize(aws.StringValue(msg.Body), msg.MessageAttributes))
+		}
+	case *kinesis.GetRecordsOutput:
+		params, ok := req.Params.(*kinesis.GetRecordsInput)
+		if !ok {
+			return
+		}
+		stream := kinesisStreamName(nil, params.StreamARN)
+		for _, r := range res.Records {
+			if r == nil {
+				continue
+			}
+			c, _ := propagation.ExtractFromJSON(r.Data)
+			propagation.SetConsumeCheckpoint(c, "kinesis", stream, int64(len(r.Data)))
+		}
+	}
+}
+
+// sqsExtractCarrier returns the carrier propagated with msg, either as a
+// message attribute or inside the SNS notification wrapping it.
+func sqsExtractCarrier(msg *sqs.Message) (tracer.TextMapCarrier, bool) {
+	if attr, ok := msg.MessageAttributes[propagation.AttributeName]; ok && attr != nil {
+		b := attr.BinaryValue
+		if attr.StringValue != nil {
+			b = []byte(*attr.StringValue)
+		}
+		c, err := propagation.Decode(b)
+		if err != nil {
+			log.Debug("contrib/aws/aws-sdk-go/aws: Failed to decode %s message attribute: %v", propagation.AttributeName, err)
+			return nil, false
+		}
+		return c, true
+	}
+	return propagation.ExtractFromSNSNotification(aws.StringValue(msg.Body))
+}
+
+func sqsAttributesWithPathway(ctx context.Context, attrs map[string]*sqs.MessageAttributeValue, queue, body string) map[string]*sqs.MessageAttributeValue {
+	if len(attrs) >= propagation.MaxMessageAttributes {
+		log.Debug("contrib/aws/aws-sdk-go/aws: Cannot propagate pathway, message already has %d attributes", len(attrs))
+		return attrs
+	}
+	c := tracer.TextMapCarrier{}
+	propagation.SetProduceCheckpoint(ctx, c, "sqs", queue, sqsMessageSize(body, attrs))
+	v, err := propagation.Encode(c)
+	if err != nil {
+		return attrs
+	}
+	out := make(map[string]*sqs.MessageAttributeValue, len(attrs)+1)
+	for k, a := range attrs {
+		out[k] = a
+	}
+	out[propagation.AttributeName] = &sqs.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(string(v)),
+	}
+	return out
+}
+
+func snsAttributesWithPathway(ctx context.Context, attrs map[string]*sns.MessageAttributeValue, topic, body string) map[string]*sns.MessageAttributeValue {
+	if len(attrs) >= propagation.MaxMessageAttributes {
+		log.Debug("contrib/aws/aws-sdk-go/aws: Cannot propagate pathway, message already has %d attributes", len(attrs))
+		return attrs
+	}
+	size := int64(len(body))
+	for k, a := range attrs {
+		if a != nil {
+			size += int64(len(k) + len(aws.StringValue(a.StringValue)) + len(a.BinaryValue))
+		}
+	}
+	c := tracer.TextMapCarrier{}
+	propagation.SetProduceCheckpoint(ctx, c, "sns", topic, size)
+	v, err := propagation.Encode(c)
+	if err != nil {
+		return attrs
+	}
+	out := make(map[string]*sns.MessageAttributeValue, len(attrs)+1)
+	for k, a := range attrs {
+		out[k] = a
+	}
+	// A binary attribute is used so that the value can be decoded the same way
+	// whether it reaches SQS wrapped in a notification or as a raw message.
+	out[propagation.AttributeName] = &sns.MessageAttributeValue{
+		DataType:    aws.String("Binary"),
+		BinaryValue: v,
+	}
+	return out
+}
+
+func kinesisDataWithPathway(ctx context.Context, data []byte, stream, partitionKey string) []byte {
+	if !propagation.CanInjectPathwayIntoJSON(data, propagation.MaxKinesisRecordSize-len(partitionKey)) {
+		log.Debug("contrib/aws/aws-sdk-go/aws: Cannot propagate pathway, record data is not a JSON object or is too large")
+		return data
+	}
+	c := tracer.TextMapCarrier{}
+	propagation.SetProduceCheckpoint(ctx, c, "kinesis", stream, int64(len(data)))
+	if len(c) == 0 {
+		return data
+	}
+	out, _ := propagation.InjectIntoJSON(data, c)
+	return out
+}
+
+func sqsMessageSize(body string, attrs map[string]*sqs.MessageAttributeValue) int64 {
+	size := int64(len(body))
+	for k, a := range attrs {
+		if a != nil {
+			size += int64(len(k) + len(aws.StringValue(a.StringValue)) + len(a.BinaryValue))
+		}
+	}
+	return size
+}
+
+func sqsQueueName(queueURL *string) string {
+	parts := strings.Split(aws.StringValue(queueURL), "/")
+	return parts[len(parts)-1]
+}
+
+func snsTopicName(arn *string) string {
+	parts := strings.Split(aws.StringValue(arn), ":")
+	return parts[len(parts)-1]
+}
+
+func kinesisStreamName(name, arn *string) string {
+	if name != nil {
+		return *name
+	}
+	parts := strings.Split(aws.StringValue(arn), "/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/contrib/aws/internal/propagation"
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingSession returns a session sending requests to a mock server
+// which records the last request body.
+func newRecordingSession(t *testing.T, body *[]byte, opts ...Option) *session.Session {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*body, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Amz-RequestId", "test_req")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	resolver := endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		return endpoints.ResolvedEndpoint{
+			PartitionID:   "aws",
+			URL:           server.URL,
+			SigningRegion: "eu-west-1",
+		}, nil
+	})
+	awsCfg := aws.Config{
+		Region:           aws.String("eu-west-1"),
+		Credentials:      credentials.AnonymousCredentials,
+		EndpointResolver: resolver,
+		MaxRetries:       aws.Int(0),
+	}
+	return WrapSession(session.Must(session.NewSession(&awsCfg)), opts...)
+}
+
+func assertPathway(t *testing.T, c tracer.TextMapCarrier, edges ...string) {
+	t.Helper()
+	p, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), c))
+	require.True(t, ok)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), edges...)
+	expected, _ := datastreams.PathwayFromContext(ctx)
+	assert.NotEqual(t, uint64(0), expected.GetHash())
+	assert.Equal(t, expected.GetHash(), p.GetHash())
+}
+
+func TestDataStreamsSQSSendMessage(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	input := &sqs.SendMessageInput{
+		MessageBody: aws.String("foobar"),
+		QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	}
+	sqs.New(newRecordingSession(t, &body, WithDataStreams())).SendMessage(input)
+	assert.Nil(t, input.MessageAttributes, "caller input must not be modified")
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, propagation.AttributeName, form.Get("MessageAttribute.1.Name"))
+	assert.Equal(t, "String", form.Get("MessageAttribute.1.Value.DataType"))
+	c, err := propagation.Decode([]byte(form.Get("MessageAttribute.1.Value.StringValue")))
+	require.NoError(t, err)
+	assertPathway(t, c, "direction:out", "topic:MyQueueName", "type:sqs")
+}
+
+func TestDataStreamsSNSPublish(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	sns.New(newRecordingSession(t, &body, WithDataStreams())).Publish(&sns.PublishInput{
+		Message:  aws.String("foobar"),
+		TopicArn: aws.String("arn:aws:sns:us-west-2:123456789012:MyTopicName"),
+	})
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, propagation.AttributeName, form.Get("MessageAttributes.entry.1.Name"))
+	assert.Equal(t, "Binary", form.Get("MessageAttributes.entry.1.Value.DataType"))
+	v, err := base64.StdEncoding.DecodeString(form.Get("MessageAttributes.entry.1.Value.BinaryValue"))
+	require.NoError(t, err)
+	c, err := propagation.Decode(v)
+	require.NoError(t, err)
+	assertPathway(t, c, "direction:out", "topic:MyTopicName", "type:sns")
+}
+
+func TestDataStreamsKinesisPutRecord(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	kinesis.New(newRecordingSession(t, &body, WithDataStreams())).PutRecord(&kinesis.PutRecordInput{
+		StreamName:   aws.String("my-kinesis-stream"),
+		Data:         []byte(`{"hello":"<world>"}`),
+		PartitionKey: aws.String("1234"),
+	})
+
+	var req struct{ Data []byte }
+	require.NoError(t, json.Unmarshal(body, &req))
+	// The original fields are left untouched.
+	assert.True(t, bytes.HasPrefix(req.Data, []byte(`{"hello":"<world>",`)), string(req.Data))
+	assert.LessOrEqual(t, len(req.Data)-len(`{"hello":"<world>"}`), 128)
+	c, ok := propagation.ExtractFromJSON(req.Data)
+	require.True(t, ok)
+	assertPathway(t, c, "direction:out", "topic:my-kinesis-stream", "type:kinesis")
+
+	t.Run("max-size", func(t *testing.T) {
+		data := []byte(`{"hello":"` + strings.Repeat("a", propagation.MaxKinesisRecordSize-len(`{"hello":""}`)-len("1234")) + `"}`)
+		kinesis.New(newRecordingSession(t, &body, WithDataStreams())).PutRecord(&kinesis.PutRecordInput{
+			StreamName:   aws.String("my-kinesis-stream"),
+			Data:         data,
+			PartitionKey: aws.String("1234"),
+		})
+
+		var req struct{ Data []byte }
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, data, req.Data)
+	})
+}
+
+func TestDataStreamsDisabled(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var body []byte
+	sqs.New(newRecordingSession(t, &body)).SendMessage(&sqs.SendMessageInput{
+		MessageBody: aws.String("foobar"),
+		QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	})
+
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Empty(t, form.Get("MessageAttribute.1.Name"))
+}
+
+func TestSQSExtractCarrier(t *testing.T) {
+	want := tracer.TextMapCarrier{"dd-pathway-ctx-base64": "abc"}
+	v, err := propagation.Encode(want)
+	require.NoError(t, err)
+
+	c, ok := sqsExtractCarrier(&sqs.Message{MessageAttributes: map[string]*sqs.MessageAttributeValue{
+		propagation.AttributeName: {DataType: aws.String("String"), StringValue: aws.String(string(v))},
+	}})
+	require.True(t, ok)
+	assert.Equal(t, want, c)
+
+	c, ok = sqsExtractCarrier(&sqs.Message{MessageAttributes: map[string]*sqs.MessageAttributeValue{
+		propagation.AttributeName: {DataType: aws.String("Binary"), BinaryValue: v},
+	}})
+	require.True(t, ok)
+	assert.Equal(t, want, c)
+
+	_, ok = sqsExtractCarrier(&sqs.Message{Body: aws.String("hello")})
+	assert.False(t, ok)
+}
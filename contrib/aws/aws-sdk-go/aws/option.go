@@ -15,6 +15,8 @@ type config struct {
 	serviceName   string
 	analyticsRate float64
 	errCheck      func(err error) bool
+	// dataStreamsEnabled enables Data Streams monitoring for SQS, SNS and Kinesis.
+	dataStreamsEnabled bool
 }
 
 // Option represents an option that can be passed to Dial.
@@ -27,6 +29,7 @@ func defaults(cfg *config) {
 	} else {
 		cfg.analyticsRate = math.NaN()
 	}
+	cfg.dataStreamsEnabled = internal.BoolEnv("DD_DATA_STREAMS_ENABLED", false)
 }
 
 // WithServiceName sets the given service name for the dialled connection.
@@ -69,3 +72,10 @@ func WithErrorCheck(fn func(err error) bool) Option {
 		cfg.errCheck = fn
 	}
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() Option {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
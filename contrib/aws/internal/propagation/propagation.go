@@ -0,0 +1,169 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+// Package propagation provides helpers shared by the AWS integrations to carry
+// Datadog context through SQS and SNS message attributes and Kinesis records.
+package propagation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/datastreams/options"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+)
+
+const (
+	// AttributeName is the name of the message attribute, or of the JSON field
+	// in Kinesis records, holding the propagated Datadog context.
+	AttributeName = "_datadog"
+
+	// MaxMessageAttributes is the maximum number of message attributes SQS and
+	// SNS accept on a single message.
+	MaxMessageAttributes = 10
+
+	// MaxKinesisRecordSize is the maximum size of a Kinesis record, counting
+	// its data and its partition key.
+	MaxKinesisRecordSize = 1 << 20
+
+	// maxPathwayFieldSize is an upper bound of the size of the _datadog field
+	// added by InjectIntoJSON to carry a Data Streams pathway.
+	maxPathwayFieldSize = 128
+)
+
+// Decode decodes the JSON value of a _datadog attribute into a carrier.
+func Decode(b []byte) (tracer.TextMapCarrier, error) {
+	var c tracer.TextMapCarrier
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Encode encodes c as the JSON value of a _datadog attribute.
+func Encode(c tracer.TextMapCarrier) ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// InjectIntoJSON returns data with c set under a _datadog field appended to
+// the object, leaving the bytes of its other fields untouched. It returns false
+// and leaves data untouched when data is not a JSON object or already holds a
+// _datadog field.
+func InjectIntoJSON(data []byte, c tracer.TextMapCarrier) ([]byte, bool) {
+	if !isInjectableJSON(data) {
+		return data, false
+	}
+	v, err := Encode(c)
+	if err != nil {
+		return data, false
+	}
+	// The last byte of the object is its closing brace, as only whitespace
+	// can follow it.
+	end := bytes.LastIndexByte(data, '}')
+	start := bytes.IndexByte(data, '{')
+	out := make([]byte, 0, len(data)+len(AttributeName)+len(v)+4)
+	out = append(out, data[:end]...)
+	if len(bytes.TrimSpace(data[start+1:end])) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, '"')
+	out = append(out, AttributeName...)
+	out = append(out, '"', ':')
+	out = append(out, v...)
+	out = append(out, data[end:]...)
+	return out, true
+}
+
+// CanInjectPathwayIntoJSON reports whether InjectIntoJSON can inject a Data
+// Streams pathway into data without making it larger than maxSize, so that
+// the produce checkpoint is only set when the pathway can be propagated.
+func CanInjectPathwayIntoJSON(data []byte, maxSize int) bool {
+	return len(data)+maxPathwayFieldSize <= maxSize && isInjectableJSON(data)
+}
+
+// isInjectableJSON reports whether data is a JSON object without a _datadog
+// field.
+func isInjectableJSON(data []byte) bool {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return false
+	}
+	_, ok := obj[AttributeName]
+	return !ok
+}
+
+// ExtractFromJSON returns the carrier found under the _datadog field of data,
+// if data is a JSON object holding one.
+func ExtractFromJSON(data []byte) (tracer.TextMapCarrier, bool) {
+	var obj struct {
+		Datadog tracer.TextMapCarrier `json:"_datadog"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil || obj.Datadog == nil {
+		return nil, false
+	}
+	return obj.Datadog, true
+}
+
+// ExtractFromSNSNotification returns the carrier found in the message
+// attributes of an SNS notification, such as the body of an SQS message
+// delivered by an SNS subscription without raw message delivery.
+func ExtractFromSNSNotification(body string) (tracer.TextMapCarrier, bool) {
+	var n struct {
+		Type              string
+		MessageAttributes map[string]struct {
+			Type  string
+			Value string
+		}
+	}
+	if err := json.Unmarshal([]byte(body), &n); err != nil || n.Type != "Notification" {
+		return nil, false
+	}
+	attr, ok := n.MessageAttributes[AttributeName]
+	if !ok {
+		return nil, false
+	}
+	b := []byte(attr.Value)
+	if attr.Type == "Binary" {
+		var err error
+		if b, err = base64.StdEncoding.DecodeString(attr.Value); err != nil {
+			return nil, false
+		}
+	}
+	c, err := Decode(b)
+	if err != nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// SetProduceCheckpoint sets a Data Streams produce checkpoint for a message
+// sent to topic through the messaging system typ. The parent pathway is read
+// from ctx, and the resulting pathway is injected into c.
+func SetProduceCheckpoint(ctx context.Context, c tracer.TextMapCarrier, typ, topic string, payloadSize int64) {
+	edges := []string{"direction:out", "topic:" + topic, "type:" + typ}
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: payloadSize}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, c)
+}
+
+// SetConsumeCheckpoint sets a Data Streams consume checkpoint for a message
+// received from topic through the messaging system typ. The parent pathway is
+// read from c, which may be nil.
+func SetConsumeCheckpoint(c tracer.TextMapCarrier, typ, topic string, payloadSize int64) {
+	ctx := context.Background()
+	if c != nil {
+		ctx = datastreams.ExtractFromBase64Carrier(ctx, c)
+	}
+	edges := []string{"direction:in", "topic:" + topic, "type:" + typ}
+	tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: payloadSize}, edges...)
+}
@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package propagation
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	c := tracer.TextMapCarrier{"key": "value"}
+
+	data, ok := InjectIntoJSON([]byte(`{"a":1}`), c)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"a":1,"_datadog":{"key":"value"}}`, string(data))
+
+	got, ok := ExtractFromJSON(data)
+	require.True(t, ok)
+	assert.Equal(t, c, got)
+
+	// The other fields are left byte for byte as is.
+	data, ok = InjectIntoJSON([]byte(`{"b":"<&>", "a":[1, 2]} `), c)
+	require.True(t, ok)
+	assert.Equal(t, `{"b":"<&>", "a":[1, 2],"_datadog":{"key":"value"}} `, string(data))
+
+	for _, in := range []string{`{}`, ` { } `} {
+		data, ok := InjectIntoJSON([]byte(in), c)
+		require.True(t, ok, in)
+		assert.JSONEq(t, `{"_datadog":{"key":"value"}}`, string(data))
+	}
+
+	// An existing field is not overwritten.
+	data, ok = InjectIntoJSON([]byte(`{"_datadog":{}}`), c)
+	assert.False(t, ok)
+	assert.Equal(t, `{"_datadog":{}}`, string(data))
+
+	for _, in := range []string{`[1]`, `"a"`, `not json`, `null`, `{"a":1`} {
+		data, ok := InjectIntoJSON([]byte(in), c)
+		assert.False(t, ok, in)
+		assert.Equal(t, in, string(data))
+		_, ok = ExtractFromJSON([]byte(in))
+		assert.False(t, ok, in)
+	}
+}
+
+func TestCanInjectPathwayIntoJSON(t *testing.T) {
+	data := []byte(`{"a":"` + strings.Repeat("a", 100) + `"}`)
+	assert.True(t, CanInjectPathwayIntoJSON(data, len(data)+maxPathwayFieldSize))
+	assert.False(t, CanInjectPathwayIntoJSON(data, len(data)+maxPathwayFieldSize-1))
+	assert.False(t, CanInjectPathwayIntoJSON([]byte(`[1]`), MaxKinesisRecordSize))
+}
+
+func TestExtractFromSNSNotification(t *testing.T) {
+	want := tracer.TextMapCarrier{"key": "value"}
+
+	c, ok := ExtractFromSNSNotification(`{"Type":"Notification","MessageAttributes":{"_datadog":{"Type":"String","Value":"{\"key\":\"value\"}"}}}`)
+	require.True(t, ok)
+	assert.Equal(t, want, c)
+
+	v := base64.StdEncoding.EncodeToString([]byte(`{"key":"value"}`))
+	c, ok = ExtractFromSNSNotification(`{"Type":"Notification","MessageAttributes":{"_datadog":{"Type":"Binary","Value":"` + v + `"}}}`)
+	require.True(t, ok)
+	assert.Equal(t, want, c)
+
+	_, ok = ExtractFromSNSNotification(`{"Type":"Notification","MessageAttributes":{}}`)
+	assert.False(t, ok)
+	_, ok = ExtractFromSNSNotification(`hello`)
+	assert.False(t, ok)
+}
@@ -10,6 +10,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/datastreams/options"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -63,6 +65,9 @@ func Publish(ctx context.Context, t *pubsub.Topic, msg *pubsub.Message, opts ...
 	if err := tracer.Inject(span.Context(), tracer.TextMapCarrier(msg.Attributes)); err != nil {
 		log.Debug("contrib/cloud.google.com/go/pubsub.v1/: failed injecting tracing attributes: %v", err)
 	}
+	if cfg.dataStreamsEnabled {
+		setProduceCheckpoint(ctx, t.ID(), msg)
+	}
 	span.SetTag("num_attributes", len(msg.Attributes))
 	return &PublishResult{
 		PublishResult: t.Publish(ctx, msg),
@@ -123,6 +128,36 @@ func WrapReceiveHandler(s *pubsub.Subscription, f func(context.Context, *pubsub.
 			span.SetTag("delivery_attempt", *msg.DeliveryAttempt)
 		}
 		defer span.Finish()
+		if cfg.dataStreamsEnabled {
+			ctx = setConsumeCheckpoint(ctx, s.ID(), msg)
+		}
 		f(ctx, msg)
 	}
 }
+
+func setProduceCheckpoint(ctx context.Context, topic string, msg *pubsub.Message) {
+	edges := []string{"direction:out", "topic:" + topic, "type:google-pubsub"}
+	carrier := tracer.TextMapCarrier(msg.Attributes)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(ctx, carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+// setConsumeCheckpoint sets a consume checkpoint for msg and returns ctx
+// holding the resulting pathway, so that messages produced while handling msg
+// are part of the same pathway.
+func setConsumeCheckpoint(ctx context.Context, subscription string, msg *pubsub.Message) context.Context {
+	edges := []string{"direction:in", "topic:" + subscription, "type:google-pubsub"}
+	carrier := tracer.TextMapCarrier(msg.Attributes)
+	ctx, _ = tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(ctx, carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	return ctx
+}
+
+func getMsgSize(msg *pubsub.Message) (size int64) {
+	for k, v := range msg.Attributes {
+		size += int64(len(k) + len(v))
+	}
+	return size + int64(len(msg.Data))
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
+	"github.com/nowfred/dd-trace-go/datastreams"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -116,6 +117,35 @@ func TestPropagationWithServiceName(t *testing.T) {
 	assert.Equal("example.service", spans[2].Tag(ext.ServiceName))
 }
 
+func TestDataStreams(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel, _, topic, sub := setup(t)
+
+	_, err := Publish(ctx, topic, &pubsub.Message{Data: []byte("hello")}, WithDataStreams()).Get(ctx)
+	assert.NoError(err)
+
+	var (
+		msgCtx context.Context
+		called bool
+	)
+	err = sub.Receive(ctx, WrapReceiveHandler(sub, func(ctx context.Context, msg *pubsub.Message) {
+		msgCtx = ctx
+		called = true
+		msg.Ack()
+		cancel()
+	}, WithDataStreams()))
+	assert.NoError(err)
+	require.True(t, called, "callback not called")
+
+	p, ok := datastreams.PathwayFromContext(msgCtx)
+	require.True(t, ok)
+	expectedCtx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:topic", "type:google-pubsub")
+	expectedCtx, _ = tracer.SetDataStreamsCheckpoint(expectedCtx, "direction:in", "topic:subscription", "type:google-pubsub")
+	expected, _ := datastreams.PathwayFromContext(expectedCtx)
+	assert.NotEqual(uint64(0), expected.GetHash())
+	assert.Equal(expected.GetHash(), p.GetHash())
+}
+
 func TestPropagationNoParentSpan(t *testing.T) {
 	assert := assert.New(t)
 	ctx, cancel, mt, topic, sub := setup(t)
@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/grpcsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace/grpctrace"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace/httptrace"
+	"github.com/nowfred/dd-trace-go/internal/log"
+
+	"connectrpc.com/connect"
+)
+
+// appsecUnary calls next while monitoring the unary call with AppSec.
+func appsecUnary(ctx context.Context, span ddtrace.Span, req connect.AnyRequest, next connect.UnaryFunc) (connect.AnyResponse, error) {
+	ctx, op := startHandlerOperation(ctx, span, req.Header(), req.Peer())
+	defer op.finish(span)
+	if op.err != nil {
+		return nil, op.err
+	}
+	defer grpcsec.StartReceiveOperation(grpcsec.ReceiveOperationArgs{}, op.HandlerOperation).Finish(grpcsec.ReceiveOperationRes{Message: req.Any()})
+	res, err := next(ctx, req)
+	return res, monitoringError(err)
+}
+
+// appsecStreamingHandler calls next while monitoring the streaming call and the
+// messages it receives with AppSec.
+func appsecStreamingHandler(ctx context.Context, span ddtrace.Span, conn connect.StreamingHandlerConn, next connect.StreamingHandlerFunc) error {
+	ctx, op := startHandlerOperation(ctx, span, conn.RequestHeader(), conn.Peer())
+	defer op.finish(span)
+	if op.err != nil {
+		return op.err
+	}
+	err := next(ctx, &appsecHandlerConn{StreamingHandlerConn: conn, op: op.HandlerOperation})
+	return monitoringError(err)
+}
+
+// handlerOperation is a grpcsec handler operation along with the error to
+// return when AppSec blocks the call.
+type handlerOperation struct {
+	*grpcsec.HandlerOperation
+	md      map[string][]string
+	err     error
+	blocked bool
+}
+
+func startHandlerOperation(ctx context.Context, span ddtrace.Span, header http.Header, peer connect.Peer) (context.Context, *handlerOperation) {
+	trace.SetAppSecEnabledTags(span)
+	ipTags, clientIP := httptrace.ClientIPTags(header, true, peer.Addr)
+	log.Debug("appsec: http client ip detection returned `%s` given the http headers `%v`", clientIP, header)
+	if len(ipTags) > 0 {
+		trace.SetTags(span, ipTags)
+	}
+	op := &handlerOperation{md: metadata(header)}
+	ctx, op.HandlerOperation = grpcsec.StartHandlerOperation(ctx, grpcsec.HandlerOperationArgs{Metadata: op.md, ClientIP: clientIP}, nil, dyngo.NewDataListener(func(a *sharedsec.Action) {
+		code, e := a.GRPC()(op.md)
+		op.blocked = a.Blocking()
+		op.err = connect.NewError(connect.Code(code), e)
+	}))
+	return ctx, op
+}
+
+func (op *handlerOperation) finish(span ddtrace.Span) {
+	events := op.Finish(grpcsec.HandlerOperationRes{})
+	if op.blocked {
+		op.SetTag(trace.BlockedRequestTag, true)
+	}
+	grpctrace.SetRequestMetadataTags(span, op.md)
+	trace.SetTags(span, op.Tags())
+	if len(events) > 0 {
+		grpctrace.SetSecurityEventsTags(span, events)
+	}
+}
+
+// monitoringError converts the errors returned by AppSec SDK functions, such as
+// when blocking a user, into Connect errors.
+func monitoringError(err error) error {
+	var e *grpcsec.MonitoringError
+	if errors.As(err, &e) {
+		return connect.NewError(connect.Code(e.GRPCStatus()), e)
+	}
+	return err
+}
+
+// metadata returns the request headers with lowercase keys, as gRPC metadata.
+func metadata(header http.Header) map[string][]string {
+	md := make(map[string][]string, len(header))
+	for k, v := range header {
+		k = strings.ToLower(k)
+		md[k] = append(md[k], v...)
+	}
+	return md
+}
+
+// appsecHandlerConn monitors the messages received by a streaming handler.
+type appsecHandlerConn struct {
+	connect.StreamingHandlerConn
+	op *grpcsec.HandlerOperation
+}
+
+func (c *appsecHandlerConn) Receive(msg any) error {
+	op := grpcsec.StartReceiveOperation(grpcsec.ReceiveOperationArgs{}, c.op)
+	defer func() {
+		op.Finish(grpcsec.ReceiveOperationRes{Message: msg})
+	}()
+	return c.StreamingHandlerConn.Receive(msg)
+}
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package connect
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// serverSpan returns the server span among the finished spans of mt.
+func serverSpan(t *testing.T, mt mocktracer.Tracer) mocktracer.Span {
+	t.Helper()
+	for _, s := range mt.FinishedSpans() {
+		if s.Tag(ext.SpanKind) == ext.SpanKindServer {
+			return s
+		}
+	}
+	require.Fail(t, "no server span")
+	return nil
+}
+
+func TestAppSec(t *testing.T) {
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		f := newFixture(t, nil, nil)
+		req := connect.NewRequest(wrapperspb.String("<script>evilJSCode;</script>"))
+		req.Header().Set("dd-canary", "dd-test-scanner-log")
+		res, err := f.ping.CallUnary(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, "passed", res.Msg.GetValue())
+
+		event, _ := serverSpan(t, mt).Tag("_dd.appsec.json").(string)
+		require.True(t, strings.Contains(event, "crs-941-110")) // XSS attack attempt
+		require.True(t, strings.Contains(event, "ua0-600-55x")) // canary rule attack attempt
+	})
+
+	t.Run("client-stream", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		f := newFixture(t, nil, nil)
+		stream := f.clientStream.CallClientStream(context.Background())
+		require.NoError(t, stream.Send(wrapperspb.String("hello")))
+		require.NoError(t, stream.Send(wrapperspb.String("-1' and 1=1 union select * from users--")))
+		_, err := stream.CloseAndReceive()
+		require.NoError(t, err)
+
+		event, _ := serverSpan(t, mt).Tag("_dd.appsec.json").(string)
+		require.True(t, strings.Contains(event, "crs-942-270")) // SQL-injection attack attempt
+	})
+}
+
+func TestBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	for _, tc := range []struct {
+		ip      string
+		blocked bool
+	}{
+		{ip: "1.2.3.4", blocked: true},
+		{ip: "1.2.3.5", blocked: false},
+	} {
+		t.Run(tc.ip, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			f := newFixture(t, nil, nil)
+			req := connect.NewRequest(wrapperspb.String("hello"))
+			req.Header().Set("x-client-ip", tc.ip)
+			res, err := f.ping.CallUnary(context.Background(), req)
+			if !tc.blocked {
+				require.NoError(t, err)
+				require.Equal(t, "passed", res.Msg.GetValue())
+				return
+			}
+			require.Equal(t, connect.CodeAborted, connect.CodeOf(err))
+
+			event, _ := serverSpan(t, mt).Tag("_dd.appsec.json").(string)
+			require.True(t, strings.Contains(event, "blk-001-001"))
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package connect provides functions to trace the connectrpc.com/connect package (https://connectrpc.com).
+package connect // import "github.com/nowfred/dd-trace-go/contrib/connectrpc/connect-go"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+)
+
+const componentName = "connectrpc/connect-go"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("connectrpc.com/connect")
+}
+
+// Tags used for Connect, following the conventions of the gRPC integration.
+const (
+	tagMethodName = "grpc.method.name"
+	tagMethodKind = "grpc.method.kind"
+	tagCode       = "grpc.code"
+	tagProtocol   = "connect.protocol"
+)
+
+const (
+	methodKindUnary        = "unary"
+	methodKindClientStream = "client_streaming"
+	methodKindServerStream = "server_streaming"
+	methodKindBidiStream   = "bidi_streaming"
+)
+
+// NewInterceptor returns a connect.Interceptor tracing the unary and streaming
+// calls of the clients and handlers it is used with, for instance with
+// connect.WithInterceptors. The trace context is propagated in the request
+// headers.
+func NewInterceptor(opts ...Option) connect.Interceptor {
+	cfg := newConfig(opts...)
+	log.Debug("contrib/connectrpc/connect-go: Configuring Interceptor: %#v", cfg)
+	return &interceptor{cfg: cfg}
+}
+
+type interceptor struct {
+	cfg *config
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		spec := req.Spec()
+		if _, ok := i.cfg.untracedMethods[spec.Procedure]; ok {
+			return next(ctx, req)
+		}
+		span, ctx := i.startSpan(ctx, spec, req.Peer(), req.Header())
+		var (
+			res connect.AnyResponse
+			err error
+		)
+		if !spec.IsClient && appsec.Enabled() {
+			res, err = appsecUnary(ctx, span, req, next)
+		} else {
+			res, err = next(ctx, req)
+		}
+		finishWithError(span, err, i.cfg)
+		return res, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		if _, ok := i.cfg.untracedMethods[spec.Procedure]; ok {
+			return next(ctx, spec)
+		}
+		span, ctx := i.startSpan(ctx, spec, connect.Peer{}, nil)
+		conn := next(ctx, spec)
+		setPeerTags(span, conn.Peer())
+		injectHeaders(span, conn.RequestHeader())
+		return &clientConn{StreamingClientConn: conn, span: span, cfg: i.cfg}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		spec := conn.Spec()
+		if _, ok := i.cfg.untracedMethods[spec.Procedure]; ok {
+			return next(ctx, conn)
+		}
+		span, ctx := i.startSpan(ctx, spec, conn.Peer(), conn.RequestHeader())
+		var err error
+		if appsec.Enabled() {
+			err = appsecStreamingHandler(ctx, span, conn, next)
+		} else {
+			err = next(ctx, conn)
+		}
+		finishWithError(span, err, i.cfg)
+		return err
+	}
+}
+
+// startSpan starts the client or server span of a call. On the server side, the
+// span is a child of the span context extracted from header. On the client
+// side, the span context is injected into header when it is not nil.
+func (i *interceptor) startSpan(ctx context.Context, spec connect.Spec, peer connect.Peer, header http.Header) (ddtrace.Span, context.Context) {
+	opts := []ddtrace.StartSpanOption{
+		tracer.Measured(),
+		tracer.ResourceName(spec.Procedure),
+		tracer.SpanType(ext.AppTypeRPC),
+		tracer.Tag(tagMethodName, spec.Procedure),
+		tracer.Tag(tagMethodKind, methodKind(spec.StreamType)),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.RPCSystem, ext.RPCSystemConnectRPC),
+		tracer.Tag(ext.GRPCFullMethod, spec.Procedure),
+	}
+	if service, method, ok := strings.Cut(strings.TrimPrefix(spec.Procedure, "/"), "/"); ok {
+		opts = append(opts, tracer.Tag(ext.RPCService, service), tracer.Tag(ext.RPCMethod, method))
+	}
+	if peer.Protocol != "" {
+		opts = append(opts, tracer.Tag(tagProtocol, peer.Protocol))
+	}
+	if !math.IsNaN(i.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, i.cfg.analyticsRate))
+	}
+	var name string
+	if spec.IsClient {
+		name = i.cfg.clientSpanName
+		opts = append(opts,
+			tracer.ServiceName(i.cfg.clientServiceName),
+			tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		)
+	} else {
+		name = i.cfg.serverSpanName
+		opts = append(opts,
+			tracer.ServiceName(i.cfg.serverServiceName()),
+			tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+		)
+		if sctx, err := tracer.Extract(tracer.HTTPHeadersCarrier(header)); err == nil {
+			opts = append(opts, tracer.ChildOf(sctx))
+		}
+	}
+	opts = append(opts, i.cfg.spanOpts...)
+	span, ctx := tracer.StartSpanFromContext(ctx, name, opts...)
+	if spec.IsClient {
+		setPeerTags(span, peer)
+		if header != nil {
+			injectHeaders(span, header)
+		}
+	}
+	return span, ctx
+}
+
+// finishWithError finishes span with the status code of err, which is reported
+// as an error unless it is one of the configured non-error codes.
+func finishWithError(span ddtrace.Span, err error, cfg *config) {
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	code := codes.OK
+	if err != nil {
+		c := connect.CodeOf(err)
+		code = codes.Code(c)
+		if cfg.nonErrorCodes[c] {
+			err = nil
+		}
+	}
+	span.SetTag(tagCode, code.String())
+	span.Finish(tracer.WithError(err))
+}
+
+func injectHeaders(span ddtrace.Span, header http.Header) {
+	if err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(header)); err != nil {
+		log.Debug("contrib/connectrpc/connect-go: Failed to inject span context in request headers: %v", err)
+	}
+}
+
+func setPeerTags(span ddtrace.Span, peer connect.Peer) {
+	if peer.Addr == "" {
+		return
+	}
+	host, port, err := net.SplitHostPort(peer.Addr)
+	if err != nil {
+		host = peer.Addr
+	}
+	span.SetTag(ext.TargetHost, host)
+	if port != "" {
+		span.SetTag(ext.TargetPort, port)
+	}
+}
+
+func methodKind(t connect.StreamType) string {
+	switch t {
+	case connect.StreamTypeClient:
+		return methodKindClientStream
+	case connect.StreamTypeServer:
+		return methodKindServerStream
+	case connect.StreamTypeBidi:
+		return methodKindBidiStream
+	default:
+		return methodKindUnary
+	}
+}
+
+// clientConn finishes the span of a client stream once its response is closed,
+// with the first error other than io.EOF returned while sending or receiving.
+type clientConn struct {
+	connect.StreamingClientConn
+	span ddtrace.Span
+	cfg  *config
+
+	mu   sync.Mutex
+	err  error
+	once sync.Once
+}
+
+func (c *clientConn) Send(msg any) error {
+	return c.record(c.StreamingClientConn.Send(msg))
+}
+
+func (c *clientConn) Receive(msg any) error {
+	return c.record(c.StreamingClientConn.Receive(msg))
+}
+
+func (c *clientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		finishWithError(c.span, c.err, c.cfg)
+	})
+	return err
+}
+
+func (c *clientConn) record(err error) error {
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.mu.Lock()
+		if c.err == nil {
+			c.err = err
+		}
+		c.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,258 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	pingProcedure         = "/test.v1.FixtureService/Ping"
+	serverStreamProcedure = "/test.v1.FixtureService/ServerStream"
+	clientStreamProcedure = "/test.v1.FixtureService/ClientStream"
+)
+
+// fixture is a client of a Connect server serving the Ping, ServerStream and
+// ClientStream procedures.
+type fixture struct {
+	ping         *connect.Client[wrapperspb.StringValue, wrapperspb.StringValue]
+	serverStream *connect.Client[wrapperspb.StringValue, wrapperspb.StringValue]
+	clientStream *connect.Client[wrapperspb.StringValue, wrapperspb.StringValue]
+}
+
+func ping(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+	switch req.Msg.GetValue() {
+	case "invalid":
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid"))
+	case "canceled":
+		return nil, connect.NewError(connect.CodeCanceled, errors.New("canceled"))
+	case "child":
+		span, _ := tracer.StartSpanFromContext(ctx, "child")
+		span.Finish()
+	}
+	return connect.NewResponse(wrapperspb.String("passed")), nil
+}
+
+func newFixture(t *testing.T, serverOpts, clientOpts []Option) *fixture {
+	serverInterceptors := connect.WithInterceptors(NewInterceptor(serverOpts...))
+	mux := http.NewServeMux()
+	mux.Handle(pingProcedure, connect.NewUnaryHandler(pingProcedure, ping, serverInterceptors))
+	mux.Handle(serverStreamProcedure, connect.NewServerStreamHandler(serverStreamProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			for i := 0; i < 2; i++ {
+				if err := stream.Send(req.Msg); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, serverInterceptors))
+	mux.Handle(clientStreamProcedure, connect.NewClientStreamHandler(clientStreamProcedure,
+		func(ctx context.Context, stream *connect.ClientStream[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			var last string
+			for stream.Receive() {
+				last = stream.Msg().GetValue()
+			}
+			if err := stream.Err(); err != nil {
+				return nil, err
+			}
+			return connect.NewResponse(wrapperspb.String(last)), nil
+		}, serverInterceptors))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	clientInterceptors := connect.WithInterceptors(NewInterceptor(clientOpts...))
+	return &fixture{
+		ping:         connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+pingProcedure, clientInterceptors),
+		serverStream: connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+serverStreamProcedure, clientInterceptors),
+		clientStream: connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+clientStreamProcedure, clientInterceptors),
+	}
+}
+
+// spansByKind returns the client and server spans among spans.
+func spansByKind(t *testing.T, spans []mocktracer.Span) (client, server mocktracer.Span) {
+	t.Helper()
+	for _, s := range spans {
+		switch s.Tag(ext.SpanKind) {
+		case ext.SpanKindClient:
+			client = s
+		case ext.SpanKindServer:
+			server = s
+		}
+	}
+	require.NotNil(t, client)
+	require.NotNil(t, server)
+	return client, server
+}
+
+func TestUnary(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	f := newFixture(t, []Option{WithServiceName("server")}, []Option{WithServiceName("client")})
+	res, err := f.ping.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("pass")))
+	require.NoError(t, err)
+	assert.Equal(t, "passed", res.Msg.GetValue())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	client, server := spansByKind(t, spans)
+	assert.Equal(t, client.SpanID(), server.ParentID())
+	assert.Equal(t, client.TraceID(), server.TraceID())
+
+	for _, s := range []mocktracer.Span{client, server} {
+		assert.Equal(t, ext.AppTypeRPC, s.Tag(ext.SpanType))
+		assert.Equal(t, pingProcedure, s.Tag(ext.ResourceName))
+		assert.Equal(t, pingProcedure, s.Tag(tagMethodName))
+		assert.Equal(t, methodKindUnary, s.Tag(tagMethodKind))
+		assert.Equal(t, "OK", s.Tag(tagCode))
+		assert.Equal(t, ext.RPCSystemConnectRPC, s.Tag(ext.RPCSystem))
+		assert.Equal(t, "test.v1.FixtureService", s.Tag(ext.RPCService))
+		assert.Equal(t, "Ping", s.Tag(ext.RPCMethod))
+		assert.Equal(t, pingProcedure, s.Tag(ext.GRPCFullMethod))
+		assert.Equal(t, componentName, s.Tag(ext.Component))
+		assert.Equal(t, connect.ProtocolConnect, s.Tag(tagProtocol))
+		assert.Nil(t, s.Tag(ext.Error))
+	}
+	assert.Equal(t, "grpc.client", client.OperationName())
+	assert.Equal(t, "client", client.Tag(ext.ServiceName))
+	assert.Equal(t, "127.0.0.1", client.Tag(ext.TargetHost))
+	assert.NotEmpty(t, client.Tag(ext.TargetPort))
+	assert.Equal(t, "grpc.server", server.OperationName())
+	assert.Equal(t, "server", server.Tag(ext.ServiceName))
+}
+
+func TestUnaryChildSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	f := newFixture(t, nil, nil)
+	_, err := f.ping.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("child")))
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "child", spans[0].OperationName())
+	_, server := spansByKind(t, spans[1:])
+	assert.Equal(t, server.SpanID(), spans[0].ParentID())
+}
+
+func TestUnaryError(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		opts      []Option
+		code      string
+		wantError bool
+	}{
+		{name: "invalid", code: "InvalidArgument", wantError: true},
+		{name: "canceled", code: "Canceled", wantError: false},
+		{name: "invalid", opts: []Option{NonErrorCodes(connect.CodeInvalidArgument)}, code: "InvalidArgument", wantError: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			f := newFixture(t, tc.opts, tc.opts)
+			_, err := f.ping.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(tc.name)))
+			require.Error(t, err)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 2)
+			for _, s := range spans {
+				assert.Equal(t, tc.code, s.Tag(tagCode))
+				assert.Equal(t, tc.wantError, s.Tag(ext.Error) != nil)
+			}
+		})
+	}
+}
+
+func TestServerStream(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	f := newFixture(t, nil, nil)
+	stream, err := f.serverStream.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("hello")))
+	require.NoError(t, err)
+	var n int
+	for stream.Receive() {
+		n++
+	}
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+	assert.Equal(t, 2, n)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	client, server := spansByKind(t, spans)
+	assert.Equal(t, client.SpanID(), server.ParentID())
+	for _, s := range spans {
+		assert.Equal(t, serverStreamProcedure, s.Tag(ext.ResourceName))
+		assert.Equal(t, methodKindServerStream, s.Tag(tagMethodKind))
+		assert.Equal(t, "OK", s.Tag(tagCode))
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	f := newFixture(t, nil, nil)
+	stream := f.clientStream.CallClientStream(context.Background())
+	require.NoError(t, stream.Send(wrapperspb.String("a")))
+	require.NoError(t, stream.Send(wrapperspb.String("b")))
+	res, err := stream.CloseAndReceive()
+	require.NoError(t, err)
+	assert.Equal(t, "b", res.Msg.GetValue())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	client, server := spansByKind(t, spans)
+	assert.Equal(t, client.SpanID(), server.ParentID())
+	for _, s := range spans {
+		assert.Equal(t, clientStreamProcedure, s.Tag(ext.ResourceName))
+		assert.Equal(t, methodKindClientStream, s.Tag(tagMethodKind))
+		assert.Equal(t, "OK", s.Tag(tagCode))
+	}
+}
+
+func TestUntracedMethods(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	opts := []Option{WithUntracedMethods(pingProcedure)}
+	f := newFixture(t, opts, opts)
+	_, err := f.ping.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("pass")))
+	require.NoError(t, err)
+	assert.Empty(t, mt.FinishedSpans())
+}
+
+func TestAnalyticsSettings(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	opts := []Option{WithAnalyticsRate(0.5)}
+	f := newFixture(t, opts, opts)
+	_, err := f.ping.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("pass")))
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	for _, s := range spans {
+		assert.Equal(t, 0.5, s.Tag(ext.EventSampleRate))
+	}
+}
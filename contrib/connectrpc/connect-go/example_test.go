@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package connect_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	connecttrace "github.com/nowfred/dd-trace-go/contrib/connectrpc/connect-go"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const procedure = "/acme.greet.v1.GreetService/Greet"
+
+func greet(_ context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+	return connect.NewResponse(wrapperspb.String("Hello, " + req.Msg.GetValue())), nil
+}
+
+func ExampleNewInterceptor_server() {
+	interceptor := connecttrace.NewInterceptor(connecttrace.WithServiceName("greet-service"))
+
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, greet, connect.WithInterceptors(interceptor)))
+	log.Fatal(http.ListenAndServe(":8080", mux))
+}
+
+func ExampleNewInterceptor_client() {
+	interceptor := connecttrace.NewInterceptor()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		http.DefaultClient,
+		"http://localhost:8080"+procedure,
+		connect.WithInterceptors(interceptor),
+	)
+	res, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("Jane")))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(res.Msg.GetValue())
+}
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package connect
+
+import (
+	"math"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/internal"
+	"github.com/nowfred/dd-trace-go/internal/globalconfig"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/namingschema"
+
+	"connectrpc.com/connect"
+)
+
+const (
+	defaultClientServiceName = "connect.client"
+	defaultServerServiceName = "connect.server"
+)
+
+type config struct {
+	clientServiceName string
+	serverServiceName func() string
+	clientSpanName    string
+	serverSpanName    string
+	analyticsRate     float64
+	nonErrorCodes     map[connect.Code]bool
+	untracedMethods   map[string]struct{}
+	spanOpts          []ddtrace.StartSpanOption
+}
+
+// Option specifies a configuration option for the interceptor.
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		analyticsRate: math.NaN(),
+		nonErrorCodes: map[connect.Code]bool{connect.CodeCanceled: true},
+	}
+	if internal.BoolEnv("DD_TRACE_CONNECT_ANALYTICS_ENABLED", false) {
+		cfg.analyticsRate = 1.0
+	}
+	cfg.clientServiceName = namingschema.ServiceNameOverrideV0(defaultClientServiceName, defaultClientServiceName)
+	// The server service name is fetched on every call when the interceptor is
+	// created before the tracer is started, as with the gRPC integration.
+	if globalconfig.ServiceName() != "" {
+		sn := namingschema.ServiceName(defaultServerServiceName)
+		cfg.serverServiceName = func() string { return sn }
+	} else {
+		log.Debug("contrib/connectrpc/connect-go: No global service name was detected, the service name will be fetched for every server span.")
+		cfg.serverServiceName = func() string { return namingschema.ServiceName(defaultServerServiceName) }
+	}
+	cfg.clientSpanName = namingschema.OpName(namingschema.GRPCClient)
+	cfg.serverSpanName = namingschema.OpName(namingschema.GRPCServer)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithServiceName sets the given service name for the client and server spans.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.clientServiceName = name
+		cfg.serverServiceName = func() string { return name }
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// NonErrorCodes determines the list of codes which will not be considered errors in instrumentation.
+// This call overrides the default handling of connect.CodeCanceled as a non-error.
+func NonErrorCodes(cs ...connect.Code) Option {
+	return func(cfg *config) {
+		cfg.nonErrorCodes = make(map[connect.Code]bool, len(cs))
+		for _, c := range cs {
+			cfg.nonErrorCodes[c] = true
+		}
+	}
+}
+
+// WithUntracedMethods specifies full procedure names, such as "/acme.foo.v1.FooService/Bar",
+// for which no spans will be created.
+func WithUntracedMethods(ms ...string) Option {
+	ums := make(map[string]struct{}, len(ms))
+	for _, e := range ms {
+		ums[e] = struct{}{}
+	}
+	return func(cfg *config) {
+		cfg.untracedMethods = ums
+	}
+}
+
+// WithSpanOptions defines a set of additional ddtrace.StartSpanOption to be added
+// to spans started by the integration.
+func WithSpanOptions(opts ...ddtrace.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
@@ -0,0 +1,24 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+)
+
+// checkQuery runs the AppSec SQL injection checks on the given query before it
+// runs. A *sharedsec.BlockedSQLQueryError is returned when the query was built
+// out of the request's user input and must not be run.
+func (tp *traceParams) checkQuery(ctx context.Context, query string) error {
+	if !appsec.Enabled() {
+		return nil
+	}
+	dbSystem, _ := normalizeDBSystem(tp.driverName)
+	return sharedsec.MonitorSQLQuery(ctx, query, dbSystem)
+}
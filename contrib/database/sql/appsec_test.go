@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sql
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pappsec "github.com/nowfred/dd-trace-go/appsec"
+	"github.com/nowfred/dd-trace-go/contrib/database/sql/internal"
+	httptrace "github.com/nowfred/dd-trace-go/contrib/net/http"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppSecSQLInjection(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/sqli.json")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	d := &internal.MockDriver{}
+	Register("mock-appsec", d)
+	db, err := Open("mock-appsec", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	var queryErr error
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		query := "SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'"
+		var rows interface{ Close() error }
+		if rows, queryErr = db.QueryContext(r.Context(), query); queryErr == nil {
+			rows.Close()
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name    string
+		param   string
+		blocked bool
+	}{
+		{name: "block", param: "' OR 1=1 --", blocked: true},
+		{name: "no-block", param: "bob"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			d.Executed = nil
+
+			res, err := http.Get(srv.URL + "/users?name=" + url.QueryEscape(tc.param))
+			require.NoError(t, err)
+			res.Body.Close()
+
+			var blockedErr *pappsec.BlockedSQLQueryError
+			require.Equal(t, tc.blocked, errors.As(queryErr, &blockedErr))
+			if !tc.blocked {
+				assert.Len(t, d.Executed, 1)
+				return
+			}
+			assert.Empty(t, d.Executed)
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+			var event string
+			for _, s := range mt.FinishedSpans() {
+				switch s.Tag(ext.SpanType) {
+				case ext.SpanTypeSQL:
+					assert.NotNil(t, s.Tag(ext.Error))
+				case ext.SpanTypeWeb:
+					event, _ = s.Tag("_dd.appsec.json").(string)
+				}
+			}
+			assert.True(t, strings.Contains(event, "sqli-001-001"))
+		})
+	}
+}
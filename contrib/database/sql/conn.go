@@ -132,6 +132,10 @@ func (tc *TracedConn) PrepareContext(ctx context.Context, query string) (stmt dr
 func (tc *TracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (r driver.Result, err error) {
 	start := time.Now()
 	if execContext, ok := tc.Conn.(driver.ExecerContext); ok {
+		if err := tc.checkQuery(ctx, query); err != nil {
+			tc.tryTrace(ctx, QueryTypeExec, query, start, err)
+			return nil, err
+		}
 		cquery, spanID := tc.injectComments(ctx, query, tc.cfg.dbmPropagationMode)
 		ctx, end := startTraceTask(ctx, QueryTypeExec)
 		defer end()
@@ -140,6 +144,10 @@ func (tc *TracedConn) ExecContext(ctx context.Context, query string, args []driv
 		return r, err
 	}
 	if execer, ok := tc.Conn.(driver.Execer); ok {
+		if err := tc.checkQuery(ctx, query); err != nil {
+			tc.tryTrace(ctx, QueryTypeExec, query, start, err)
+			return nil, err
+		}
 		dargs, err := namedValueToValue(args)
 		if err != nil {
 			return nil, err
@@ -176,6 +184,10 @@ func (tc *TracedConn) Ping(ctx context.Context) (err error) {
 func (tc *TracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
 	start := time.Now()
 	if queryerContext, ok := tc.Conn.(driver.QueryerContext); ok {
+		if err := tc.checkQuery(ctx, query); err != nil {
+			tc.tryTrace(ctx, QueryTypeQuery, query, start, err)
+			return nil, err
+		}
 		cquery, spanID := tc.injectComments(ctx, query, tc.cfg.dbmPropagationMode)
 		ctx, end := startTraceTask(ctx, QueryTypeQuery)
 		defer end()
@@ -184,6 +196,10 @@ func (tc *TracedConn) QueryContext(ctx context.Context, query string, args []dri
 		return rows, err
 	}
 	if queryer, ok := tc.Conn.(driver.Queryer); ok {
+		if err := tc.checkQuery(ctx, query); err != nil {
+			tc.tryTrace(ctx, QueryTypeQuery, query, start, err)
+			return nil, err
+		}
 		dargs, err := namedValueToValue(args)
 		if err != nil {
 			return nil, err
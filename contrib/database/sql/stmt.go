@@ -35,6 +35,10 @@ func (s *tracedStmt) Close() (err error) {
 // ExecContext is needed to implement the driver.StmtExecContext interface
 func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
 	start := time.Now()
+	if err := s.checkQuery(ctx, s.query); err != nil {
+		s.tryTrace(ctx, QueryTypeExec, s.query, start, err)
+		return nil, err
+	}
 	if stmtExecContext, ok := s.Stmt.(driver.StmtExecContext); ok {
 		ctx, end := startTraceTask(ctx, QueryTypeExec)
 		defer end()
@@ -61,6 +65,10 @@ func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue)
 // QueryContext is needed to implement the driver.StmtQueryContext interface
 func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
 	start := time.Now()
+	if err := s.checkQuery(ctx, s.query); err != nil {
+		s.tryTrace(ctx, QueryTypeQuery, s.query, start, err)
+		return nil, err
+	}
 	if stmtQueryContext, ok := s.Stmt.(driver.StmtQueryContext); ok {
 		ctx, end := startTraceTask(ctx, QueryTypeQuery)
 		defer end()
@@ -52,10 +52,11 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	route, _ := getRoute(r.TreeMux, w, req)
 	// pass r.TreeMux to avoid a circular reference panic on calling r.ServeHTTP
 	httptrace.TraceAndServe(r.TreeMux, w, req, &httptrace.ServeConfig{
-		Service:  r.config.serviceName,
-		Resource: resource,
-		SpanOpts: r.config.spanOpts,
-		Route:    route,
+		Service:       r.config.serviceName,
+		Resource:      resource,
+		SpanOpts:      r.config.spanOpts,
+		Route:         route,
+		IsStatusError: r.config.isStatusError,
 	})
 }
 
@@ -87,10 +88,11 @@ func (r *ContextRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	route, _ := getRoute(r.TreeMux, w, req)
 	// pass r.TreeMux to avoid a circular reference panic on calling r.ServeHTTP
 	httptrace.TraceAndServe(r.TreeMux, w, req, &httptrace.ServeConfig{
-		Service:  r.config.serviceName,
-		Resource: resource,
-		SpanOpts: r.config.spanOpts,
-		Route:    route,
+		Service:       r.config.serviceName,
+		Resource:      resource,
+		SpanOpts:      r.config.spanOpts,
+		Route:         route,
+		IsStatusError: r.config.isStatusError,
 	})
 }
 
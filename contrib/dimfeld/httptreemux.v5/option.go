@@ -21,6 +21,7 @@ type routerConfig struct {
 	serviceName   string
 	spanOpts      []ddtrace.StartSpanOption
 	resourceNamer func(*httptreemux.TreeMux, http.ResponseWriter, *http.Request) string
+	isStatusError func(statusCode int) bool
 }
 
 // RouterOption represents an option that can be passed to New.
@@ -52,3 +53,12 @@ func WithResourceNamer(namer func(router *httptreemux.TreeMux, w http.ResponseWr
 		cfg.resourceNamer = namer
 	}
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error. It defaults to the status codes
+// configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES, 5xx if unset.
+func WithStatusCheck(fn func(statusCode int) bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.isStatusError = fn
+	}
+}
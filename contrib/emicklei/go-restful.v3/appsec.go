@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package restful
+
+import (
+	"net/http"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// withAppSec processes the filter chain while monitoring the request with
+// AppSec. It returns the status code written to the client, which is the one of
+// the blocking response when the request was blocked, or 0 when none was
+// written.
+func withAppSec(req *restful.Request, resp *restful.Response, chain *restful.FilterChain, span tracer.Span) int {
+	rw := &statusResponseWriter{ResponseWriter: resp.ResponseWriter}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req.Request = r
+		// The handlers write the response through resp, which must write it through w for it to be monitored
+		resp.ResponseWriter = w
+		defer func() { resp.ResponseWriter = rw.ResponseWriter }()
+		chain.ProcessFilter(req, resp)
+	})
+	httpsec.WrapHandler(handler, span, req.PathParameters()).ServeHTTP(rw, req.Request)
+	return rw.status
+}
+
+// statusResponseWriter wraps the underlying http.ResponseWriter of the
+// restful response to allow retrieving its status code through a Status()
+// method, including when the response is written by AppSec.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// Status returns the status code that was written.
+func (w *statusResponseWriter) Status() int {
+	return w.status
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
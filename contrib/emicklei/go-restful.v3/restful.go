@@ -13,6 +13,7 @@ import (
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
 	"github.com/nowfred/dd-trace-go/internal/log"
 	"github.com/nowfred/dd-trace-go/internal/telemetry"
 
@@ -47,12 +48,20 @@ func FilterFunc(configOpts ...Option) restful.FilterFunction {
 		}
 		spanOpts = append(spanOpts, httptrace.HeaderTagsFromRequest(req.Request, cfg.headerTags))
 		span, ctx := httptrace.StartRequestSpan(req.Request, spanOpts...)
+		var status int
 		defer func() {
-			httptrace.FinishRequestSpan(span, resp.StatusCode(), tracer.WithError(resp.Error()))
+			if status == 0 {
+				status = resp.StatusCode()
+			}
+			httptrace.FinishRequestSpan(span, status, cfg.isStatusError, tracer.WithError(resp.Error()))
 		}()
 
 		// pass the span through the request context
 		req.Request = req.Request.WithContext(ctx)
+		if appsec.Enabled() {
+			status = withAppSec(req, resp, chain, span)
+			return
+		}
 		chain.ProcessFilter(req, resp)
 	}
 }
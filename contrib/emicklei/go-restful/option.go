@@ -8,6 +8,7 @@ package restful
 import (
 	"math"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -20,6 +21,7 @@ type config struct {
 	serviceName   string
 	analyticsRate float64
 	headerTags    *internal.LockMap
+	isStatusError func(statusCode int) bool
 }
 
 func newConfig() *config {
@@ -32,6 +34,7 @@ func newConfig() *config {
 		serviceName:   serviceName,
 		analyticsRate: rate,
 		headerTags:    globalconfig.HeaderTagMap(),
+		isStatusError: httptrace.IsServerError,
 	}
 }
 
@@ -78,3 +81,11 @@ func WithHeaderTags(headers []string) Option {
 		cfg.headerTags = internal.NewLockMap(headerTagsMap)
 	}
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error.
+func WithStatusCheck(fn func(statusCode int) bool) Option {
+	return func(cfg *config) {
+		cfg.isStatusError = fn
+	}
+}
@@ -26,9 +26,78 @@ func useAppSec(c *gin.Context, span tracer.Span) {
 	}
 	httpWrapper := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c.Request = r
+		if httpsec.IsResponseBodyMonitored(w) {
+			// The handlers write the response through c.Writer rather than w, which must be used for the response to
+			// be monitored
+			writer := c.Writer
+			c.Writer = &appsecResponseWriter{ResponseWriter: writer, monitor: w}
+			defer func() { c.Writer = writer }()
+		}
 		c.Next()
 	})
 	httpsec.WrapHandler(httpWrapper, span, params, func() {
 		c.Abort()
 	}).ServeHTTP(c.Writer, c.Request)
 }
+
+// appsecResponseWriter wraps the gin response writer to write the response
+// through the AppSec monitoring of httpsec.WrapHandler, which buffers it until
+// the handler operation is finished. The status code can still be changed by
+// the handlers until the first write of the response, as with gin. Written,
+// Size and Status report the response written to the monitoring, as it may
+// not be written to the gin response writer yet.
+type appsecResponseWriter struct {
+	gin.ResponseWriter
+	monitor http.ResponseWriter
+	written bool
+	size    int
+}
+
+// WriteHeaderNow forces the monitoring to write the status code.
+func (w *appsecResponseWriter) WriteHeaderNow() {
+	w.written = true
+	w.monitor.WriteHeader(w.ResponseWriter.Status())
+}
+
+// Write implements http.ResponseWriter.
+func (w *appsecResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	n, err := w.monitor.Write(b)
+	w.size += n
+	return n, err
+}
+
+// WriteString implements io.StringWriter.
+func (w *appsecResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written implements gin.ResponseWriter.
+func (w *appsecResponseWriter) Written() bool {
+	return w.written || w.ResponseWriter.Written()
+}
+
+// Size implements gin.ResponseWriter.
+func (w *appsecResponseWriter) Size() int {
+	if !w.written {
+		return w.ResponseWriter.Size()
+	}
+	return w.size
+}
+
+// Status implements gin.ResponseWriter, returning the status code written to
+// the monitoring once it was written.
+func (w *appsecResponseWriter) Status() int {
+	if s, ok := w.monitor.(interface{ Status() int }); ok && w.written {
+		return s.Status()
+	}
+	return w.ResponseWriter.Status()
+}
+
+// Flush implements http.Flusher when the monitoring does.
+func (w *appsecResponseWriter) Flush() {
+	w.WriteHeaderNow()
+	if f, ok := w.monitor.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -434,3 +434,39 @@ func TestBlocking(t *testing.T) {
 		}
 	})
 }
+
+// statusRecorder is a response recorder reporting its status code, as the
+// AppSec response monitoring does.
+type statusRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r statusRecorder) Status() int {
+	return r.Code
+}
+
+func TestAppSecResponseWriter(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	monitor := statusRecorder{httptest.NewRecorder()}
+	w := &appsecResponseWriter{ResponseWriter: c.Writer, monitor: monitor}
+
+	require.False(t, w.Written())
+	require.Equal(t, -1, w.Size())
+	w.WriteHeader(http.StatusCreated)
+	require.False(t, w.Written())
+	require.Equal(t, http.StatusCreated, w.Status())
+
+	n, err := w.WriteString("hello")
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.True(t, w.Written())
+	require.Equal(t, 5, w.Size())
+	require.Equal(t, http.StatusCreated, w.Status())
+	// The response is only written to the monitoring so far
+	require.False(t, c.Writer.Written())
+	require.Equal(t, "hello", monitor.Body.String())
+
+	// The status code can no longer be changed
+	w.WriteHeader(http.StatusTeapot)
+	require.Equal(t, http.StatusCreated, w.Status())
+}
@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -25,6 +26,7 @@ type config struct {
 	serviceName   string
 	ignoreRequest func(c *gin.Context) bool
 	headerTags    *internal.LockMap
+	isStatusError func(statusCode int) bool
 }
 
 func newConfig(serviceName string) *config {
@@ -41,6 +43,7 @@ func newConfig(serviceName string) *config {
 		serviceName:   serviceName,
 		ignoreRequest: func(_ *gin.Context) bool { return false },
 		headerTags:    globalconfig.HeaderTagMap(),
+		isStatusError: httptrace.IsServerError,
 	}
 }
 
@@ -110,3 +113,11 @@ func defaultResourceNamer(c *gin.Context) string {
 	}
 	return getName(c.Request, c)
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error.
+func WithStatusCheck(fn func(statusCode int) bool) Option {
+	return func(cfg *config) {
+		cfg.isStatusError = fn
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
@@ -40,7 +41,7 @@ func defaults(cfg *config) {
 		cfg.analyticsRate = globalconfig.AnalyticsRate()
 	}
 	cfg.headerTags = globalconfig.HeaderTagMap()
-	cfg.isStatusError = isServerError
+	cfg.isStatusError = httptrace.IsServerError
 	cfg.ignoreRequest = func(_ *http.Request) bool { return false }
 	cfg.modifyResourceName = func(s string) string { return s }
 	// for backward compatibility with modifyResourceName, initialize resourceName as nil.
@@ -93,10 +94,6 @@ func WithStatusCheck(fn func(statusCode int) bool) Option {
 	}
 }
 
-func isServerError(statusCode int) bool {
-	return statusCode >= 500 && statusCode < 600
-}
-
 // WithIgnoreRequest specifies a function to use for determining if the
 // incoming HTTP request tracing should be skipped.
 func WithIgnoreRequest(fn func(r *http.Request) bool) Option {
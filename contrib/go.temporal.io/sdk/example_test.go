@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package temporal_test
+
+import (
+	"log"
+
+	temporaltrace "github.com/nowfred/dd-trace-go/contrib/go.temporal.io/sdk"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// The interceptor traces the client calls, and the executions of the
+	// workers created from the client.
+	c, err := client.Dial(client.Options{
+		Interceptors: []interceptor.ClientInterceptor{temporaltrace.NewInterceptor()},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	w := worker.New(c, "task-queue", worker.Options{})
+	if err := w.Run(worker.InterruptCh()); err != nil {
+		log.Fatal(err)
+	}
+}
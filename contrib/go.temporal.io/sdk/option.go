@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package temporal
+
+import (
+	"math"
+
+	"github.com/nowfred/dd-trace-go/internal"
+)
+
+type config struct {
+	serviceName   string
+	analyticsRate float64
+	traceSignals  bool
+	traceQueries  bool
+}
+
+// Option represents an option that can be passed to NewInterceptor.
+type Option func(*config)
+
+func defaults(cfg *config) {
+	if internal.BoolEnv("DD_TRACE_TEMPORAL_ANALYTICS_ENABLED", false) {
+		cfg.analyticsRate = 1.0
+	} else {
+		cfg.analyticsRate = math.NaN()
+	}
+	cfg.traceSignals = true
+	cfg.traceQueries = true
+}
+
+// WithServiceName sets the given service name for the spans of the
+// interceptor. It defaults to the global service name.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithSignalTracing enables or disables the tracing of the signals sent and
+// handled. It is enabled by default.
+func WithSignalTracing(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.traceSignals = enabled
+	}
+}
+
+// WithQueryTracing enables or disables the tracing of the queries sent and
+// handled. It is enabled by default.
+func WithQueryTracing(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.traceQueries = enabled
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
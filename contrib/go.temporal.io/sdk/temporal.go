@@ -0,0 +1,273 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package temporal provides an interceptor to trace the clients and workers of
+// the Temporal Go SDK (https://github.com/temporalio/sdk-go).
+package temporal // import "github.com/nowfred/dd-trace-go/contrib/go.temporal.io/sdk"
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+const componentName = "go.temporal.io/sdk"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported(componentName)
+}
+
+// headerKey is the key of the Temporal headers holding the span context. It
+// is the one of the Datadog tracer of the Temporal Go SDK, so that both
+// tracers propagate the traces to each other.
+const headerKey = "_tracer-data"
+
+// spanContextKey is the key of the workflow contexts holding the span.
+type spanContextKey struct{}
+
+// NewInterceptor returns a Temporal interceptor tracing the workflows and
+// activities started, signaled and queried by the clients, and executed by
+// the workers, including the child workflows, with spans named after the
+// operation, such as temporal.StartWorkflow or temporal.RunActivity, and the
+// resource name of the workflow type, activity type, signal or query name.
+// The span context is propagated through the Temporal headers.
+//
+// No span is created when the workflow code is replayed by the workers, as the
+// spans of the replayed operations were created when they were first executed.
+//
+// The interceptor must be given to the client options, and it is then also
+// used by the workers created from the client:
+//
+//	c, err := client.Dial(client.Options{
+//		Interceptors: []interceptor.ClientInterceptor{temporaltrace.NewInterceptor()},
+//	})
+func NewInterceptor(opts ...Option) interceptor.Interceptor {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	log.Debug("contrib/go.temporal.io/sdk: Configuring Interceptor: %#v", cfg)
+	return &tracingInterceptor{
+		Interceptor: interceptor.NewTracingInterceptor(&tracerAdapter{cfg: cfg}),
+	}
+}
+
+// tracingInterceptor is the tracing interceptor of the Temporal SDK, whose
+// workflow inbound interceptor is skipped during replay.
+type tracingInterceptor struct {
+	interceptor.Interceptor
+}
+
+// InterceptWorkflow implements interceptor.WorkerInterceptor.
+func (i *tracingInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	return &workflowInboundInterceptor{
+		WorkflowInboundInterceptor: i.Interceptor.InterceptWorkflow(ctx, next),
+		next:                       next,
+	}
+}
+
+type workflowInboundInterceptor struct {
+	// WorkflowInboundInterceptor is the traced interceptor, whose Init method
+	// installs the traced workflow outbound interceptor. The outbound calls are
+	// not traced during replay by the Temporal SDK.
+	interceptor.WorkflowInboundInterceptor
+	next interceptor.WorkflowInboundInterceptor
+}
+
+// ExecuteWorkflow implements interceptor.WorkflowInboundInterceptor.
+func (w *workflowInboundInterceptor) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (any, error) {
+	if !workflow.IsReplaying(ctx) {
+		return w.WorkflowInboundInterceptor.ExecuteWorkflow(ctx, in)
+	}
+	// The workflow span was created by the worker that first executed the
+	// workflow. The operations following the replay are made children of the
+	// parent of the workflow span, so that they remain part of its trace.
+	if parent := workflowHeaderSpanContext(ctx); parent != nil {
+		ctx = workflow.WithValue(ctx, spanContextKey{}, &remoteSpan{SpanContext: parent})
+	}
+	return w.next.ExecuteWorkflow(ctx, in)
+}
+
+// HandleSignal implements interceptor.WorkflowInboundInterceptor.
+func (w *workflowInboundInterceptor) HandleSignal(ctx workflow.Context, in *interceptor.HandleSignalInput) error {
+	if workflow.IsReplaying(ctx) {
+		return w.next.HandleSignal(ctx, in)
+	}
+	return w.WorkflowInboundInterceptor.HandleSignal(ctx, in)
+}
+
+// workflowHeaderSpanContext returns the span context propagated through the
+// headers of the workflow, if any.
+func workflowHeaderSpanContext(ctx workflow.Context) ddtrace.SpanContext {
+	payload := interceptor.WorkflowHeader(ctx)[headerKey]
+	if payload == nil {
+		return nil
+	}
+	var data map[string]string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &data); err != nil {
+		return nil
+	}
+	sctx, err := tracer.Extract(tracer.TextMapCarrier(data))
+	if err != nil {
+		return nil
+	}
+	return sctx
+}
+
+// tracerAdapter implements the tracer of the tracing interceptor of the
+// Temporal SDK.
+type tracerAdapter struct {
+	interceptor.BaseTracer
+	cfg *config
+}
+
+// span is a span created by the tracer.
+type span struct {
+	ddtrace.Span
+}
+
+// Finish implements interceptor.TracerSpan.
+func (s *span) Finish(opts *interceptor.TracerFinishSpanOptions) {
+	var err error
+	if opts != nil && !workflow.IsContinueAsNewError(opts.Error) {
+		// Continuing a workflow as new is not a failure.
+		err = opts.Error
+	}
+	s.Span.Finish(tracer.WithError(err))
+}
+
+// remoteSpan is a span created by another process, read from the headers.
+type remoteSpan struct {
+	ddtrace.SpanContext
+}
+
+// Finish implements interceptor.TracerSpan.
+func (*remoteSpan) Finish(*interceptor.TracerFinishSpanOptions) {}
+
+// Options implements interceptor.Tracer.
+func (t *tracerAdapter) Options() interceptor.TracerOptions {
+	return interceptor.TracerOptions{
+		SpanContextKey:       spanContextKey{},
+		HeaderKey:            headerKey,
+		DisableSignalTracing: !t.cfg.traceSignals,
+		DisableQueryTracing:  !t.cfg.traceQueries,
+	}
+}
+
+// UnmarshalSpan implements interceptor.Tracer.
+func (*tracerAdapter) UnmarshalSpan(data map[string]string) (interceptor.TracerSpanRef, error) {
+	sctx, err := tracer.Extract(tracer.TextMapCarrier(data))
+	if err != nil {
+		// A missing or invalid span context must not fail the workflows.
+		return nil, nil
+	}
+	return &remoteSpan{SpanContext: sctx}, nil
+}
+
+// MarshalSpan implements interceptor.Tracer.
+func (*tracerAdapter) MarshalSpan(s interceptor.TracerSpan) (map[string]string, error) {
+	sctx := spanContext(s)
+	if sctx == nil {
+		return nil, nil
+	}
+	data := make(map[string]string)
+	if err := tracer.Inject(sctx, tracer.TextMapCarrier(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SpanFromContext implements interceptor.Tracer.
+func (*tracerAdapter) SpanFromContext(ctx context.Context) interceptor.TracerSpan {
+	s, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &span{Span: s}
+}
+
+// ContextWithSpan implements interceptor.Tracer.
+func (*tracerAdapter) ContextWithSpan(ctx context.Context, s interceptor.TracerSpan) context.Context {
+	if s, ok := s.(*span); ok {
+		return tracer.ContextWithSpan(ctx, s.Span)
+	}
+	return ctx
+}
+
+// SpanName implements interceptor.Tracer.
+func (*tracerAdapter) SpanName(options *interceptor.TracerStartSpanOptions) string {
+	return "temporal." + options.Operation
+}
+
+// StartSpan implements interceptor.Tracer.
+func (t *tracerAdapter) StartSpan(options *interceptor.TracerStartSpanOptions) (interceptor.TracerSpan, error) {
+	opts := []ddtrace.StartSpanOption{
+		tracer.ResourceName(options.Name),
+		tracer.Tag(ext.SpanKind, spanKind(options.Operation)),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Measured(),
+	}
+	if t.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(t.cfg.serviceName))
+	}
+	if parent := spanContext(options.Parent); parent != nil {
+		opts = append(opts, tracer.ChildOf(parent))
+	}
+	if !options.Time.IsZero() {
+		opts = append(opts, tracer.StartTime(options.Time))
+	}
+	if options.IdempotencyKey != "" {
+		// The workflow span is created again when the workflow task is retried,
+		// possibly by another worker. Its span ID is derived from the
+		// idempotency key so that it is deduplicated.
+		h := fnv.New64()
+		h.Write([]byte(options.IdempotencyKey))
+		opts = append(opts, tracer.WithSpanID(h.Sum64()))
+	}
+	for k, v := range options.Tags {
+		opts = append(opts, tracer.Tag(k, v))
+	}
+	if !math.IsNaN(t.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
+	}
+	return &span{Span: tracer.StartSpan(t.SpanName(options), opts...)}, nil
+}
+
+// spanContext returns the span context of the given span reference, if any.
+func spanContext(ref interceptor.TracerSpanRef) ddtrace.SpanContext {
+	switch ref := ref.(type) {
+	case *span:
+		return ref.Context()
+	case *remoteSpan:
+		return ref.SpanContext
+	default:
+		return nil
+	}
+}
+
+// spanKind returns the span kind of the given operation of the tracing
+// interceptor: the executions of workflows, activities, signals, queries and
+// updates are server spans, while their calls are client spans.
+func spanKind(operation string) string {
+	for _, prefix := range [...]string{"Run", "Handle", "Validate"} {
+		if strings.HasPrefix(operation, prefix) {
+			return ext.SpanKindServer
+		}
+	}
+	return ext.SpanKindClient
+}
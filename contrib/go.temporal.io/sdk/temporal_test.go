@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package temporal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+)
+
+func newTracerAdapter(opts ...Option) *tracerAdapter {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return &tracerAdapter{cfg: cfg}
+}
+
+func TestStartSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := newTracerAdapter(WithServiceName("worker"))
+	start := time.Now().Add(-time.Minute)
+	parent, err := tr.StartSpan(&interceptor.TracerStartSpanOptions{
+		Operation: "StartWorkflow",
+		Name:      "MyWorkflow",
+		Tags:      map[string]string{"temporalWorkflowID": "wf-1"},
+	})
+	require.NoError(t, err)
+	child, err := tr.StartSpan(&interceptor.TracerStartSpanOptions{
+		Parent:    parent,
+		Operation: "RunWorkflow",
+		Name:      "MyWorkflow",
+		Time:      start,
+	})
+	require.NoError(t, err)
+	child.Finish(&interceptor.TracerFinishSpanOptions{Error: errors.New("failed")})
+	parent.Finish(&interceptor.TracerFinishSpanOptions{})
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	run, startWorkflow := spans[0], spans[1]
+	assert.Equal(t, "temporal.StartWorkflow", startWorkflow.OperationName())
+	assert.Equal(t, "MyWorkflow", startWorkflow.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindClient, startWorkflow.Tag(ext.SpanKind))
+	assert.Equal(t, "wf-1", startWorkflow.Tag("temporalWorkflowID"))
+	assert.Equal(t, "worker", startWorkflow.Tag(ext.ServiceName))
+	assert.Equal(t, componentName, startWorkflow.Tag(ext.Component))
+	assert.Nil(t, startWorkflow.Tag(ext.Error))
+
+	assert.Equal(t, "temporal.RunWorkflow", run.OperationName())
+	assert.Equal(t, ext.SpanKindServer, run.Tag(ext.SpanKind))
+	assert.Equal(t, startWorkflow.SpanID(), run.ParentID())
+	assert.Equal(t, start, run.StartTime())
+	assert.NotNil(t, run.Tag(ext.Error))
+}
+
+func TestStartSpanIdempotencyKey(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := newTracerAdapter()
+	opts := &interceptor.TracerStartSpanOptions{Operation: "RunWorkflow", Name: "MyWorkflow", IdempotencyKey: "key"}
+	s1, _ := tr.StartSpan(opts)
+	s1.Finish(nil)
+	s2, _ := tr.StartSpan(opts)
+	s2.Finish(nil)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].SpanID(), spans[1].SpanID())
+}
+
+func TestMarshalSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := newTracerAdapter()
+	s, _ := tr.StartSpan(&interceptor.TracerStartSpanOptions{Operation: "StartActivity", Name: "MyActivity"})
+	defer s.Finish(nil)
+
+	data, err := tr.MarshalSpan(s)
+	require.NoError(t, err)
+	require.NotEmpty(t, data)
+	ref, err := tr.UnmarshalSpan(data)
+	require.NoError(t, err)
+	sctx := spanContext(ref)
+	require.NotNil(t, sctx)
+	assert.Equal(t, s.(*span).Context().SpanID(), sctx.SpanID())
+	assert.Equal(t, s.(*span).Context().TraceID(), sctx.TraceID())
+
+	// A missing span context is not an error
+	ref, err = tr.UnmarshalSpan(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, ref)
+}
+
+func TestContextWithSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := newTracerAdapter()
+	assert.Nil(t, tr.SpanFromContext(context.Background()))
+
+	root := tracer.StartSpan("root")
+	defer root.Finish()
+	ctx := tr.ContextWithSpan(context.Background(), &span{Span: root})
+	s := tr.SpanFromContext(ctx)
+	require.NotNil(t, s)
+	assert.Equal(t, root.Context().SpanID(), spanContext(s).SpanID())
+}
+
+func TestOptions(t *testing.T) {
+	opts := newTracerAdapter().Options()
+	assert.Equal(t, headerKey, opts.HeaderKey)
+	assert.False(t, opts.DisableSignalTracing)
+	assert.False(t, opts.DisableQueryTracing)
+
+	opts = newTracerAdapter(WithSignalTracing(false), WithQueryTracing(false)).Options()
+	assert.True(t, opts.DisableSignalTracing)
+	assert.True(t, opts.DisableQueryTracing)
+}
+
+func TestSpanKind(t *testing.T) {
+	for operation, kind := range map[string]string{
+		"StartWorkflow":      ext.SpanKindClient,
+		"SignalWorkflow":     ext.SpanKindClient,
+		"StartChildWorkflow": ext.SpanKindClient,
+		"StartActivity":      ext.SpanKindClient,
+		"RunWorkflow":        ext.SpanKindServer,
+		"RunActivity":        ext.SpanKindServer,
+		"HandleSignal":       ext.SpanKindServer,
+		"HandleQuery":        ext.SpanKindServer,
+		"ValidateUpdate":     ext.SpanKindServer,
+	} {
+		assert.Equal(t, kind, spanKind(operation), operation)
+	}
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package fiber
+
+import (
+	"context"
+
+	"github.com/nowfred/dd-trace-go/contrib/internal/fasthttptrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// withAppSec executes the next handlers while monitoring the request with
+// AppSec. The path parameters are only known when the middleware is used by
+// the matched route, rather than by the whole application.
+func withAppSec(c *fiber.Ctx, span tracer.Span) error {
+	var params map[string]string
+	if p := c.AllParams(); len(p) > 0 {
+		params = p
+	}
+	var err error
+	blocked := fasthttptrace.WithAppSec(c.Context(), c.UserContext(), span, params, func(ctx context.Context) {
+		c.SetUserContext(ctx)
+		err = c.Next()
+	})
+	// If the request was blocked or if the error is a monitoring one, the blocking response was written by AppSec.
+	// Don't return the error in these cases so that the fiber error handler doesn't overwrite it.
+	if _, ok := err.(*httpsec.MonitoringError); ok || blocked {
+		return nil
+	}
+	return err
+}
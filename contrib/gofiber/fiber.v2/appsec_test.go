@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package fiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppSec(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	appsec.Start()
+	defer appsec.Stop()
+
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	var called bool
+	app := fiber.New()
+	app.Get("/user/:id", Middleware(), func(c *fiber.Ctx) error {
+		called = true
+		return c.SendString("Hello " + c.Params("id") + "!\n")
+	})
+
+	for _, tc := range []struct {
+		name    string
+		id      string
+		blocked bool
+	}{
+		{name: "path-params-blocking", id: "$globals", blocked: true},
+		{name: "no-attack", id: "bob"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			called = false
+
+			res, err := app.Test(httptest.NewRequest("GET", "/user/"+url.PathEscape(tc.id), nil))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			finished := mt.FinishedSpans()
+			require.Len(t, finished, 1)
+			require.Equal(t, !tc.blocked, called)
+			if !tc.blocked {
+				require.Equal(t, http.StatusOK, res.StatusCode)
+				require.Equal(t, "Hello bob!\n", string(b))
+				return
+			}
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+			require.Equal(t, "403", finished[0].Tag("http.status_code"))
+			require.Equal(t, true, finished[0].Tag("appsec.blocked"))
+			event := finished[0].Tag("_dd.appsec.json")
+			require.Contains(t, event, "crs-933-130-block")
+			require.Contains(t, event, "server.request.path_params")
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
 	"github.com/nowfred/dd-trace-go/internal/log"
 	"github.com/nowfred/dd-trace-go/internal/telemetry"
 
@@ -73,7 +74,12 @@ func Middleware(opts ...Option) func(c *fiber.Ctx) error {
 		c.SetUserContext(ctx)
 
 		// pass the execution down the line
-		err := c.Next()
+		var err error
+		if appsec.Enabled() {
+			err = withAppSec(c, span)
+		} else {
+			err = c.Next()
+		}
 
 		span.SetTag(ext.ResourceName, cfg.resourceNamer(c))
 		span.SetTag(ext.HTTPRoute, c.Route().Path)
@@ -8,6 +8,7 @@ package fiber
 import (
 	"math"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
@@ -33,7 +34,7 @@ type Option func(*config)
 func defaults(cfg *config) {
 	cfg.serviceName = namingschema.ServiceName(defaultServiceName)
 	cfg.spanName = namingschema.OpName(namingschema.HTTPServer)
-	cfg.isStatusError = isServerError
+	cfg.isStatusError = httptrace.IsServerError
 	cfg.resourceNamer = defaultResourceNamer
 
 	if internal.BoolEnv("DD_TRACE_FIBER_ENABLED", false) {
@@ -101,7 +102,3 @@ func defaultResourceNamer(c *fiber.Ctx) string {
 	r := c.Route()
 	return r.Method + " " + r.Path
 }
-
-func isServerError(statusCode int) bool {
-	return statusCode >= 500 && statusCode < 600
-}
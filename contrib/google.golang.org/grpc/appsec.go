@@ -7,8 +7,10 @@ package grpc
 
 import (
 	"context"
+	"strings"
 
 	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
 	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
 	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/grpcsec"
 	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
@@ -139,3 +141,28 @@ func setClientIP(ctx context.Context, span ddtrace.Span, md metadata.MD) netip.A
 	}
 	return clientIP
 }
+
+// appsecOutboundRequest checks the destination of a client call performed by a
+// monitored request handler against the SSRF rules. A
+// *sharedsec.BlockedOutboundRequestError is returned when the call must not be
+// performed.
+func appsecOutboundRequest(ctx context.Context, cc *grpc.ClientConn, method string) error {
+	if !appsec.Enabled() || cc == nil {
+		return nil
+	}
+	return sharedsec.MonitorOutboundRequest(ctx, outboundURL(cc.Target(), method))
+}
+
+// outboundURL returns the URL of a call to method on the given client
+// connection target, using the grpc scheme and the target endpoint without its
+// resolver scheme and authority, such as grpc://localhost:50051/pkg.Service/Method
+// for the target dns:///localhost:50051.
+func outboundURL(target, method string) string {
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+		if _, endpoint, ok := strings.Cut(rest, "/"); ok {
+			target = endpoint
+		}
+	}
+	return "grpc://" + target + method
+}
@@ -8,10 +8,12 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	pappsec "github.com/nowfred/dd-trace-go/appsec"
 	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
@@ -355,6 +357,102 @@ func TestUserBlocking(t *testing.T) {
 	})
 }
 
+// Test that outbound gRPC requests performed by a monitored handler are blocked
+// when their destination matches the SSRF rules
+func TestBlockRequest(t *testing.T) {
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	rig, err := newAppsecRig(false)
+	require.NoError(t, err)
+	defer rig.Close()
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("user-id", "legit user", "block-request", "true"))
+	reply, err := rig.client.Ping(ctx, &FixtureRequest{Name: "hello"})
+	require.Nil(t, reply)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	finished := mt.FinishedSpans()
+	require.Len(t, finished, 1)
+	require.Equal(t, true, finished[0].Tag("appsec.blocked"))
+}
+
+func TestOutboundRequestBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/ssrf.json")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		target  string
+		blocked bool
+	}{
+		{name: "block", target: "169.254.169.254:80", blocked: true},
+		{name: "no-block", target: "127.0.0.1:1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			// The outbound connection is lazily established and never used
+			// when the call is blocked.
+			conn, err := grpc.Dial(tc.target, grpc.WithInsecure(), grpc.WithUnaryInterceptor(UnaryClientInterceptor()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			var outboundErr error
+			interceptor := UnaryServerInterceptor(WithServiceName("grpc"))
+			info := &grpc.UnaryServerInfo{FullMethod: "/grpc.Fixture/Ping"}
+			_, err = interceptor(context.Background(), &FixtureRequest{}, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+				defer cancel()
+				_, outboundErr = NewFixtureClient(conn).Ping(ctx, &FixtureRequest{})
+				return nil, outboundErr
+			})
+			require.Error(t, err)
+
+			var blockedErr *pappsec.BlockedOutboundRequestError
+			require.Equal(t, tc.blocked, errors.As(outboundErr, &blockedErr))
+			if !tc.blocked {
+				return
+			}
+			require.Equal(t, "grpc://169.254.169.254:80/grpc.Fixture/Ping", blockedErr.URL)
+
+			var event string
+			for _, s := range mt.FinishedSpans() {
+				if e, ok := s.Tag("_dd.appsec.json").(string); ok {
+					event = e
+				}
+			}
+			require.True(t, strings.Contains(event, "ssrf-001-001"))
+		})
+	}
+}
+
+func TestOutboundURL(t *testing.T) {
+	for _, tc := range []struct {
+		target string
+		want   string
+	}{
+		{target: "localhost:50051", want: "grpc://localhost:50051/pkg.Service/Method"},
+		{target: "dns:///localhost:50051", want: "grpc://localhost:50051/pkg.Service/Method"},
+		{target: "dns://8.8.8.8/example.com:443", want: "grpc://example.com:443/pkg.Service/Method"},
+		{target: "passthrough:///10.0.0.1:80", want: "grpc://10.0.0.1:80/pkg.Service/Method"},
+	} {
+		t.Run(tc.target, func(t *testing.T) {
+			require.Equal(t, tc.want, outboundURL(tc.target, "/pkg.Service/Method"))
+		})
+	}
+}
+
 func newAppsecRig(traceClient bool, interceptorOpts ...Option) (*appsecRig, error) {
 	interceptorOpts = append([]InterceptorOption{WithServiceName("grpc")}, interceptorOpts...)
 
@@ -427,6 +525,11 @@ func (s *appsecFixtureServer) StreamPing(stream Fixture_StreamPingServer) (err e
 }
 func (s *appsecFixtureServer) Ping(ctx context.Context, in *FixtureRequest) (*FixtureReply, error) {
 	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("block-request")) > 0 {
+		if err := pappsec.BlockRequest(ctx, pappsec.WithBlockingGRPCStatusCode(int(codes.PermissionDenied))); err != nil {
+			return nil, err
+		}
+	}
 	ids := md.Get("user-id")
 	if err := pappsec.SetUser(ctx, ids[0]); err != nil {
 		return nil, err
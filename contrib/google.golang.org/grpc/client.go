@@ -7,7 +7,10 @@ package grpc
 
 import (
 	"context"
+	"io"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/contrib/google.golang.org/internal/grpcutil"
 	"github.com/nowfred/dd-trace-go/ddtrace"
@@ -19,6 +22,7 @@ import (
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 type clientStream struct {
@@ -26,6 +30,25 @@ type clientStream struct {
 	ctx    context.Context
 	cfg    *config
 	method string
+
+	// serverStreams reports whether the server sends a stream of messages,
+	// the stream otherwise ending once its single message is received.
+	serverStreams bool
+	// onFinish, if set, is called once with the terminal error of the stream.
+	onFinish   func(err error)
+	finishOnce sync.Once
+}
+
+// finish reports the terminal error of the stream, as returned by RecvMsg or
+// SendMsg, to onFinish. io.EOF marks the successful end of the stream.
+func (cs *clientStream) finish(err error) {
+	if cs.onFinish == nil {
+		return
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	cs.finishOnce.Do(func() { cs.onFinish(err) })
 }
 
 func (cs *clientStream) Context() context.Context {
@@ -48,6 +71,9 @@ func (cs *clientStream) RecvMsg(m interface{}) (err error) {
 		defer func() { finishWithError(span, err, cs.cfg) }()
 	}
 	err = cs.ClientStream.RecvMsg(m)
+	if err != nil || !cs.serverStreams {
+		cs.finish(err)
+	}
 	return err
 }
 
@@ -67,6 +93,10 @@ func (cs *clientStream) SendMsg(m interface{}) (err error) {
 		defer func() { finishWithError(span, err, cs.cfg) }()
 	}
 	err = cs.ClientStream.SendMsg(m)
+	if err != nil && err != io.EOF {
+		// io.EOF means that the stream ended, with the status returned by RecvMsg
+		cs.finish(err)
+	}
 	return err
 }
 
@@ -78,8 +108,10 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, false)
 	log.Debug("contrib/google.golang.org/grpc: Configuring StreamClientInterceptor: %#v", cfg)
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
 		var methodKind string
 		if desc != nil {
 			switch {
@@ -91,8 +123,13 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 				methodKind = methodKindClientStream
 			}
 		}
+		_, untraced := cfg.untracedMethods[method]
+		recordCall := cfg.metrics != nil && !untraced
+		if recordCall {
+			ctx = contextWithCallMetrics(ctx)
+		}
 		var stream grpc.ClientStream
-		if _, ok := cfg.untracedMethods[method]; cfg.traceStreamCalls && !ok {
+		if cfg.traceStreamCalls && !untraced {
 			var (
 				span tracer.Span
 				err  error
@@ -105,6 +142,9 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 				})
 			if err != nil {
 				finishWithError(span, err, cfg)
+				if recordCall {
+					cfg.metrics.recordCall(method, err, time.Since(start))
+				}
 				return nil, err
 			}
 
@@ -126,18 +166,36 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 			// we're not tracing calls, so inject it if it's there
 			ctx = injectSpanIntoContext(ctx)
 
-			var err error
-			stream, err = streamer(ctx, desc, cc, method, opts...)
+			err := appsecOutboundRequest(ctx, cc, method)
+			if err == nil {
+				stream, err = streamer(ctx, desc, cc, method, opts...)
+			}
 			if err != nil {
+				if recordCall {
+					cfg.metrics.recordCall(method, err, time.Since(start))
+				}
 				return nil, err
 			}
 		}
-		return &clientStream{
-			ClientStream: stream,
-			cfg:          cfg,
-			method:       method,
-			ctx:          ctx,
-		}, nil
+		cs := &clientStream{
+			ClientStream:  stream,
+			cfg:           cfg,
+			method:        method,
+			ctx:           ctx,
+			serverStreams: desc != nil && desc.ServerStreams,
+		}
+		if recordCall {
+			// The call metrics are reported with the terminal error returned
+			// by RecvMsg or SendMsg, or when the call is canceled.
+			cs.onFinish = func(err error) { cfg.metrics.recordCall(method, err, time.Since(start)) }
+			go func() {
+				<-stream.Context().Done()
+				if err := ctx.Err(); err != nil {
+					cs.finish(status.FromContextError(err).Err())
+				}
+			}()
+		}
+		return cs, nil
 	}
 }
 
@@ -149,16 +207,25 @@ func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, false)
 	log.Debug("contrib/google.golang.org/grpc: Configuring UnaryClientInterceptor: %#v", cfg)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		if _, ok := cfg.untracedMethods[method]; ok {
+			if err := appsecOutboundRequest(ctx, cc, method); err != nil {
+				return err
+			}
 			return invoker(ctx, method, req, reply, cc, opts...)
 		}
+		start := time.Now()
+		if cfg.metrics != nil {
+			ctx = contextWithCallMetrics(ctx)
+		}
 		span, _, err := doClientRequest(ctx, cfg, method, methodKindUnary, cc, opts,
 			func(ctx context.Context, opts []grpc.CallOption) error {
 				return invoker(ctx, method, req, reply, cc, opts...)
 			})
 		finishWithError(span, err, cfg)
+		cfg.metrics.recordCall(method, err, time.Since(start))
 		return err
 	}
 }
@@ -191,6 +258,10 @@ func doClientRequest(
 	var p peer.Peer
 	opts = append(opts, grpc.Peer(&p))
 
+	if err := appsecOutboundRequest(ctx, cc, method); err != nil {
+		return span, ctx, err
+	}
+
 	handlerCtx := injectSpanIntoContext(ctx)
 	err := handler(handlerCtx, opts)
 
@@ -75,8 +75,9 @@ func startSpanFromContext(
 	return tracer.StartSpanFromContext(ctx, operation, opts...)
 }
 
-// finishWithError applies finish option and a tag with gRPC status code, disregarding OK, EOF and Canceled errors.
-func finishWithError(span ddtrace.Span, err error, cfg *config) {
+// normalizeError returns the status code of err, and err or nil if it should not
+// be reported as an error according to cfg.
+func normalizeError(err error, cfg *config) (codes.Code, error) {
 	if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
 		err = nil
 	}
@@ -84,6 +85,12 @@ func finishWithError(span ddtrace.Span, err error, cfg *config) {
 	if errcode == codes.OK || cfg.nonErrorCodes[errcode] {
 		err = nil
 	}
+	return errcode, err
+}
+
+// finishWithError applies finish option and a tag with gRPC status code, disregarding OK, EOF and Canceled errors.
+func finishWithError(span ddtrace.Span, err error, cfg *config) {
+	errcode, err := normalizeError(err, cfg)
 	span.SetTag(tagCode, errcode.String())
 	if e, ok := status.FromError(err); ok && cfg.withErrorDetailTags {
 		for i, d := range e.Details() {
@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/internal/log"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+// Metric name suffixes, prefixed with grpc.server or grpc.client.
+const (
+	metricRequests     = ".requests"
+	metricErrors       = ".errors"
+	metricLatency      = ".latency"
+	metricRequestSize  = ".request.size"
+	metricResponseSize = ".response.size"
+)
+
+// rpcMetrics reports RED metrics of the calls made or received to DogStatsD.
+// A nil *rpcMetrics reports nothing.
+type rpcMetrics struct {
+	client statsd.ClientInterface
+	server bool
+	cfg    *config
+}
+
+// newRPCMetrics returns the rpcMetrics of a client or server configured with
+// cfg, or nil if metrics are disabled.
+func newRPCMetrics(cfg *config, server bool) *rpcMetrics {
+	if cfg.statsdClient == nil {
+		return nil
+	}
+	return &rpcMetrics{client: cfg.statsdClient, server: server, cfg: cfg}
+}
+
+// recordCall reports the call to method, which finished with err after d.
+func (m *rpcMetrics) recordCall(method string, err error, d time.Duration) {
+	if m == nil {
+		return
+	}
+	code, err := normalizeError(err, m.cfg)
+	tags := m.tags(method, tagCode+":"+code.String())
+	m.incr(metricRequests, tags)
+	if err != nil {
+		m.incr(metricErrors, tags)
+	}
+	m.distribution(metricLatency, float64(d)/float64(time.Millisecond), m.tags(method))
+}
+
+// recordPayload reports the size of a message sent or received for method.
+// in reports whether the message was received.
+func (m *rpcMetrics) recordPayload(method string, in bool, size int) {
+	if m == nil {
+		return
+	}
+	// The server receives requests, and the client responses.
+	name := metricResponseSize
+	if in == m.server {
+		name = metricRequestSize
+	}
+	m.distribution(name, float64(size), m.tags(method))
+}
+
+func (m *rpcMetrics) tags(method string, extra ...string) []string {
+	return append([]string{
+		"service:" + m.cfg.serviceName(),
+		tagMethodName + ":" + method,
+	}, extra...)
+}
+
+func (m *rpcMetrics) name(suffix string) string {
+	if m.server {
+		return "grpc.server" + suffix
+	}
+	return "grpc.client" + suffix
+}
+
+func (m *rpcMetrics) incr(suffix string, tags []string) {
+	if err := m.client.Incr(m.name(suffix), tags, 1); err != nil {
+		log.Debug("contrib/google.golang.org/grpc: Failed to report %s: %v", m.name(suffix), err)
+	}
+}
+
+func (m *rpcMetrics) distribution(suffix string, value float64, tags []string) {
+	if err := m.client.Distribution(m.name(suffix), value, tags, 1); err != nil {
+		log.Debug("contrib/google.golang.org/grpc: Failed to report %s: %v", m.name(suffix), err)
+	}
+}
+
+type statsMetricsKey struct{}
+
+// statsMetrics holds the metrics reported by a stats handler for an RPC.
+type statsMetrics struct {
+	method string
+	// recordCall reports whether the stats handler reports the call metrics,
+	// which are otherwise reported by an interceptor.
+	recordCall bool
+}
+
+// contextWithStatsMetrics returns a copy of ctx holding the full method of the
+// RPC, for the stats handlers to report it with the metrics. The call metrics
+// are only reported by the stats handler when no interceptor reports them yet.
+func contextWithStatsMetrics(ctx context.Context, method string) context.Context {
+	ctx = context.WithValue(ctx, statsMetricsKey{}, &statsMetrics{
+		method:     method,
+		recordCall: !callMetricsReported(ctx),
+	})
+	return contextWithCallMetrics(ctx)
+}
+
+func statsMetricsFromContext(ctx context.Context) (*statsMetrics, bool) {
+	m, ok := ctx.Value(statsMetricsKey{}).(*statsMetrics)
+	return m, ok
+}
+
+type callMetricsKey struct{}
+
+// contextWithCallMetrics returns a copy of ctx marking that the metrics of the
+// call are reported. When WithMetrics is given to both the interceptors and the
+// stats handlers, the call metrics are then reported once, by the first of them
+// to handle the RPC: the interceptor on the client side, and the stats handler
+// on the server side.
+func contextWithCallMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callMetricsKey{}, true)
+}
+
+// callMetricsReported reports whether the metrics of the call of ctx are
+// already reported.
+func callMetricsReported(ctx context.Context) bool {
+	reported, _ := ctx.Value(callMetricsKey{}).(bool)
+	return reported
+}
@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// testStatsdClient records the metrics reported with Incr and Distribution.
+type testStatsdClient struct {
+	statsd.NoOpClient
+
+	mu      sync.Mutex
+	metrics []string
+}
+
+func (c *testStatsdClient) Incr(name string, tags []string, _ float64) error {
+	c.record(name, tags)
+	return nil
+}
+
+func (c *testStatsdClient) Distribution(name string, _ float64, tags []string, _ float64) error {
+	c.record(name, tags)
+	return nil
+}
+
+func (c *testStatsdClient) record(name string, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = append(c.metrics, name+" "+strings.Join(tags, ","))
+}
+
+// waitFor waits until the given metrics were reported, and returns all the
+// metrics reported so far, sorted.
+func (c *testStatsdClient) waitFor(t *testing.T, n int) []string {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.metrics) >= n
+	}, time.Second, 10*time.Millisecond)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	metrics := append([]string(nil), c.metrics...)
+	sort.Strings(metrics)
+	return metrics
+}
+
+func TestMetricsInterceptors(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := new(testStatsdClient)
+	rig, err := newRig(true, WithServiceName("grpc"), WithMetrics(client))
+	require.NoError(t, err)
+	defer rig.Close()
+
+	_, err = rig.client.Ping(context.Background(), &FixtureRequest{Name: "invalid"})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{
+		"grpc.client.errors service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:InvalidArgument",
+		"grpc.client.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.client.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:InvalidArgument",
+		"grpc.server.errors service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:InvalidArgument",
+		"grpc.server.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:InvalidArgument",
+	}, client.waitFor(t, 6))
+}
+
+func TestMetricsStatsHandlers(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	serverClient, clientClient := new(testStatsdClient), new(testStatsdClient)
+	rig, err := newRigWithInterceptors(
+		[]grpc.ServerOption{grpc.StatsHandler(NewServerStatsHandler(WithServiceName("grpc"), WithMetrics(serverClient)))},
+		[]grpc.DialOption{grpc.WithInsecure(), grpc.WithStatsHandler(NewClientStatsHandler(WithServiceName("grpc"), WithMetrics(clientClient)))},
+	)
+	require.NoError(t, err)
+	defer rig.Close()
+
+	_, err = rig.client.Ping(context.Background(), &FixtureRequest{Name: "pass"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"grpc.server.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.request.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:OK",
+		"grpc.server.response.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+	}, serverClient.waitFor(t, 4))
+	assert.Equal(t, []string{
+		"grpc.client.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.client.request.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.client.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:OK",
+		"grpc.client.response.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+	}, clientClient.waitFor(t, 4))
+}
+
+func TestMetricsStream(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := new(testStatsdClient)
+	rig, err := newRig(true, WithServiceName("grpc"), WithMetrics(client))
+	require.NoError(t, err)
+	defer rig.Close()
+
+	stream, err := rig.client.StreamPing(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&FixtureRequest{Name: "invalid"}))
+	_, err = stream.Recv()
+	require.Error(t, err)
+
+	// the failure of the stream is reported, not the cancelation of its context
+	assert.Equal(t, []string{
+		"grpc.client.errors service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:InvalidArgument",
+		"grpc.client.latency service:grpc,grpc.method.name:/grpc.Fixture/StreamPing",
+		"grpc.client.requests service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:InvalidArgument",
+		"grpc.server.errors service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:InvalidArgument",
+		"grpc.server.latency service:grpc,grpc.method.name:/grpc.Fixture/StreamPing",
+		"grpc.server.requests service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:InvalidArgument",
+	}, client.waitFor(t, 6))
+
+	t.Run("canceled", func(t *testing.T) {
+		client.mu.Lock()
+		client.metrics = nil
+		client.mu.Unlock()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := rig.client.StreamPing(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&FixtureRequest{Name: "pass"}))
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		cancel()
+
+		// the cancelation is not an error
+		assert.Equal(t, []string{
+			"grpc.client.latency service:grpc,grpc.method.name:/grpc.Fixture/StreamPing",
+			"grpc.client.requests service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:Canceled",
+			"grpc.server.latency service:grpc,grpc.method.name:/grpc.Fixture/StreamPing",
+			"grpc.server.requests service:grpc,grpc.method.name:/grpc.Fixture/StreamPing,grpc.code:Canceled",
+		}, client.waitFor(t, 4))
+	})
+}
+
+func TestMetricsInterceptorsAndStatsHandlers(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := new(testStatsdClient)
+	opts := []Option{WithServiceName("grpc"), WithMetrics(client)}
+	rig, err := newRigWithInterceptors(
+		[]grpc.ServerOption{
+			grpc.UnaryInterceptor(UnaryServerInterceptor(opts...)),
+			grpc.StatsHandler(NewServerStatsHandler(opts...)),
+		},
+		[]grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithUnaryInterceptor(UnaryClientInterceptor(opts...)),
+			grpc.WithStatsHandler(NewClientStatsHandler(opts...)),
+		},
+	)
+	require.NoError(t, err)
+	defer rig.Close()
+
+	_, err = rig.client.Ping(context.Background(), &FixtureRequest{Name: "pass"})
+	require.NoError(t, err)
+
+	// the calls are counted once
+	metrics := client.waitFor(t, 8)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, metrics, client.waitFor(t, 8))
+	assert.Equal(t, []string{
+		"grpc.client.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.client.request.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.client.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:OK",
+		"grpc.client.response.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.latency service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.request.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+		"grpc.server.requests service:grpc,grpc.method.name:/grpc.Fixture/Ping,grpc.code:OK",
+		"grpc.server.response.size service:grpc,grpc.method.name:/grpc.Fixture/Ping",
+	}, metrics)
+}
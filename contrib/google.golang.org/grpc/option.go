@@ -13,6 +13,7 @@ import (
 	"github.com/nowfred/dd-trace-go/internal/log"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
 
+	"github.com/DataDog/datadog-go/v5/statsd"
 	"google.golang.org/grpc/codes"
 )
 
@@ -40,6 +41,8 @@ type config struct {
 	withErrorDetailTags bool
 	spanOpts            []ddtrace.StartSpanOption
 	tags                map[string]interface{}
+	statsdClient        statsd.ClientInterface
+	metrics             *rpcMetrics
 }
 
 // InterceptorOption represents an option that can be passed to the grpc unary
@@ -220,3 +223,20 @@ func WithSpanOptions(opts ...ddtrace.StartSpanOption) Option {
 		cfg.spanOpts = append(cfg.spanOpts, opts...)
 	}
 }
+
+// WithMetrics enables reporting metrics to DogStatsD using the given client,
+// such as one created with github.com/DataDog/datadog-go/v5/statsd.New.
+// Every call is counted in grpc.server.requests or grpc.client.requests,
+// calls failing with an error code in grpc.server.errors or grpc.client.errors,
+// and their duration is reported in milliseconds in the grpc.server.latency or
+// grpc.client.latency distribution, all tagged with the method and the status
+// code where applicable. When used with the stats handlers, the size of the
+// messages is also reported in the request.size and response.size
+// distributions. As metrics are reported for every call, they are not affected
+// by trace sampling. When given to both the interceptors and the stats handlers,
+// each call is still counted once.
+func WithMetrics(client statsd.ClientInterface) Option {
+	return func(cfg *config) {
+		cfg.statsdClient = client
+	}
+}
@@ -7,6 +7,7 @@ package grpc
 
 import (
 	"context"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
@@ -86,12 +87,16 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, true)
 	log.Debug("contrib/google.golang.org/grpc: Configuring StreamServerInterceptor: %#v", cfg)
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		ctx := ss.Context()
 		// if we've enabled call tracing, create a span
 		_, im := cfg.ignoredMethods[info.FullMethod]
 		_, um := cfg.untracedMethods[info.FullMethod]
+		if cfg.metrics != nil && !im && !um && !callMetricsReported(ctx) {
+			defer func(start time.Time) { cfg.metrics.recordCall(info.FullMethod, err, time.Since(start)) }(time.Now())
+		}
 		if cfg.traceStreamCalls && !im && !um {
 			var span ddtrace.Span
 			span, ctx = startSpanFromContext(
@@ -135,6 +140,7 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, true)
 	log.Debug("contrib/google.golang.org/grpc: Configuring UnaryServerInterceptor: %#v", cfg)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		_, im := cfg.ignoredMethods[info.FullMethod]
@@ -142,6 +148,9 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 		if im || um {
 			return handler(ctx, req)
 		}
+		start := time.Now()
+		// the call metrics may be reported by a stats handler
+		recordCall := !callMetricsReported(ctx)
 		span, ctx := startSpanFromContext(
 			ctx,
 			info.FullMethod,
@@ -159,6 +168,9 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 		}
 		resp, err := handler(ctx, req)
 		finishWithError(span, err, cfg)
+		if recordCall {
+			cfg.metrics.recordCall(info.FullMethod, err, time.Since(start))
+		}
 		return resp, err
 	}
 }
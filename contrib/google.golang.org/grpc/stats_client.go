@@ -21,6 +21,7 @@ func NewClientStatsHandler(opts ...Option) stats.Handler {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, false)
 	return &clientStatsHandler{
 		cfg: cfg,
 	}
@@ -39,11 +40,26 @@ func (h *clientStatsHandler) TagRPC(ctx context.Context, rti *stats.RPCTagInfo)
 		spanOpts...,
 	)
 	ctx = injectSpanIntoContext(ctx)
+	if h.cfg.metrics != nil {
+		ctx = contextWithStatsMetrics(ctx, rti.FullMethodName)
+	}
 	return ctx
 }
 
 // HandleRPC processes the RPC ending event by finishing the span from the context.
 func (h *clientStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if m, ok := statsMetricsFromContext(ctx); ok {
+		switch rs := rs.(type) {
+		case *stats.InPayload:
+			h.cfg.metrics.recordPayload(m.method, true, rs.Length)
+		case *stats.OutPayload:
+			h.cfg.metrics.recordPayload(m.method, false, rs.Length)
+		case *stats.End:
+			if m.recordCall {
+				h.cfg.metrics.recordCall(m.method, rs.Error, rs.EndTime.Sub(rs.BeginTime))
+			}
+		}
+	}
 	span, ok := tracer.SpanFromContext(ctx)
 	if !ok {
 		return
@@ -21,6 +21,7 @@ func NewServerStatsHandler(opts ...Option) stats.Handler {
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	cfg.metrics = newRPCMetrics(cfg, true)
 	return &serverStatsHandler{
 		cfg: cfg,
 	}
@@ -44,11 +45,26 @@ func (h *serverStatsHandler) TagRPC(ctx context.Context, rti *stats.RPCTagInfo)
 		h.cfg.serviceName,
 		spanOpts...,
 	)
+	if h.cfg.metrics != nil {
+		ctx = contextWithStatsMetrics(ctx, rti.FullMethodName)
+	}
 	return ctx
 }
 
 // HandleRPC processes the RPC ending event by finishing the span from the context.
 func (h *serverStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if m, ok := statsMetricsFromContext(ctx); ok {
+		switch rs := rs.(type) {
+		case *stats.InPayload:
+			h.cfg.metrics.recordPayload(m.method, true, rs.Length)
+		case *stats.OutPayload:
+			h.cfg.metrics.recordPayload(m.method, false, rs.Length)
+		case *stats.End:
+			if m.recordCall {
+				h.cfg.metrics.recordCall(m.method, rs.Error, rs.EndTime.Sub(rs.BeginTime))
+			}
+		}
+	}
 	span, ok := tracer.SpanFromContext(ctx)
 	if !ok {
 		return
@@ -29,6 +29,7 @@ type routerConfig struct {
 	ignoreRequest func(*http.Request) bool
 	queryParams   bool
 	headerTags    *internal.LockMap
+	isStatusError func(statusCode int) bool
 }
 
 // RouterOption represents an option that can be passed to NewRouter.
@@ -140,3 +141,12 @@ func WithQueryParams() RouterOption {
 		cfg.queryParams = true
 	}
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error. It defaults to the status codes
+// configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES, 5xx if unset.
+func WithStatusCheck(fn func(statusCode int) bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.isStatusError = fn
+	}
+}
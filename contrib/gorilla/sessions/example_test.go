@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sessions_test
+
+import (
+	"net/http"
+
+	sessionstrace "github.com/nowfred/dd-trace-go/contrib/gorilla/sessions"
+	httptrace "github.com/nowfred/dd-trace-go/contrib/net/http"
+
+	"github.com/gorilla/sessions"
+)
+
+// To track the user logins and the authenticated users, wrap the session
+// store used by the traced request handlers.
+func Example() {
+	store := sessionstrace.WrapStore(sessions.NewCookieStore([]byte("secret")), sessionstrace.WithUserIDKey("user_id"))
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		// ... authenticate the user
+		session, _ := store.Get(r, "session")
+		session.Values["user_id"] = r.FormValue("username")
+		if err := session.Save(r, w); err != nil {
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+	http.ListenAndServe(":8080", mux)
+}
@@ -0,0 +1,25 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sessions
+
+type config struct {
+	userIDKey any
+}
+
+// Option represents an option that can be passed to WrapStore.
+type Option func(*config)
+
+func defaults(cfg *config) {
+	cfg.userIDKey = "user_id"
+}
+
+// WithUserIDKey sets the key of the session value holding the user ID.
+// Defaults to "user_id".
+func WithUserIDKey(key any) Option {
+	return func(cfg *config) {
+		cfg.userIDKey = key
+	}
+}
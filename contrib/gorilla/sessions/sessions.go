@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package sessions provides an AppSec adapter of the gorilla/sessions package
+// (https://github.com/gorilla/sessions) tracking the user logins and the
+// authenticated users out of the sessions.
+package sessions // import "github.com/nowfred/dd-trace-go/contrib/gorilla/sessions"
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"github.com/gorilla/sessions"
+)
+
+const componentName = "gorilla/sessions"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("github.com/gorilla/sessions")
+}
+
+// WrapStore returns the given session store along with the automated user
+// events tracking when AppSec is enabled with the env var
+// DD_APPSEC_AUTOMATED_USER_EVENTS_TRACKING set to safe or extended:
+//   - Saving a session whose user ID value changed since the request's session
+//     was loaded is reported as a user login success event. The session isn't
+//     saved and an error is returned when the user is blocked.
+//   - The user ID value of the loaded sessions is set as the authenticated user
+//     of the request, which is blocked when the user is in your denylist.
+//
+// The user IDs are anonymized in safe mode. The requests must be traced by
+// one of the HTTP tracing middlewares of the contrib packages.
+func WrapStore(store sessions.Store, opts ...Option) sessions.Store {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return &appsecStore{Store: store, cfg: cfg}
+}
+
+type appsecStore struct {
+	sessions.Store
+	cfg *config
+}
+
+// Get returns the session cached in the request registry, created with New.
+func (s *appsecStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the session loaded by the wrapped store, whose store is s so
+// that saving it goes through s. The returned error is the blocking error of
+// sharedsec.MonitorUser when the session user is blocked, in which case the
+// handler must return as soon as possible and let the tracing middleware send
+// the blocking response.
+func (s *appsecStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	loaded, err := s.Store.New(r, name)
+	if loaded == nil {
+		return nil, err
+	}
+	session := sessions.NewSession(s, name)
+	session.ID = loaded.ID
+	session.Values = loaded.Values
+	session.Options = loaded.Options
+	session.IsNew = loaded.IsNew
+	uid := s.cfg.userID(session)
+	loadedUserIDs(r)[session] = uid
+	if uid != "" {
+		if mode := appsec.UserEventsMode(); mode.Enabled() {
+			uid = mode.UserID(uid)
+			if span, ok := tracer.SpanFromContext(r.Context()); ok {
+				trace.SetAutoUserTags(span, uid, string(mode))
+			}
+			if blockErr := sharedsec.MonitorUser(r.Context(), uid); blockErr != nil {
+				return session, blockErr
+			}
+		}
+	}
+	return session, err
+}
+
+// Save saves the session with the wrapped store, after reporting a user login
+// success event when its user ID changed since it was loaded by New.
+func (s *appsecStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if mode := appsec.UserEventsMode(); mode.Enabled() {
+		uid := s.cfg.userID(session)
+		if loaded, ok := loadedUserIDs(r)[session]; uid != "" && (!ok || loaded != uid) {
+			uid = mode.UserID(uid)
+			if span, ok := tracer.SpanFromContext(r.Context()); ok {
+				trace.SetAutoUserLoginSuccessTags(span, uid, string(mode))
+			}
+			if err := sharedsec.MonitorUser(r.Context(), uid); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.Store.Save(r, w, session); err != nil {
+		return err
+	}
+	loadedUserIDs(r)[session] = s.cfg.userID(session)
+	return nil
+}
+
+// loadedUserIDsKey is the request context key of the user IDs of the sessions
+// loaded during the request.
+type loadedUserIDsKey struct{}
+
+// loadedUserIDs returns the user IDs of the sessions loaded during the request,
+// keyed by session. As the sessions registry does, the map is stored in the
+// request context the first time it is needed.
+func loadedUserIDs(r *http.Request) map[*sessions.Session]string {
+	ctx := r.Context()
+	if ids, ok := ctx.Value(loadedUserIDsKey{}).(map[*sessions.Session]string); ok {
+		return ids
+	}
+	ids := make(map[*sessions.Session]string)
+	*r = *r.WithContext(context.WithValue(ctx, loadedUserIDsKey{}, ids))
+	return ids
+}
+
+// userID returns the user ID value of the session, or an empty string when
+// there is none.
+func (cfg *config) userID(session *sessions.Session) string {
+	switch v := session.Values[cfg.userIDKey].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
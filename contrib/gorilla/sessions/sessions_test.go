@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httptrace "github.com/nowfred/dd-trace-go/contrib/net/http"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	appsecconfig "github.com/nowfred/dd-trace-go/internal/appsec/config"
+
+	"github.com/gorilla/sessions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEvents(t *testing.T) {
+	t.Setenv(appsecconfig.EnvUserEventsTracking, "safe")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	store := WrapStore(sessions.NewCookieStore([]byte("secret")), WithUserIDKey("uid"))
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		session, err := store.Get(r, "session")
+		require.NoError(t, err)
+		session.Values["uid"] = r.URL.Query().Get("user")
+		require.NoError(t, session.Save(r, w))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, err := store.Get(r, "session")
+		require.NoError(t, err)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	anonymized := appsecconfig.UserEventsModeSafe.UserID("user")
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	// Log in a first time
+	res, err := http.Get(srv.URL + "/login?user=user")
+	require.NoError(t, err)
+	res.Body.Close()
+	cookies := res.Cookies()
+	require.NotEmpty(t, cookies)
+
+	// Send an authenticated request
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/", nil)
+	require.NoError(t, err)
+	req.AddCookie(cookies[0])
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	// Log in again as the same user
+	req, err = http.NewRequest(http.MethodGet, srv.URL+"/login?user=user", nil)
+	require.NoError(t, err)
+	req.AddCookie(cookies[0])
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	require.Equal(t, true, spans[0].Tag("appsec.events.users.login.success.track"))
+	require.Equal(t, "safe", spans[0].Tag("_dd.appsec.events.users.login.success.auto.mode"))
+	require.Equal(t, anonymized, spans[0].Tag("usr.id"))
+	require.Nil(t, spans[1].Tag("appsec.events.users.login.success.track"))
+	require.Equal(t, anonymized, spans[1].Tag("usr.id"))
+	require.Nil(t, spans[2].Tag("appsec.events.users.login.success.track"))
+}
@@ -15,7 +15,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	ddtracer "github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -51,6 +53,9 @@ type Tracer struct {
 
 var _ tracer.Tracer = (*Tracer)(nil)
 
+// trivialResolversKey is the context key of the summary of the trivial resolvers of the query.
+type trivialResolversKey struct{}
+
 // TraceQuery traces a GraphQL query.
 func (t *Tracer) TraceQuery(ctx context.Context, queryString, operationName string, variables map[string]interface{}, _ map[string]*introspection.Type) (context.Context, tracer.QueryFinishFunc) {
 	opts := []ddtrace.StartSpanOption{
@@ -69,6 +74,14 @@ func (t *Tracer) TraceQuery(ctx context.Context, queryString, operationName stri
 		opts = append(opts, ddtracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
 	}
 	span, ctx := ddtracer.StartSpanFromContext(ctx, t.cfg.querySpanName, opts...)
+	if metrics, ok := graphqltrace.ParseQueryMetrics(queryString, operationName, variables); ok {
+		metrics.SetTags(span)
+	}
+	var trivial *graphqltrace.TrivialResolvers
+	if t.cfg.summarizeTrivial {
+		trivial = graphqltrace.NewTrivialResolvers(t.cfg.trivialThreshold)
+		ctx = context.WithValue(ctx, trivialResolversKey{}, trivial)
+	}
 
 	ctx, request := graphqlsec.StartRequestOperation(ctx, nil, span, graphqlsec.RequestOperationArgs{
 		RawQuery:      queryString,
@@ -91,6 +104,16 @@ func (t *Tracer) TraceQuery(ctx context.Context, queryString, operationName stri
 		default:
 			err = fmt.Errorf("%s (and %d more errors)", errs[0], n-1)
 		}
+		if len(errs) > 0 {
+			paths := make([][]any, len(errs))
+			for i, e := range errs {
+				paths[i] = e.Path
+			}
+			graphqltrace.SetErrorTags(span, paths)
+		}
+		if trivial != nil {
+			trivial.Finish(span, ddtracer.ServiceName(t.cfg.serviceName), ddtracer.Tag(ext.Component, componentName))
+		}
 		defer span.Finish(ddtracer.WithError(err))
 		defer request.Finish(graphqlsec.RequestOperationRes{Error: err})
 		query.Finish(graphqlsec.ExecutionOperationRes{Error: err})
@@ -102,22 +125,10 @@ func (t *Tracer) TraceField(ctx context.Context, _, typeName, fieldName string,
 	if t.cfg.omitTrivial && trivial {
 		return ctx, func(queryError *errors.QueryError) {}
 	}
-	opts := []ddtrace.StartSpanOption{
-		ddtracer.ServiceName(t.cfg.serviceName),
-		ddtracer.Tag(tagGraphqlField, fieldName),
-		ddtracer.Tag(tagGraphqlType, typeName),
-		ddtracer.Tag(ext.Component, componentName),
-		ddtracer.Measured(),
-	}
-	if t.cfg.traceVariables {
-		for key, value := range arguments {
-			opts = append(opts, ddtracer.Tag(fmt.Sprintf("%s.%s", tagGraphqlVariables, key), value))
-		}
-	}
-	if !math.IsNaN(t.cfg.analyticsRate) {
-		opts = append(opts, ddtracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
+	if summary, ok := ctx.Value(trivialResolversKey{}).(*graphqltrace.TrivialResolvers); ok && trivial {
+		return t.traceTrivialField(ctx, summary, typeName, fieldName, arguments)
 	}
-	span, ctx := ddtracer.StartSpanFromContext(ctx, "graphql.field", opts...)
+	span, ctx := ddtracer.StartSpanFromContext(ctx, "graphql.field", t.fieldSpanOptions(typeName, fieldName, arguments)...)
 
 	ctx, field := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), span, graphqlsec.ResolveOperationArgs{
 		TypeName:  typeName,
@@ -138,6 +149,54 @@ func (t *Tracer) TraceField(ctx context.Context, _, typeName, fieldName string,
 	}
 }
 
+// traceTrivialField traces the trivial field access without creating its span upfront. It is only traced with its
+// own span when it is slower than the threshold or fails, and summarized otherwise.
+func (t *Tracer) traceTrivialField(ctx context.Context, summary *graphqltrace.TrivialResolvers, typeName, fieldName string, arguments map[string]interface{}) (context.Context, tracer.FieldFinishFunc) {
+	parent, _ := ddtracer.SpanFromContext(ctx)
+	start := time.Now()
+	ctx, field := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), parent, graphqlsec.ResolveOperationArgs{
+		TypeName:  typeName,
+		FieldName: fieldName,
+		Arguments: arguments,
+		Trivial:   true,
+	})
+
+	return ctx, func(err *errors.QueryError) {
+		field.Finish(graphqlsec.ResolveOperationRes{Error: err})
+		finish := time.Now()
+		// must explicitly check for nil, see issue golang/go#22729
+		if err == nil && summary.Add(start, finish) {
+			return
+		}
+		opts := append(t.fieldSpanOptions(typeName, fieldName, arguments), ddtracer.StartTime(start))
+		span, _ := ddtracer.StartSpanFromContext(ctx, "graphql.field", opts...)
+		if err != nil {
+			span.Finish(ddtracer.FinishTime(finish), ddtracer.WithError(err))
+		} else {
+			span.Finish(ddtracer.FinishTime(finish))
+		}
+	}
+}
+
+func (t *Tracer) fieldSpanOptions(typeName, fieldName string, arguments map[string]interface{}) []ddtrace.StartSpanOption {
+	opts := []ddtrace.StartSpanOption{
+		ddtracer.ServiceName(t.cfg.serviceName),
+		ddtracer.Tag(tagGraphqlField, fieldName),
+		ddtracer.Tag(tagGraphqlType, typeName),
+		ddtracer.Tag(ext.Component, componentName),
+		ddtracer.Measured(),
+	}
+	if t.cfg.traceVariables {
+		for key, value := range arguments {
+			opts = append(opts, ddtracer.Tag(fmt.Sprintf("%s.%s", tagGraphqlVariables, key), value))
+		}
+	}
+	if !math.IsNaN(t.cfg.analyticsRate) {
+		opts = append(opts, ddtracer.Tag(ext.EventSampleRate, t.cfg.analyticsRate))
+	}
+	return opts
+}
+
 // NewTracer creates a new Tracer.
 func NewTracer(opts ...Option) tracer.Tracer {
 	cfg := new(config)
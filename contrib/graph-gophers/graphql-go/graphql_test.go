@@ -6,11 +6,14 @@
 package graphql
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/contrib/internal/lists"
 	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
@@ -27,6 +30,7 @@ type testResolver struct{}
 
 func (*testResolver) Hello() string                    { return "Hello, world!" }
 func (*testResolver) HelloNonTrivial() (string, error) { return "Hello, world!", nil }
+func (*testResolver) HelloError() (*string, error)     { return nil, errors.New("hello error") }
 
 const testServerSchema = `
 	schema {
@@ -35,6 +39,7 @@ const testServerSchema = `
 	type Query {
 		hello: String!
 		helloNonTrivial: String!
+		helloError: String
 	}
 `
 
@@ -134,6 +139,73 @@ func Test(t *testing.T) {
 	})
 }
 
+func TestTrivialResolversSummary(t *testing.T) {
+	makeRequest := func(opts ...Option) []mocktracer.Span {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		srv := newTestServer(opts...)
+		defer srv.Close()
+		resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"query": "{ hello, helloNonTrivial }"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return mt.FinishedSpans()
+	}
+	t.Run("summarized", func(t *testing.T) {
+		spans := makeRequest(WithTrivialResolversSummary(time.Hour))
+		require.Len(t, spans, 3)
+		// The order of the spans isn't deterministic.
+		var summary, field, root mocktracer.Span
+		for _, s := range spans {
+			switch s.OperationName() {
+			case graphqltrace.TrivialResolversSpanName:
+				summary = s
+			case "graphql.field":
+				field = s
+			default:
+				root = s
+			}
+		}
+		require.NotNil(t, summary)
+		require.NotNil(t, field)
+		require.NotNil(t, root)
+		assert.Equal(t, "helloNonTrivial", field.Tag(tagGraphqlField))
+		assert.Equal(t, root.SpanID(), summary.ParentID())
+		assert.Equal(t, 1, summary.Tag(graphqltrace.MetricTrivialResolversCount))
+		assert.Equal(t, "graph-gophers/graphql-go", summary.Tag(ext.Component))
+	})
+	t.Run("slow", func(t *testing.T) {
+		spans := makeRequest(WithTrivialResolversSummary(0))
+		require.Len(t, spans, 3)
+		var fields []string
+		for _, s := range spans {
+			if s.OperationName() == "graphql.field" {
+				fields = append(fields, s.Tag(tagGraphqlField).(string))
+			}
+		}
+		assert.ElementsMatch(t, []string{"hello", "helloNonTrivial"}, fields)
+	})
+}
+
+func TestQueryMetrics(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	srv := newTestServer()
+	defer srv.Close()
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"query": "{ hello, helloError }"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	root := spans[2]
+	assert.Equal(t, "graphql.request", root.OperationName())
+	assert.Equal(t, 1, root.Tag(graphqltrace.MetricQueryDepth))
+	assert.Equal(t, 2, root.Tag(graphqltrace.MetricQueryFields))
+	assert.Equal(t, 2, root.Tag(graphqltrace.MetricQueryComplexity))
+	assert.Equal(t, 1, root.Tag(graphqltrace.MetricErrorsCount))
+	assert.Equal(t, "helloError", root.Tag(graphqltrace.TagErrorsPaths))
+}
+
 func TestAnalyticsSettings(t *testing.T) {
 	assertRate := func(t *testing.T, mt mocktracer.Tracer, rate interface{}, opts ...Option) {
 		srv := newTestServer(opts...)
@@ -7,6 +7,7 @@ package graphql
 
 import (
 	"math"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -20,6 +21,9 @@ type config struct {
 	analyticsRate  float64
 	omitTrivial    bool
 	traceVariables bool
+
+	summarizeTrivial bool
+	trivialThreshold time.Duration
 }
 
 // Option represents an option that can be used customize the Tracer.
@@ -81,3 +85,17 @@ func WithTraceVariables() Option {
 		cfg.traceVariables = true
 	}
 }
+
+// WithTrivialResolversSummary aggregates the fields marked as trivial resolved
+// faster than the given threshold into a single graphql.resolvers.trivial span
+// per query holding their count and total duration. The trivial fields as slow
+// as the threshold, or failing, are still traced with their own span. This
+// reduces the number of spans of wide queries while keeping slow resolvers
+// visible. Unlike WithOmitTrivial, the trivial fields remain monitored by
+// Threat Detection.
+func WithTrivialResolversSummary(threshold time.Duration) Option {
+	return func(cfg *config) {
+		cfg.summarizeTrivial = true
+		cfg.trivialThreshold = threshold
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -67,6 +69,7 @@ type contextData struct {
 	variables     map[string]any
 	query         string
 	operationName string
+	trivial       *graphqltrace.TrivialResolvers
 }
 
 // finish closes the top-level request operation, as well as the server span.
@@ -97,6 +100,13 @@ func (i datadogExtension) Init(ctx context.Context, params *graphql.Params) cont
 		tracer.Tag(ext.Component, componentName),
 		tracer.Measured(),
 	)
+	if metrics, ok := graphqltrace.ParseQueryMetrics(params.RequestString, params.OperationName, params.VariableValues); ok {
+		metrics.SetTags(span)
+	}
+	var trivial *graphqltrace.TrivialResolvers
+	if i.config.summarizeTrivial {
+		trivial = graphqltrace.NewTrivialResolvers(i.config.trivialThreshold)
+	}
 	ctx, request := graphqlsec.StartRequestOperation(ctx, nil, span, graphqlsec.RequestOperationArgs{
 		RawQuery:      params.RequestString,
 		Variables:     params.VariableValues,
@@ -108,6 +118,7 @@ func (i datadogExtension) Init(ctx context.Context, params *graphql.Params) cont
 		variables:     params.VariableValues,
 		serverSpan:    span,
 		requestOp:     request,
+		trivial:       trivial,
 	})
 }
 
@@ -199,9 +210,19 @@ func (i datadogExtension) ExecutionDidStart(ctx context.Context) (context.Contex
 	})
 	return ctx, func(result *graphql.Result) {
 		err := toError(result.Errors)
+		if len(result.Errors) > 0 {
+			paths := make([][]any, len(result.Errors))
+			for i, e := range result.Errors {
+				paths[i] = e.Path
+			}
+			graphqltrace.SetErrorTags(data.serverSpan, paths)
+		}
 		defer func() {
 			defer data.finish(result.Data, err)
 			span.Finish(tracer.WithError(err))
+			if data.trivial != nil {
+				data.trivial.Finish(data.serverSpan, tracer.ServiceName(i.config.serviceName), tracer.Tag(ext.Component, componentName))
+			}
 		}()
 		op.Finish(graphqlsec.ExecutionOperationRes{Data: result.Data, Error: err})
 	}
@@ -222,6 +243,45 @@ func (i datadogExtension) ResolveFieldDidStart(ctx context.Context, info *graphq
 	default:
 		operationName = info.FieldName
 	}
+	if data, _ := ctx.Value(contextKey{}).(contextData); data.trivial != nil && isTrivial(info) {
+		return i.resolveTrivialField(ctx, data.trivial, info, operationName)
+	}
+	span, ctx := tracer.StartSpanFromContext(ctx, spanResolve, i.resolveSpanOptions(info, operationName)...)
+	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), span, graphqlsec.ResolveOperationArgs{
+		TypeName:  info.ParentType.Name(),
+		FieldName: info.FieldName,
+		Arguments: collectArguments(info),
+	})
+	return ctx, func(result any, err error) {
+		defer span.Finish(tracer.WithError(err))
+		op.Finish(graphqlsec.ResolveOperationRes{Error: err, Data: result})
+	}
+}
+
+// resolveTrivialField resolves the trivial field without creating its span upfront. It is only traced with its own
+// span when it is slower than the threshold or fails, and summarized otherwise.
+func (i datadogExtension) resolveTrivialField(ctx context.Context, trivial *graphqltrace.TrivialResolvers, info *graphql.ResolveInfo, operationName string) (context.Context, graphql.ResolveFieldFinishFunc) {
+	parent, _ := tracer.SpanFromContext(ctx)
+	start := time.Now()
+	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), parent, graphqlsec.ResolveOperationArgs{
+		TypeName:  info.ParentType.Name(),
+		FieldName: info.FieldName,
+		Arguments: collectArguments(info),
+		Trivial:   true,
+	})
+	return ctx, func(result any, err error) {
+		op.Finish(graphqlsec.ResolveOperationRes{Error: err, Data: result})
+		finish := time.Now()
+		if err == nil && trivial.Add(start, finish) {
+			return
+		}
+		opts := append(i.resolveSpanOptions(info, operationName), tracer.StartTime(start))
+		span, _ := tracer.StartSpanFromContext(ctx, spanResolve, opts...)
+		span.Finish(tracer.FinishTime(finish), tracer.WithError(err))
+	}
+}
+
+func (i datadogExtension) resolveSpanOptions(info *graphql.ResolveInfo, operationName string) []ddtrace.StartSpanOption {
 	opts := []ddtrace.StartSpanOption{
 		tracer.ServiceName(i.config.serviceName),
 		spanTagKind,
@@ -238,16 +298,18 @@ func (i datadogExtension) ResolveFieldDidStart(ctx context.Context, info *graphq
 	if !math.IsNaN(i.config.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, i.config.analyticsRate))
 	}
-	span, ctx := tracer.StartSpanFromContext(ctx, spanResolve, opts...)
-	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.FromContext[*graphqlsec.ExecutionOperation](ctx), span, graphqlsec.ResolveOperationArgs{
-		TypeName:  info.ParentType.Name(),
-		FieldName: info.FieldName,
-		Arguments: collectArguments(info),
-	})
-	return ctx, func(result any, err error) {
-		defer span.Finish(tracer.WithError(err))
-		op.Finish(graphqlsec.ResolveOperationRes{Error: err, Data: result})
+	return opts
+}
+
+// isTrivial returns true when the field is resolved by the default resolver of graphql-go, which returns the value
+// of the corresponding property of the source object.
+func isTrivial(info *graphql.ResolveInfo) bool {
+	obj, ok := info.ParentType.(*graphql.Object)
+	if !ok {
+		return false
 	}
+	field, ok := obj.Fields()[info.FieldName]
+	return ok && field.Resolve == nil
 }
 
 // HasResult returns if the extension wants to add data to the result
@@ -6,9 +6,12 @@
 package graphql
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/graphqltrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
 
@@ -112,6 +115,10 @@ func Test(t *testing.T) {
 			hasOperationName("graphql.server"),
 			hasTag(ext.ResourceName, "graphql.server"),
 			hasTag(ext.Component, "graphql-go/graphql"),
+			hasTag(graphqltrace.MetricQueryDepth, 1),
+			hasTag(graphqltrace.MetricQueryFields, 2),
+			hasTag(graphqltrace.MetricQueryComplexity, 2),
+			hasNoTag(graphqltrace.MetricErrorsCount),
 		)
 	})
 
@@ -210,6 +217,67 @@ func Test(t *testing.T) {
 	})
 }
 
+func TestTrivialResolversSummary(t *testing.T) {
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			// hello is resolved by the default resolver from the root value
+			"hello": {
+				Type: graphql.String,
+			},
+			"helloError": {
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return nil, errors.New("hello error")
+				},
+			},
+		},
+	})
+	do := func(t *testing.T, threshold time.Duration) (*graphql.Result, []mocktracer.Span) {
+		schema, err := NewSchema(graphql.SchemaConfig{Query: rootQuery}, WithTrivialResolversSummary(threshold))
+		require.NoError(t, err)
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		resp := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ hello, helloError }`,
+			RootObject:    map[string]any{"hello": "Hello, world!"},
+		})
+		return resp, mt.FinishedSpans()
+	}
+
+	t.Run("summarized", func(t *testing.T) {
+		resp, spans := do(t, time.Hour)
+		require.Len(t, resp.Errors, 1)
+		require.Len(t, spans, 6)
+		assertSpanMatches(t, spans[2],
+			hasOperationName("graphql.resolve"),
+			hasTag(tagGraphqlField, "helloError"),
+		)
+		assertSpanMatches(t, spans[3], hasOperationName("graphql.execute"))
+		summary, server := spans[4], spans[5]
+		assertSpanMatches(t, summary,
+			hasOperationName(graphqltrace.TrivialResolversSpanName),
+			hasTag(graphqltrace.MetricTrivialResolversCount, 1),
+			hasTag(ext.Component, "graphql-go/graphql"),
+		)
+		assert.Equal(t, server.SpanID(), summary.ParentID())
+		assertSpanMatches(t, server,
+			hasOperationName("graphql.server"),
+			hasTag(graphqltrace.MetricErrorsCount, 1),
+			hasTag(graphqltrace.TagErrorsPaths, "helloError"),
+		)
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		_, spans := do(t, 0)
+		require.Len(t, spans, 6)
+		fields := []string{"hello", "helloError"}
+		assertSpanMatches(t, spans[2], hasOperationName("graphql.resolve"), hasTagFrom(tagGraphqlField, &fields, nil))
+		assertSpanMatches(t, spans[3], hasOperationName("graphql.resolve"), hasTagFrom(tagGraphqlField, &fields, nil))
+	})
+}
+
 type spanMatcher func(*testing.T, mocktracer.Span)
 
 func assertSpanMatches(t *testing.T, span mocktracer.Span, assertions ...spanMatcher) {
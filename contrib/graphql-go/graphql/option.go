@@ -7,6 +7,7 @@ package graphql
 
 import (
 	"math"
+	"time"
 
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -17,6 +18,9 @@ const defaultServiceName = "graphql.server"
 type config struct {
 	serviceName   string
 	analyticsRate float64
+
+	summarizeTrivial bool
+	trivialThreshold time.Duration
 }
 
 type Option func(*config)
@@ -59,3 +63,16 @@ func WithServiceName(name string) Option {
 		cfg.serviceName = name
 	}
 }
+
+// WithTrivialResolversSummary aggregates the fields resolved by the default
+// resolver faster than the given threshold into a single
+// graphql.resolvers.trivial span per query holding their count and total
+// duration. The trivial fields as slow as the threshold, or failing, are still
+// traced with their own span. This reduces the number of spans of wide queries
+// while keeping slow resolvers visible.
+func WithTrivialResolversSummary(threshold time.Duration) Option {
+	return func(cfg *config) {
+		cfg.summarizeTrivial = true
+		cfg.trivialThreshold = threshold
+	}
+}
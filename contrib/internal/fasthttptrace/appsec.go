@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package fasthttptrace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+	"github.com/nowfred/dd-trace-go/internal/log"
+
+	"github.com/valyala/fasthttp"
+)
+
+// WithAppSec monitors the fasthttp request handled by next with AppSec, by
+// adapting the fasthttp request and response to the net/http ones expected by
+// httpsec.WrapHandler. The context passed to next is derived from ctx and
+// holds the AppSec monitoring of the request, which is also made available
+// through the user values of fctx so that fctx can be used as the context of
+// the AppSec SDK functions. Blocking responses are written to fctx.Response,
+// and the returned value reports whether the request was blocked.
+func WithAppSec(fctx *fasthttp.RequestCtx, ctx context.Context, span tracer.Span, pathParams map[string]string, next func(ctx context.Context)) (blocked bool) {
+	r, err := newHTTPRequest(ctx, fctx)
+	if err != nil {
+		log.Debug("appsec: could not monitor the fasthttp request: %v", err)
+		next(ctx)
+		return false
+	}
+	rw := &responseWriter{fctx: fctx}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fctx.SetUserValue(listener.ContextKey{}, r.Context().Value(listener.ContextKey{}))
+		next(r.Context())
+		rw.readHeader()
+		if httpsec.IsResponseBodyMonitored(w) && !fctx.Response.IsBodyStream() {
+			// Write the response through w for it to be buffered and inspected
+			body := append([]byte(nil), fctx.Response.Body()...)
+			status := fctx.Response.StatusCode()
+			fctx.Response.ResetBody()
+			w.WriteHeader(status)
+			w.Write(body)
+		}
+	})
+	httpsec.WrapHandler(handler, span, pathParams, func() {
+		blocked = true
+	}).ServeHTTP(rw, r)
+	return blocked
+}
+
+// newHTTPRequest returns the net/http request corresponding to the fasthttp
+// one, without its body. Contrary to fasthttpadaptor.ConvertRequest, the
+// strings are copied so that they can safely outlive the fasthttp request.
+func newHTTPRequest(ctx context.Context, fctx *fasthttp.RequestCtx) (*http.Request, error) {
+	requestURI := string(fctx.RequestURI())
+	r, err := http.NewRequestWithContext(ctx, string(fctx.Method()), requestURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.RequestURI = requestURI
+	r.Host = string(fctx.Host())
+	r.RemoteAddr = fctx.RemoteAddr().String()
+	r.TLS = fctx.TLSConnectionState()
+	fctx.Request.Header.VisitAll(func(k, v []byte) {
+		r.Header.Add(string(k), string(v))
+	})
+	// As with net/http, the Host header is removed from the request headers
+	r.Header.Del("Host")
+	return r, nil
+}
+
+// responseWriter is the http.ResponseWriter writing the response to the
+// fasthttp response. It allows httpsec.WrapHandler to read the response status
+// code and headers, and to replace the response with a blocking one.
+type responseWriter struct {
+	fctx        *fasthttp.RequestCtx
+	header      http.Header
+	wroteHeader bool
+}
+
+// Header implements http.ResponseWriter.
+func (w *responseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+// readHeader reads the headers of the fasthttp response written by the request
+// handler.
+func (w *responseWriter) readHeader() {
+	header := w.Header()
+	w.fctx.Response.Header.VisitAll(func(k, v []byte) {
+		key := string(k)
+		if key == fasthttp.HeaderContentLength {
+			// fasthttp computes it out of the response body
+			return
+		}
+		header.Add(key, string(v))
+	})
+}
+
+// WriteHeader implements http.ResponseWriter. The fasthttp response is reset
+// and replaced by the response status code and headers.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.fctx.Response.Reset()
+	for k, values := range w.header {
+		for _, v := range values {
+			w.fctx.Response.Header.Add(k, v)
+		}
+	}
+	w.fctx.Response.SetStatusCode(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.fctx.Response.AppendBody(b)
+	return len(b), nil
+}
+
+// Status returns the status code of the fasthttp response.
+func (w *responseWriter) Status() int {
+	return w.fctx.Response.StatusCode()
+}
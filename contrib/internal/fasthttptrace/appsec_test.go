@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package fasthttptrace
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	testlib "github.com/nowfred/dd-trace-go/internal/appsec/_testlib"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func newTestRequestCtx() *fasthttp.RequestCtx {
+	var req fasthttp.Request
+	req.Header.SetMethod("POST")
+	req.SetRequestURI("/path?query=value")
+	req.Header.SetHost("example.com")
+	req.Header.Set("My-Header", "my-value")
+	req.Header.SetCookie("my-cookie", "my-cookie-value")
+	var fctx fasthttp.RequestCtx
+	fctx.Init(&req, &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 1234}, nil)
+	return &fctx
+}
+
+func TestWithAppSec(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		monitorBody   bool
+		blockAtStart  bool
+		blockAtFinish bool
+		status        int
+		body          string
+	}{
+		{
+			name:   "not-blocked",
+			status: http.StatusCreated,
+			body:   `{"key":"value"}`,
+		},
+		{
+			name:        "not-blocked-monitored",
+			monitorBody: true,
+			status:      http.StatusCreated,
+			body:        `{"key":"value"}`,
+		},
+		{
+			name:         "blocked-at-start",
+			blockAtStart: true,
+			status:       http.StatusForbidden,
+		},
+		{
+			name:          "blocked-at-finish",
+			monitorBody:   true,
+			blockAtFinish: true,
+			status:        http.StatusForbidden,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := dyngo.NewRootOperation()
+			dyngo.SwapRootOperation(root)
+			defer dyngo.SwapRootOperation(dyngo.NewRootOperation())
+
+			var (
+				args httpsec.HandlerOperationArgs
+				res  httpsec.HandlerOperationRes
+			)
+			root.On(httpsec.OnHandlerOperationStart(func(op *httpsec.Operation, a httpsec.HandlerOperationArgs) {
+				args = a
+				if tc.monitorBody {
+					op.MonitorResponseBody(1024)
+				}
+				if tc.blockAtStart {
+					op.EmitData(sharedsec.NewBlockRequestAction(http.StatusForbidden, 0, "json"))
+				}
+				op.On(httpsec.OnHandlerOperationFinish(func(op *httpsec.Operation, r httpsec.HandlerOperationRes) {
+					res = r
+					if tc.blockAtFinish {
+						op.EmitData(sharedsec.NewBlockRequestAction(http.StatusForbidden, 0, "json"))
+					}
+				}))
+			}))
+
+			fctx := newTestRequestCtx()
+			var called bool
+			blocked := WithAppSec(fctx, context.Background(), &testlib.MockSpan{}, map[string]string{"param": "value"}, func(ctx context.Context) {
+				called = true
+				// The AppSec monitoring is available through both the context and the request context
+				require.NotNil(t, ctx.Value(listener.ContextKey{}))
+				require.Equal(t, ctx.Value(listener.ContextKey{}), fctx.Value(listener.ContextKey{}))
+				fctx.SetStatusCode(http.StatusCreated)
+				fctx.SetContentType("application/json")
+				fctx.Response.Header.Set("My-Response-Header", "my-value")
+				fctx.SetBodyString(`{"key":"value"}`)
+			})
+
+			require.Equal(t, "POST", args.Method)
+			require.Equal(t, "/path?query=value", args.RequestURI)
+			require.Equal(t, []string{"example.com"}, args.Headers["host"])
+			require.Equal(t, []string{"my-value"}, args.Headers["my-header"])
+			require.Equal(t, []string{"my-cookie-value"}, args.Cookies["my-cookie"])
+			require.Equal(t, map[string][]string{"query": {"value"}}, args.Query)
+			require.Equal(t, map[string]string{"param": "value"}, args.PathParams)
+			require.Equal(t, "1.2.3.4", args.ClientIP.String())
+
+			require.Equal(t, !tc.blockAtStart, called)
+			require.Equal(t, tc.blockAtStart || tc.blockAtFinish, blocked)
+			require.Equal(t, tc.status, fctx.Response.StatusCode())
+			if tc.status == http.StatusForbidden {
+				require.Contains(t, string(fctx.Response.Body()), "You've been blocked")
+				require.Empty(t, fctx.Response.Header.Peek("My-Response-Header"))
+				return
+			}
+			require.Equal(t, tc.body, string(fctx.Response.Body()))
+			require.Equal(t, "application/json", string(fctx.Response.Header.ContentType()))
+			require.Equal(t, "my-value", string(fctx.Response.Header.Peek("My-Response-Header")))
+			require.Equal(t, tc.status, res.Status)
+			require.Equal(t, []string{"my-value"}, res.Headers["my-response-header"])
+			if tc.monitorBody {
+				require.Equal(t, map[string]any{"key": "value"}, res.Body)
+			} else {
+				require.Nil(t, res.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package graphqltrace provides the functions shared by the GraphQL server
+// integrations to report the query metrics, the error paths and the summary of
+// the trivial resolvers.
+package graphqltrace
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	// MetricQueryDepth is the span metric of the maximum nesting depth of the fields of the operation.
+	MetricQueryDepth = "graphql.query.depth"
+	// MetricQueryFields is the span metric of the number of fields selected by the operation.
+	MetricQueryFields = "graphql.query.fields"
+	// MetricQueryComplexity is the span metric of the complexity of the operation. Every field costs 1 plus the
+	// complexity of its own selection set multiplied by its pagination argument (first, last or limit), if any. It
+	// is capped at MaxQueryComplexity.
+	MetricQueryComplexity = "graphql.query.complexity"
+	// MetricErrorsCount is the span metric of the number of errors returned by the operation.
+	MetricErrorsCount = "graphql.errors.count"
+	// TagErrorsPaths is the span tag of the comma-separated list of the paths of the errors returned by the
+	// operation, such as `user.friends.0.name`.
+	TagErrorsPaths = "graphql.errors.paths"
+
+	// TrivialResolversSpanName is the name of the span summarizing the trivial resolvers of an operation.
+	TrivialResolversSpanName = "graphql.resolvers.trivial"
+	// MetricTrivialResolversCount is the span metric of the number of trivial resolvers summarized by the span.
+	MetricTrivialResolversCount = "graphql.resolvers.trivial.count"
+	// MetricTrivialResolversDuration is the span metric of the total duration, in nanoseconds, of the trivial
+	// resolvers summarized by the span.
+	MetricTrivialResolversDuration = "graphql.resolvers.trivial.duration"
+)
+
+// MaxQueryComplexity is the maximum value of MetricQueryComplexity, reported by the operations at least as complex.
+const MaxQueryComplexity = math.MaxInt32
+
+// maxErrorPaths is the maximum number of error paths reported in TagErrorsPaths.
+const maxErrorPaths = 20
+
+// paginationArguments are the names of the field arguments multiplying the complexity of their selection set.
+var paginationArguments = [...]string{"first", "last", "limit"}
+
+// QueryMetrics are the metrics of a GraphQL operation.
+type QueryMetrics struct {
+	Depth      int
+	Fields     int
+	Complexity int
+}
+
+// SetTags sets the query metrics on the given span.
+func (m QueryMetrics) SetTags(span ddtrace.Span) {
+	span.SetTag(MetricQueryDepth, m.Depth)
+	span.SetTag(MetricQueryFields, m.Fields)
+	span.SetTag(MetricQueryComplexity, m.Complexity)
+}
+
+// ParseQueryMetrics parses the given query and returns the metrics of the operation of the given name. False is
+// returned when the query cannot be parsed or the operation is not found.
+func ParseQueryMetrics(query, operationName string, variables map[string]any) (QueryMetrics, bool) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return QueryMetrics{}, false
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return QueryMetrics{}, false
+	}
+	return OperationQueryMetrics(op, doc.Fragments, variables), true
+}
+
+// OperationQueryMetrics returns the metrics of the given parsed operation. The fragments are only used when the
+// fragment spreads of the operation were not resolved by the validation of the query.
+func OperationQueryMetrics(op *ast.OperationDefinition, fragments ast.FragmentDefinitionList, variables map[string]any) QueryMetrics {
+	w := walker{fragments: fragments, variables: variables, visiting: map[string]bool{}}
+	var m QueryMetrics
+	m.Complexity = w.walk(op.SelectionSet, 1, &m)
+	return m
+}
+
+type walker struct {
+	fragments ast.FragmentDefinitionList
+	variables map[string]any
+	// visiting holds the fragments being walked to stop at fragment cycles, which are only rejected by the query
+	// validation.
+	visiting map[string]bool
+}
+
+// walk walks the given selection set at the given depth, updating the depth and field count of m, and returns the
+// complexity of the selection set, capped at MaxQueryComplexity.
+func (w *walker) walk(set ast.SelectionSet, depth int, m *QueryMetrics) (complexity int) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			m.Fields++
+			if depth > m.Depth {
+				m.Depth = depth
+			}
+			complexity = addComplexity(complexity, addComplexity(1, mulComplexity(w.multiplier(sel), w.walk(sel.SelectionSet, depth+1, m))))
+		case *ast.InlineFragment:
+			complexity = addComplexity(complexity, w.walk(sel.SelectionSet, depth, m))
+		case *ast.FragmentSpread:
+			def := sel.Definition
+			if def == nil {
+				def = w.fragments.ForName(sel.Name)
+			}
+			if def == nil || w.visiting[sel.Name] {
+				continue
+			}
+			w.visiting[sel.Name] = true
+			complexity = addComplexity(complexity, w.walk(def.SelectionSet, depth, m))
+			delete(w.visiting, sel.Name)
+		}
+	}
+	return complexity
+}
+
+// addComplexity returns a+b, saturating at MaxQueryComplexity. Both values must be in [0, MaxQueryComplexity].
+func addComplexity(a, b int) int {
+	if a > MaxQueryComplexity-b {
+		return MaxQueryComplexity
+	}
+	return a + b
+}
+
+// mulComplexity returns a*b, saturating at MaxQueryComplexity. Both values must be in [0, MaxQueryComplexity].
+func mulComplexity(a, b int) int {
+	if b != 0 && a > MaxQueryComplexity/b {
+		return MaxQueryComplexity
+	}
+	return a * b
+}
+
+// multiplier returns the value of the pagination argument of the given field, capped at MaxQueryComplexity, or 1.
+func (w *walker) multiplier(f *ast.Field) int {
+	for _, name := range paginationArguments {
+		arg := f.Arguments.ForName(name)
+		if arg == nil {
+			continue
+		}
+		v, err := arg.Value.Value(w.variables)
+		if err != nil {
+			continue
+		}
+		if n := toInt(v); n > 0 {
+			return n
+		}
+	}
+	return 1
+}
+
+// toInt returns the given numeric argument value as an int capped at MaxQueryComplexity, or 0.
+func toInt(v any) int {
+	var n float64
+	switch v := v.(type) {
+	case int:
+		n = float64(v)
+	case int32:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	case json.Number:
+		n, _ = v.Float64()
+	default:
+		return 0
+	}
+	if n >= MaxQueryComplexity {
+		return MaxQueryComplexity
+	}
+	return int(n)
+}
+
+// FormatErrorPath returns the dot-separated representation of the given error path, made of field names and list
+// indexes.
+func FormatErrorPath(path []any) string {
+	var sb strings.Builder
+	for i, elem := range path {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		switch elem := elem.(type) {
+		case string:
+			sb.WriteString(elem)
+		case int:
+			sb.WriteString(strconv.Itoa(elem))
+		default:
+			b, _ := json.Marshal(elem)
+			sb.Write(b)
+		}
+	}
+	return sb.String()
+}
+
+// SetErrorTags sets the number of errors and the list of their distinct non-empty paths, formatted with
+// FormatErrorPath, on the given span. Nothing is set when there are no errors.
+func SetErrorTags(span ddtrace.Span, paths [][]any) {
+	if len(paths) == 0 {
+		return
+	}
+	span.SetTag(MetricErrorsCount, len(paths))
+	seen := make(map[string]struct{}, len(paths))
+	formatted := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if len(p) == 0 {
+			continue
+		}
+		s := FormatErrorPath(p)
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		if formatted = append(formatted, s); len(formatted) == maxErrorPaths {
+			break
+		}
+	}
+	if len(formatted) > 0 {
+		span.SetTag(TagErrorsPaths, strings.Join(formatted, ","))
+	}
+}
+
+// TrivialResolvers summarizes the trivial resolvers of an operation faster than a threshold into a single span,
+// while the slower ones are still traced with their own span. It is safe for concurrent use.
+type TrivialResolvers struct {
+	threshold time.Duration
+
+	mu            sync.Mutex
+	count         int
+	totalDuration time.Duration
+	start, end    time.Time
+}
+
+// NewTrivialResolvers returns a new summary of the trivial resolvers faster than the given threshold.
+func NewTrivialResolvers(threshold time.Duration) *TrivialResolvers {
+	return &TrivialResolvers{threshold: threshold}
+}
+
+// Add adds the trivial resolver execution of the given start and finish times to the summary. False is returned
+// when it is as slow as the threshold, in which case it is not added and should be traced with its own span.
+func (r *TrivialResolvers) Add(start, finish time.Time) bool {
+	d := finish.Sub(start)
+	if d >= r.threshold {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	r.totalDuration += d
+	if r.start.IsZero() || start.Before(r.start) {
+		r.start = start
+	}
+	if finish.After(r.end) {
+		r.end = finish
+	}
+	return true
+}
+
+// Finish creates the summary span, as a child of the given parent span, spanning from the start of the first
+// summarized resolver to the end of the last one. No span is created when no resolver was summarized. The summary
+// is reset, so that calling Finish again only reports the resolvers added since the previous call.
+func (r *TrivialResolvers) Finish(parent ddtrace.Span, opts ...ddtrace.StartSpanOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.count == 0 {
+		return
+	}
+	opts = append(opts,
+		tracer.ChildOf(parent.Context()),
+		tracer.StartTime(r.start),
+		tracer.Tag(MetricTrivialResolversCount, r.count),
+		tracer.Tag(MetricTrivialResolversDuration, r.totalDuration.Nanoseconds()),
+	)
+	span := tracer.StartSpan(TrivialResolversSpanName, opts...)
+	span.Finish(tracer.FinishTime(r.end))
+	r.count, r.totalDuration, r.start, r.end = 0, 0, time.Time{}, time.Time{}
+}
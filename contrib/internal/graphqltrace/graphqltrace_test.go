@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package graphqltrace
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueryMetrics(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]any
+		expected      QueryMetrics
+		err           bool
+	}{
+		{
+			name:     "flat",
+			query:    `{ hello, world }`,
+			expected: QueryMetrics{Depth: 1, Fields: 2, Complexity: 2},
+		},
+		{
+			name:     "nested",
+			query:    `{ user { name, friends { name } } }`,
+			expected: QueryMetrics{Depth: 3, Fields: 4, Complexity: 4},
+		},
+		{
+			name:     "pagination",
+			query:    `{ users(first: 10) { name, posts(last: 5) { title } } }`,
+			expected: QueryMetrics{Depth: 3, Fields: 4, Complexity: 1 + 10*(1+(1+5*1))},
+		},
+		{
+			name:      "pagination-variable",
+			query:     `query Q($n: Int) { users(limit: $n) { name } }`,
+			variables: map[string]any{"n": 3},
+			expected:  QueryMetrics{Depth: 2, Fields: 2, Complexity: 1 + 3},
+		},
+		{
+			name:     "pagination-overflow",
+			query:    `{ a(first: 2147483647) { b(first: 2147483647) { c(first: 2147483647) { d } } } }`,
+			expected: QueryMetrics{Depth: 4, Fields: 4, Complexity: MaxQueryComplexity},
+		},
+		{
+			name:      "pagination-overflow-variable",
+			query:     `query Q($n: Int) { a(first: $n) { b } }`,
+			variables: map[string]any{"n": float64(1 << 62)},
+			expected:  QueryMetrics{Depth: 2, Fields: 2, Complexity: MaxQueryComplexity},
+		},
+		{
+			name: "fragments",
+			query: `
+				query { user { ...UserFields, ... on User { id } } }
+				fragment UserFields on User { name, friends { name } }`,
+			expected: QueryMetrics{Depth: 3, Fields: 5, Complexity: 5},
+		},
+		{
+			name: "fragment-cycle",
+			query: `
+				query { user { ...A } }
+				fragment A on User { name, ...B }
+				fragment B on User { id, ...A }`,
+			expected: QueryMetrics{Depth: 2, Fields: 3, Complexity: 3},
+		},
+		{
+			name:          "operation-name",
+			query:         `query A { a } query B { b { c } }`,
+			operationName: "B",
+			expected:      QueryMetrics{Depth: 2, Fields: 2, Complexity: 2},
+		},
+		{
+			name:          "unknown-operation",
+			query:         `query A { a } query B { b }`,
+			operationName: "C",
+			err:           true,
+		},
+		{
+			name:  "invalid",
+			query: `query is invalid`,
+			err:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, ok := ParseQueryMetrics(tc.query, tc.operationName, tc.variables)
+			require.Equal(t, !tc.err, ok)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestFormatErrorPath(t *testing.T) {
+	assert.Equal(t, "", FormatErrorPath(nil))
+	assert.Equal(t, "user", FormatErrorPath([]any{"user"}))
+	assert.Equal(t, "user.friends.0.name", FormatErrorPath([]any{"user", "friends", 0, "name"}))
+	assert.Equal(t, "user.friends.1.name", FormatErrorPath([]any{"user", "friends", float64(1), "name"}))
+}
+
+func TestSetErrorTags(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	t.Run("none", func(t *testing.T) {
+		span := tracer.StartSpan("test")
+		SetErrorTags(span, nil)
+		span.Finish()
+		s := mt.FinishedSpans()[0]
+		assert.Nil(t, s.Tag(MetricErrorsCount))
+		assert.Nil(t, s.Tag(TagErrorsPaths))
+		mt.Reset()
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		span := tracer.StartSpan("test")
+		SetErrorTags(span, [][]any{{"a", 0}, nil, {"a", 0}, {"b"}})
+		span.Finish()
+		s := mt.FinishedSpans()[0]
+		assert.Equal(t, 4, s.Tag(MetricErrorsCount))
+		assert.Equal(t, "a.0,b", s.Tag(TagErrorsPaths))
+		mt.Reset()
+	})
+
+	t.Run("max-paths", func(t *testing.T) {
+		paths := make([][]any, maxErrorPaths+5)
+		for i := range paths {
+			paths[i] = []any{"list", i}
+		}
+		span := tracer.StartSpan("test")
+		SetErrorTags(span, paths)
+		span.Finish()
+		s := mt.FinishedSpans()[0]
+		assert.Equal(t, maxErrorPaths+5, s.Tag(MetricErrorsCount))
+		tag := s.Tag(TagErrorsPaths).(string)
+		assert.Len(t, strings.Split(tag, ","), maxErrorPaths)
+		assert.True(t, strings.HasSuffix(tag, ",list.19"))
+		mt.Reset()
+	})
+}
+
+func TestTrivialResolvers(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	now := time.Now()
+	r := NewTrivialResolvers(time.Millisecond)
+	require.True(t, r.Add(now.Add(time.Microsecond), now.Add(3*time.Microsecond)))
+	require.True(t, r.Add(now, now.Add(time.Microsecond)))
+	require.False(t, r.Add(now, now.Add(time.Millisecond)))
+
+	parent := tracer.StartSpan("parent")
+	r.Finish(parent, tracer.ServiceName("service"))
+	parent.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[0]
+	assert.Equal(t, TrivialResolversSpanName, s.OperationName())
+	assert.Equal(t, parent.Context().SpanID(), s.ParentID())
+	assert.Equal(t, "service", s.Tag("service.name"))
+	assert.Equal(t, 2, s.Tag(MetricTrivialResolversCount))
+	assert.Equal(t, int64(3*time.Microsecond), s.Tag(MetricTrivialResolversDuration))
+	assert.Equal(t, now, s.StartTime())
+	assert.Equal(t, now.Add(3*time.Microsecond), s.FinishTime())
+
+	t.Run("reset", func(t *testing.T) {
+		mt.Reset()
+		parent := tracer.StartSpan("parent")
+		r.Finish(parent)
+		require.True(t, r.Add(now, now.Add(time.Microsecond)))
+		r.Finish(parent)
+		parent.Finish()
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, 1, spans[0].Tag(MetricTrivialResolversCount))
+		assert.Equal(t, int64(time.Microsecond), spans[0].Tag(MetricTrivialResolversDuration))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mt.Reset()
+		parent := tracer.StartSpan("parent")
+		NewTrivialResolvers(time.Millisecond).Finish(parent)
+		parent.Finish()
+		require.Len(t, mt.FinishedSpans(), 1)
+	})
+}
@@ -6,8 +6,11 @@
 package httptrace
 
 import (
+	"fmt"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/log"
@@ -22,8 +25,17 @@ const (
 	envQueryStringRegexp = "DD_TRACE_OBFUSCATION_QUERY_STRING_REGEXP"
 	// envTraceClientIPEnabled is the name of the env var used to specify whether or not to collect client ip in span tags
 	envTraceClientIPEnabled = "DD_TRACE_CLIENT_IP_ENABLED"
+	// envServerErrorStatuses is the name of the env var used to specify the status codes of server responses
+	// reported as errors, as a comma-separated list of codes and inclusive ranges such as "429,500-599".
+	envServerErrorStatuses = "DD_TRACE_HTTP_SERVER_ERROR_STATUSES"
+	// envClientErrorStatuses is the name of the env var used to specify the status codes of responses received
+	// by HTTP clients reported as errors, in the same format as envServerErrorStatuses.
+	envClientErrorStatuses = "DD_TRACE_HTTP_CLIENT_ERROR_STATUSES"
 )
 
+// defaultErrorStatuses are the status codes reported as errors when no other ranges are configured.
+const defaultErrorStatuses = "500-599"
+
 // defaultQueryStringRegexp is the regexp used for query string obfuscation if `envQueryStringRegexp` is empty.
 var defaultQueryStringRegexp = regexp.MustCompile("(?i)(?:p(?:ass)?w(?:or)?d|pass(?:_?phrase)?|secret|(?:api_?|private_?|public_?|access_?|secret_?)key(?:_?id)?|token|consumer_?(?:id|key|secret)|sign(?:ed|ature)?|auth(?:entication|orization)?)(?:(?:\\s|%20)*(?:=|%3D)[^&]+|(?:\"|%22)(?:\\s|%20)*(?::|%3A)(?:\\s|%20)*(?:\"|%22)(?:%2[^2]|%[^2]|[^\"%])+(?:\"|%22))|bearer(?:\\s|%20)+[a-z0-9\\._\\-]|token(?::|%3A)[a-z0-9]{13}|gh[opsu]_[0-9a-zA-Z]{36}|ey[I-L](?:[\\w=-]|%3D)+\\.ey[I-L](?:[\\w=-]|%3D)+(?:\\.(?:[\\w.+\\/=-]|%3D|%2F|%2B)+)?|[\\-]{5}BEGIN(?:[a-z\\s]|%20)+PRIVATE(?:\\s|%20)KEY[\\-]{5}[^\\-]+[\\-]{5}END(?:[a-z\\s]|%20)+PRIVATE(?:\\s|%20)KEY|ssh-rsa(?:\\s|%20)*(?:[a-z0-9\\/\\.+]|%2F|%5C|%2B){100,}")
 
@@ -31,6 +43,8 @@ type config struct {
 	queryStringRegexp *regexp.Regexp // specifies the regexp to use for query string obfuscation.
 	queryString       bool           // reports whether the query string should be included in the URL span tag.
 	traceClientIP     bool
+	isServerError     func(statusCode int) bool // reports whether a server response status is an error.
+	isClientError     func(statusCode int) bool // reports whether a client response status is an error.
 }
 
 func newConfig() config {
@@ -38,6 +52,8 @@ func newConfig() config {
 		queryString:       !internal.BoolEnv(envQueryStringDisabled, false),
 		queryStringRegexp: defaultQueryStringRegexp,
 		traceClientIP:     internal.BoolEnv(envTraceClientIPEnabled, false),
+		isServerError:     statusRangesFromEnv(envServerErrorStatuses),
+		isClientError:     statusRangesFromEnv(envClientErrorStatuses),
 	}
 	if s, ok := os.LookupEnv(envQueryStringRegexp); !ok {
 		return c
@@ -51,3 +67,62 @@ func newConfig() config {
 	}
 	return c
 }
+
+// statusRangesFromEnv returns a function reporting whether a status code belongs to the ranges held by the env var
+// env, or to defaultErrorStatuses if the env var is not set or invalid.
+func statusRangesFromEnv(env string) func(statusCode int) bool {
+	fn, _ := ParseStatusRanges(defaultErrorStatuses)
+	s, ok := os.LookupEnv(env)
+	if !ok {
+		return fn
+	}
+	if f, err := ParseStatusRanges(s); err == nil {
+		fn = f
+	} else {
+		log.Warn("Invalid value for %s: %v. Using default value %q instead.", env, err, defaultErrorStatuses)
+	}
+	return fn
+}
+
+// ParseStatusRanges parses a comma-separated list of HTTP status codes and inclusive status code ranges, such as
+// "429,500-599", and returns a function reporting whether a status code belongs to one of them.
+func ParseStatusRanges(s string) (func(statusCode int) bool, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		min, err := parseStatus(lo)
+		if err != nil {
+			return nil, err
+		}
+		max := min
+		if isRange {
+			if max, err = parseStatus(hi); err != nil {
+				return nil, err
+			}
+		}
+		if min > max {
+			return nil, fmt.Errorf("invalid status range %q", part)
+		}
+		ranges = append(ranges, [2]int{min, max})
+	}
+	return func(statusCode int) bool {
+		for _, r := range ranges {
+			if statusCode >= r[0] && statusCode <= r[1] {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func parseStatus(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", s)
+	}
+	return code, nil
+}
@@ -61,10 +61,73 @@ func TestConfig(t *testing.T) {
 	}
 }
 
+func TestParseStatusRanges(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		errors  []int
+		success []int
+	}{
+		{in: "500-599", errors: []int{500, 503, 599}, success: []int{200, 429, 499}},
+		{in: "429,500-502", errors: []int{429, 500, 502}, success: []int{200, 428, 503}},
+		{in: " 400 - 403 , 418 ", errors: []int{400, 403, 418}, success: []int{404, 500}},
+		{in: "", success: []int{200, 404, 500}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			fn, err := ParseStatusRanges(tc.in)
+			require.NoError(t, err)
+			for _, code := range tc.errors {
+				require.True(t, fn(code), code)
+			}
+			for _, code := range tc.success {
+				require.False(t, fn(code), code)
+			}
+		})
+	}
+
+	for _, in := range []string{"abc", "500-", "600", "99", "599-500", "400-abc"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := ParseStatusRanges(in)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestErrorStatusesConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		defer cleanEnv()()
+		c := newConfig()
+		require.True(t, c.isServerError(500))
+		require.False(t, c.isServerError(429))
+		require.True(t, c.isClientError(503))
+		require.False(t, c.isClientError(404))
+	})
+
+	t.Run("env", func(t *testing.T) {
+		defer cleanEnv()()
+		os.Setenv(envServerErrorStatuses, "429,500-502")
+		os.Setenv(envClientErrorStatuses, "400-499")
+		c := newConfig()
+		require.True(t, c.isServerError(429))
+		require.False(t, c.isServerError(503))
+		require.True(t, c.isClientError(404))
+		require.False(t, c.isClientError(500))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		defer cleanEnv()()
+		os.Setenv(envServerErrorStatuses, "5xx")
+		c := newConfig()
+		require.True(t, c.isServerError(500))
+		require.False(t, c.isServerError(429))
+	})
+}
+
 func cleanEnv() func() {
 	env := map[string]string{
 		envQueryStringDisabled: os.Getenv(envQueryStringDisabled),
 		envQueryStringRegexp:   os.Getenv(envQueryStringRegexp),
+		envServerErrorStatuses: os.Getenv(envServerErrorStatuses),
+		envClientErrorStatuses: os.Getenv(envClientErrorStatuses),
 	}
 	for k := range env {
 		os.Unsetenv(k)
@@ -62,21 +62,35 @@ func StartRequestSpan(r *http.Request, opts ...ddtrace.StartSpanOption) (tracer.
 }
 
 // FinishRequestSpan finishes the given HTTP request span and sets the expected response-related tags such as the status
-// code. Any further span finish option can be added with opts.
-func FinishRequestSpan(s tracer.Span, status int, opts ...tracer.FinishOption) {
-	var statusStr string
+// code. The span is marked as an error when isStatusError reports the status as one, isStatusError defaulting to
+// IsServerError when nil. Any further span finish option can be added with opts.
+func FinishRequestSpan(s tracer.Span, status int, isStatusError func(statusCode int) bool, opts ...tracer.FinishOption) {
 	if status == 0 {
-		statusStr = "200"
-	} else {
-		statusStr = strconv.Itoa(status)
+		status = http.StatusOK
 	}
+	statusStr := strconv.Itoa(status)
 	s.SetTag(ext.HTTPCode, statusStr)
-	if status >= 500 && status < 600 {
+	if isStatusError == nil {
+		isStatusError = IsServerError
+	}
+	if isStatusError(status) {
 		s.SetTag(ext.Error, fmt.Errorf("%s: %s", statusStr, http.StatusText(status)))
 	}
 	s.Finish(opts...)
 }
 
+// IsServerError reports whether the status of a server response should be reported as an error, according to
+// DD_TRACE_HTTP_SERVER_ERROR_STATUSES (5xx by default).
+func IsServerError(statusCode int) bool {
+	return cfg.isServerError(statusCode)
+}
+
+// IsClientError reports whether the status of a response received by an HTTP client should be reported as an error,
+// according to DD_TRACE_HTTP_CLIENT_ERROR_STATUSES (5xx by default).
+func IsClientError(statusCode int) bool {
+	return cfg.isClientError(statusCode)
+}
+
 // urlFromRequest returns the full URL from the HTTP request. If query params are collected, they are obfuscated granted
 // obfuscation is not disabled by the user (through DD_TRACE_OBFUSCATION_QUERY_STRING_REGEXP)
 // See https://docs.datadoghq.com/tracing/configure_data_security#redacting-the-query-in-the-url for more information.
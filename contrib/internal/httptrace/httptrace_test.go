@@ -65,6 +65,39 @@ func TestStartRequestSpan(t *testing.T) {
 
 // TestClientIP tests behavior of StartRequestSpan based on
 // the DD_TRACE_CLIENT_IP_ENABLED environment variable
+func TestFinishRequestSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	for _, tc := range []struct {
+		name          string
+		status        int
+		isStatusError func(int) bool
+		wantCode      string
+		wantError     bool
+	}{
+		{name: "default-success", status: 0, wantCode: "200"},
+		{name: "default-client-error", status: 404, wantCode: "404"},
+		{name: "default-server-error", status: 503, wantCode: "503", wantError: true},
+		{name: "custom-error", status: 429, isStatusError: func(c int) bool { return c == 429 }, wantCode: "429", wantError: true},
+		{name: "custom-success", status: 503, isStatusError: func(c int) bool { return c == 429 }, wantCode: "503"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt.Reset()
+			span, _ := StartRequestSpan(httptest.NewRequest(http.MethodGet, "/", nil))
+			FinishRequestSpan(span, tc.status, tc.isStatusError)
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.wantCode, spans[0].Tag(ext.HTTPCode))
+			if tc.wantError {
+				assert.NotNil(t, spans[0].Tag(ext.Error))
+			} else {
+				assert.Nil(t, spans[0].Tag(ext.Error))
+			}
+		})
+	}
+}
+
 func TestTraceClientIPFlag(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
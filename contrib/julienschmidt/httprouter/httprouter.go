@@ -58,17 +58,24 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// get the resource associated to this request
 	route := req.URL.Path
 	_, ps, _ := r.Router.Lookup(req.Method, route)
+	var routeParams map[string]string
+	if len(ps) > 0 {
+		routeParams = make(map[string]string, len(ps))
+	}
 	for _, param := range ps {
 		route = strings.Replace(route, param.Value, ":"+param.Key, 1)
+		routeParams[param.Key] = param.Value
 	}
 	resource := req.Method + " " + route
 	spanOpts := options.Copy(r.config.spanOpts...) // spanOpts must be a copy of r.config.spanOpts, locally scoped, to avoid races.
 	spanOpts = append(spanOpts, httptraceinternal.HeaderTagsFromRequest(req, r.config.headerTags))
 
 	httptrace.TraceAndServe(r.Router, w, req, &httptrace.ServeConfig{
-		Service:  r.config.serviceName,
-		Resource: resource,
-		SpanOpts: spanOpts,
-		Route:    route,
+		Service:       r.config.serviceName,
+		Resource:      resource,
+		SpanOpts:      spanOpts,
+		Route:         route,
+		RouteParams:   routeParams,
+		IsStatusError: r.config.isStatusError,
 	})
 }
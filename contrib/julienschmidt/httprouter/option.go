@@ -22,6 +22,7 @@ type routerConfig struct {
 	spanOpts      []ddtrace.StartSpanOption
 	analyticsRate float64
 	headerTags    *internal.LockMap
+	isStatusError func(statusCode int) bool
 }
 
 // RouterOption represents an option that can be passed to New.
@@ -84,3 +85,12 @@ func WithHeaderTags(headers []string) RouterOption {
 		cfg.headerTags = internal.NewLockMap(headerTagsMap)
 	}
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error. It defaults to the status codes
+// configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES, 5xx if unset.
+func WithStatusCheck(fn func(statusCode int) bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.isStatusError = fn
+	}
+}
@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package cache provides functions to trace the informer event handlers of
+// k8s.io/client-go/tools/cache (https://github.com/kubernetes/client-go).
+package cache // import "github.com/nowfred/dd-trace-go/contrib/k8s.io/client-go/tools/cache"
+
+import (
+	"context"
+	"math"
+	"reflect"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+const componentName = "k8s.io/client-go/tools/cache"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("k8s.io/client-go/kubernetes")
+}
+
+const (
+	spanEvent = "k8s.informer.event"
+
+	tagEventType       = "k8s.informer.event_type"
+	tagObjectKind      = "k8s.object.kind"
+	tagObjectNamespace = "k8s.object.namespace"
+	tagObjectName      = "k8s.object.name"
+)
+
+const (
+	eventAdd    = "add"
+	eventUpdate = "update"
+	eventDelete = "delete"
+)
+
+// ResourceEventHandlerFuncs is like cache.ResourceEventHandlerFuncs, but its
+// functions are given a context holding the span of the event, to be passed to
+// the AddContext method of the traced queues of the util/workqueue package of
+// this integration, or to the Kubernetes API calls, so that the reconcile loop
+// is traced end to end. Any of the functions may be nil.
+type ResourceEventHandlerFuncs struct {
+	AddFunc    func(ctx context.Context, obj any)
+	UpdateFunc func(ctx context.Context, oldObj, newObj any)
+	DeleteFunc func(ctx context.Context, obj any)
+}
+
+// WrapEventHandler returns an informer event handler tracing every call of the
+// given handler with a k8s.informer.event span.
+func WrapEventHandler(h cache.ResourceEventHandler, opts ...Option) cache.ResourceEventHandler {
+	return WrapEventHandlerFuncs(ResourceEventHandlerFuncs{
+		AddFunc:    func(_ context.Context, obj any) { h.OnAdd(obj) },
+		UpdateFunc: func(_ context.Context, oldObj, newObj any) { h.OnUpdate(oldObj, newObj) },
+		DeleteFunc: func(_ context.Context, obj any) { h.OnDelete(obj) },
+	}, opts...)
+}
+
+// WrapEventHandlerFuncs returns an informer event handler tracing every call of
+// the given functions with a k8s.informer.event span.
+func WrapEventHandlerFuncs(funcs ResourceEventHandlerFuncs, opts ...Option) cache.ResourceEventHandler {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return &eventHandler{funcs: funcs, cfg: cfg}
+}
+
+type eventHandler struct {
+	funcs ResourceEventHandlerFuncs
+	cfg   *config
+}
+
+// OnAdd implements cache.ResourceEventHandler.
+func (h *eventHandler) OnAdd(obj any) {
+	if h.funcs.AddFunc == nil {
+		return
+	}
+	span, ctx := h.startSpan(eventAdd, obj)
+	defer span.Finish()
+	h.funcs.AddFunc(ctx, obj)
+}
+
+// OnUpdate implements cache.ResourceEventHandler.
+func (h *eventHandler) OnUpdate(oldObj, newObj any) {
+	if h.funcs.UpdateFunc == nil {
+		return
+	}
+	span, ctx := h.startSpan(eventUpdate, newObj)
+	defer span.Finish()
+	h.funcs.UpdateFunc(ctx, oldObj, newObj)
+}
+
+// OnDelete implements cache.ResourceEventHandler.
+func (h *eventHandler) OnDelete(obj any) {
+	if h.funcs.DeleteFunc == nil {
+		return
+	}
+	span, ctx := h.startSpan(eventDelete, obj)
+	defer span.Finish()
+	h.funcs.DeleteFunc(ctx, obj)
+}
+
+func (h *eventHandler) startSpan(event string, obj any) (ddtrace.Span, context.Context) {
+	// The final state of the deleted objects may be unknown when the deletion
+	// was missed by the watch.
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	kind := objectKind(obj)
+	opts := []ddtrace.StartSpanOption{
+		tracer.ResourceName(event + " " + kind),
+		tracer.Tag(tagEventType, event),
+		tracer.Tag(tagObjectKind, kind),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Measured(),
+	}
+	if h.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(h.cfg.serviceName))
+	}
+	if o, err := meta.Accessor(obj); err == nil {
+		if ns := o.GetNamespace(); ns != "" {
+			opts = append(opts, tracer.Tag(tagObjectNamespace, ns))
+		}
+		opts = append(opts, tracer.Tag(tagObjectName, o.GetName()))
+	}
+	if !math.IsNaN(h.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, h.cfg.analyticsRate))
+	}
+	return tracer.StartSpanFromContext(context.Background(), spanEvent, opts...)
+}
+
+// objectKind returns the kind of the given object. The kind of the objects
+// decoded by the typed clients is usually unset, in which case the name of the
+// Go type of the object is returned, which matches its kind.
+func objectKind(obj any) string {
+	if o, ok := obj.(runtime.Object); ok {
+		if kind := o.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+			return kind
+		}
+	}
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return "unknown"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestWrapEventHandler(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var events []string
+	h := WrapEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { events = append(events, "add") },
+		UpdateFunc: func(any, any) { events = append(events, "update") },
+		DeleteFunc: func(any) { events = append(events, "delete") },
+	}, WithServiceName("controller"))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}}
+	h.OnAdd(pod)
+	h.OnUpdate(pod, pod)
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/pod", Obj: pod})
+	assert.Equal(t, []string{"add", "update", "delete"}, events)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	for i, event := range events {
+		s := spans[i]
+		assert.Equal(t, "k8s.informer.event", s.OperationName())
+		assert.Equal(t, event+" Pod", s.Tag(ext.ResourceName))
+		assert.Equal(t, event, s.Tag(tagEventType))
+		assert.Equal(t, "Pod", s.Tag(tagObjectKind))
+		assert.Equal(t, "default", s.Tag(tagObjectNamespace))
+		assert.Equal(t, "pod", s.Tag(tagObjectName))
+		assert.Equal(t, "controller", s.Tag(ext.ServiceName))
+		assert.Equal(t, componentName, s.Tag(ext.Component))
+	}
+}
+
+func TestWrapEventHandlerFuncs(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	h := WrapEventHandlerFuncs(ResourceEventHandlerFuncs{
+		AddFunc: func(ctx context.Context, _ any) {
+			span, _ := tracer.StartSpanFromContext(ctx, "enqueue")
+			span.Finish()
+		},
+	})
+	node := &corev1.Node{
+		TypeMeta:   metav1.TypeMeta{Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+	}
+	h.OnAdd(node)
+	// Events without function aren't traced
+	h.OnUpdate(node, node)
+	h.OnDelete(node)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	child, event := spans[0], spans[1]
+	assert.Equal(t, event.SpanID(), child.ParentID())
+	assert.Equal(t, "add Node", event.Tag(ext.ResourceName))
+	assert.Equal(t, "node", event.Tag(tagObjectName))
+	assert.Nil(t, event.Tag(tagObjectNamespace))
+}
+
+func TestObjectKind(t *testing.T) {
+	assert.Equal(t, "Pod", objectKind(&corev1.Pod{}))
+	assert.Equal(t, "Deployment", objectKind(&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "Deployment"}}))
+	assert.Equal(t, "string", objectKind("default/pod"))
+	assert.Equal(t, "unknown", objectKind(nil))
+}
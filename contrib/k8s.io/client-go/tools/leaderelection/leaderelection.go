@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package leaderelection provides functions to trace the leadership transitions
+// of k8s.io/client-go/tools/leaderelection (https://github.com/kubernetes/client-go).
+package leaderelection // import "github.com/nowfred/dd-trace-go/contrib/k8s.io/client-go/tools/leaderelection"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+const componentName = "k8s.io/client-go/tools/leaderelection"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("k8s.io/client-go/kubernetes")
+}
+
+const (
+	spanTransition = "k8s.leaderelection.transition"
+
+	tagName            = "k8s.leaderelection.name"
+	tagIdentity        = "k8s.leaderelection.identity"
+	tagLeader          = "k8s.leaderelection.leader"
+	tagLock            = "k8s.leaderelection.lock"
+	tagLeadingDuration = "k8s.leaderelection.leading_duration"
+)
+
+const (
+	transitionStartedLeading = "started_leading"
+	transitionStoppedLeading = "stopped_leading"
+	transitionNewLeader      = "new_leader"
+)
+
+// WrapLeaderElectionConfig returns a copy of the given leader election config
+// whose callbacks trace the leadership transitions with a
+// k8s.leaderelection.transition span, whose resource name is the transition:
+// started_leading, stopped_leading or new_leader. The stopped_leading span
+// holds the duration of the leadership, in nanoseconds. The spans of
+// OnStoppedLeading and OnNewLeader cover the execution of the callbacks, while
+// the span of OnStartedLeading is finished before calling it as it blocks for
+// the whole leadership. Only the callbacks set in the given config are wrapped,
+// so that leaderelection.NewLeaderElector still rejects the configs missing the
+// required ones.
+func WrapLeaderElectionConfig(lec leaderelection.LeaderElectionConfig, opts ...Option) leaderelection.LeaderElectionConfig {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	t := &transitions{cfg: cfg, callbacks: lec.Callbacks, name: lec.Name}
+	if lec.Lock != nil {
+		t.identity = lec.Lock.Identity()
+		t.lock = lec.Lock.Describe()
+	}
+	if lec.Callbacks.OnStartedLeading != nil {
+		lec.Callbacks.OnStartedLeading = t.onStartedLeading
+	}
+	if lec.Callbacks.OnStoppedLeading != nil {
+		lec.Callbacks.OnStoppedLeading = t.onStoppedLeading
+	}
+	if lec.Callbacks.OnNewLeader != nil {
+		lec.Callbacks.OnNewLeader = t.onNewLeader
+	}
+	return lec
+}
+
+type transitions struct {
+	cfg       *config
+	callbacks leaderelection.LeaderCallbacks
+	name      string
+	identity  string
+	lock      string
+
+	mu        sync.Mutex
+	leadingAt time.Time
+}
+
+func (t *transitions) onStartedLeading(ctx context.Context) {
+	t.mu.Lock()
+	t.leadingAt = time.Now()
+	t.mu.Unlock()
+	span := t.startSpan(transitionStartedLeading, tracer.Tag(tagLeader, t.identity))
+	span.Finish()
+	t.callbacks.OnStartedLeading(ctx)
+}
+
+func (t *transitions) onStoppedLeading() {
+	var opts []ddtrace.StartSpanOption
+	t.mu.Lock()
+	if !t.leadingAt.IsZero() {
+		opts = append(opts, tracer.Tag(tagLeadingDuration, time.Since(t.leadingAt).Nanoseconds()))
+		t.leadingAt = time.Time{}
+	}
+	t.mu.Unlock()
+	span := t.startSpan(transitionStoppedLeading, opts...)
+	defer span.Finish()
+	t.callbacks.OnStoppedLeading()
+}
+
+func (t *transitions) onNewLeader(identity string) {
+	span := t.startSpan(transitionNewLeader, tracer.Tag(tagLeader, identity))
+	defer span.Finish()
+	t.callbacks.OnNewLeader(identity)
+}
+
+func (t *transitions) startSpan(transition string, opts ...ddtrace.StartSpanOption) ddtrace.Span {
+	opts = append(opts,
+		tracer.ResourceName(transition),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Measured(),
+	)
+	if t.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(t.cfg.serviceName))
+	}
+	if t.name != "" {
+		opts = append(opts, tracer.Tag(tagName, t.name))
+	}
+	if t.identity != "" {
+		opts = append(opts, tracer.Tag(tagIdentity, t.identity))
+	}
+	if t.lock != "" {
+		opts = append(opts, tracer.Tag(tagLock, t.lock))
+	}
+	return tracer.StartSpan(spanTransition, opts...)
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package leaderelection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+func TestWrapLeaderElectionConfig(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var calls []string
+	lec := WrapLeaderElectionConfig(leaderelection.LeaderElectionConfig{
+		Name: "controller",
+		Lock: &resourcelock.LeaseLock{LockConfig: resourcelock.ResourceLockConfig{Identity: "pod-1"}},
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) { calls = append(calls, "started") },
+			OnStoppedLeading: func() { calls = append(calls, "stopped") },
+			OnNewLeader:      func(string) { calls = append(calls, "new") },
+		},
+	}, WithServiceName("controller-manager"))
+	assert.Equal(t, "controller", lec.Name)
+
+	lec.Callbacks.OnNewLeader("pod-1")
+	lec.Callbacks.OnStartedLeading(context.Background())
+	lec.Callbacks.OnStoppedLeading()
+	assert.Equal(t, []string{"new", "started", "stopped"}, calls)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	for i, transition := range []string{"new_leader", "started_leading", "stopped_leading"} {
+		s := spans[i]
+		assert.Equal(t, "k8s.leaderelection.transition", s.OperationName())
+		assert.Equal(t, transition, s.Tag(ext.ResourceName))
+		assert.Equal(t, "controller", s.Tag(tagName))
+		assert.Equal(t, "pod-1", s.Tag(tagIdentity))
+		assert.Equal(t, "controller-manager", s.Tag(ext.ServiceName))
+		assert.Equal(t, componentName, s.Tag(ext.Component))
+		assert.NotEmpty(t, s.Tag(tagLock))
+	}
+	assert.Equal(t, "pod-1", spans[0].Tag(tagLeader))
+	assert.Equal(t, "pod-1", spans[1].Tag(tagLeader))
+	assert.Nil(t, spans[2].Tag(tagLeader))
+	assert.NotNil(t, spans[2].Tag(tagLeadingDuration))
+}
+
+func TestWrapLeaderElectionConfigUnsetCallbacks(t *testing.T) {
+	lec := WrapLeaderElectionConfig(leaderelection.LeaderElectionConfig{
+		Lock:          &resourcelock.LeaseLock{LockConfig: resourcelock.ResourceLockConfig{Identity: "pod-1"}},
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {},
+		},
+	})
+	assert.Nil(t, lec.Callbacks.OnStoppedLeading)
+	assert.Nil(t, lec.Callbacks.OnNewLeader)
+	_, err := leaderelection.NewLeaderElector(lec)
+	assert.ErrorContains(t, err, "OnStoppedLeading")
+}
@@ -0,0 +1,23 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package leaderelection
+
+type config struct {
+	serviceName string
+}
+
+// Option represents an option that can be passed to WrapLeaderElectionConfig.
+type Option func(*config)
+
+func defaults(*config) {}
+
+// WithServiceName sets the given service name for the spans of the leadership
+// transitions. It defaults to the global service name.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package workqueue_test
+
+import (
+	"context"
+	"time"
+
+	cachetrace "github.com/nowfred/dd-trace-go/contrib/k8s.io/client-go/tools/cache"
+	workqueuetrace "github.com/nowfred/dd-trace-go/contrib/k8s.io/client-go/util/workqueue"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func Example() {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		panic(err)
+	}
+	client := kubernetes.NewForConfigOrDie(cfg)
+	factory := informers.NewSharedInformerFactory(client, time.Hour)
+
+	// Trace the processing of the queue items.
+	queue := workqueuetrace.WrapQueue(
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "pods"),
+		workqueuetrace.WithQueueName("pods"),
+	)
+	defer queue.ShutDown()
+
+	// Trace the informer events, and make them the parents of the processing of
+	// the queue items they add.
+	factory.Core().V1().Pods().Informer().AddEventHandler(cachetrace.WrapEventHandlerFuncs(cachetrace.ResourceEventHandlerFuncs{
+		AddFunc: func(ctx context.Context, obj any) {
+			if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+				queue.AddContext(ctx, key)
+			}
+		},
+	}))
+	factory.Start(context.Background().Done())
+
+	for {
+		ctx, key, shutdown := queue.GetContext(context.Background())
+		if shutdown {
+			return
+		}
+		// The spans created from ctx, such as the ones of the Kubernetes API
+		// calls, are children of the span of the processing of the item.
+		if err := reconcile(ctx, key.(string)); err != nil {
+			queue.AddRateLimited(key)
+		} else {
+			queue.Forget(key)
+		}
+		queue.Done(key)
+	}
+}
+
+func reconcile(context.Context, string) error { return nil }
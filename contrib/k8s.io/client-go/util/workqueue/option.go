@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package workqueue
+
+import (
+	"math"
+
+	"github.com/nowfred/dd-trace-go/internal"
+)
+
+type config struct {
+	serviceName   string
+	queueName     string
+	analyticsRate float64
+}
+
+// Option represents an option that can be passed to WrapQueue.
+type Option func(*config)
+
+func defaults(cfg *config) {
+	if internal.BoolEnv("DD_TRACE_K8S_ANALYTICS_ENABLED", false) {
+		cfg.analyticsRate = 1.0
+	} else {
+		cfg.analyticsRate = math.NaN()
+	}
+}
+
+// WithServiceName sets the given service name for the spans of the queue. It
+// defaults to the global service name.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithQueueName sets the name of the queue, used as the resource name of its
+// spans. It should match the name given to workqueue.NewNamedRateLimitingQueue.
+func WithQueueName(name string) Option {
+	return func(cfg *config) {
+		cfg.queueName = name
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
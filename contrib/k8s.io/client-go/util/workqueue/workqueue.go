@@ -0,0 +1,221 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package workqueue provides functions to trace the processing of the items of
+// k8s.io/client-go/util/workqueue (https://github.com/kubernetes/client-go) queues.
+package workqueue // import "github.com/nowfred/dd-trace-go/contrib/k8s.io/client-go/util/workqueue"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+const componentName = "k8s.io/client-go/util/workqueue"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("k8s.io/client-go/kubernetes")
+}
+
+const (
+	spanWait    = "k8s.workqueue.wait"
+	spanProcess = "k8s.workqueue.process"
+
+	tagQueueName = "k8s.workqueue.name"
+	tagKey       = "k8s.workqueue.key"
+	tagRetries   = "k8s.workqueue.retries"
+	tagRequeued  = "k8s.workqueue.requeued"
+)
+
+// Queue is a traced workqueue.RateLimitingInterface. Every item returned by Get
+// or GetContext is traced with two spans: a k8s.workqueue.wait span measuring
+// the queue latency, from the time the item was added to the time it was
+// returned, and its k8s.workqueue.process child span measuring the work
+// duration, from the time the item was returned to the time Done is called.
+type Queue struct {
+	workqueue.RateLimitingInterface
+	cfg *config
+
+	mu         sync.Mutex
+	added      map[any]addition
+	processing map[any]ddtrace.Span
+}
+
+// addition is the time an item was first added to the queue since it was last
+// returned, and the span context it was added from, if any.
+type addition struct {
+	time   time.Time
+	parent ddtrace.SpanContext
+}
+
+var _ workqueue.RateLimitingInterface = (*Queue)(nil)
+
+// WrapQueue returns the traced version of the given queue.
+func WrapQueue(q workqueue.RateLimitingInterface, opts ...Option) *Queue {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return &Queue{
+		RateLimitingInterface: q,
+		cfg:                   cfg,
+		added:                 make(map[any]addition),
+		processing:            make(map[any]ddtrace.Span),
+	}
+}
+
+// Add adds the given item to the queue.
+func (q *Queue) Add(item any) {
+	q.AddContext(context.Background(), item)
+}
+
+// AddContext adds the given item to the queue. The spans of the processing of
+// the item are children of the span found in ctx, if any, so that the
+// reconcile loop is part of the trace of the event that triggered it, such as
+// the one created by the informer event handlers of the tools/cache package of
+// this integration.
+func (q *Queue) AddContext(ctx context.Context, item any) {
+	var parent ddtrace.SpanContext
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		parent = span.Context()
+	}
+	q.record(item, parent)
+	q.RateLimitingInterface.Add(item)
+}
+
+// AddAfter adds the given item to the queue after the given duration, which is
+// included in the queue latency.
+func (q *Queue) AddAfter(item any, duration time.Duration) {
+	q.record(item, nil)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited adds the given item to the queue once the rate limiter says
+// it's ok, which is included in the queue latency. When the item is being
+// processed, its processing span is tagged as requeued and the spans of the
+// next processing of the item are children of it, so that the retries of a
+// failing item are part of the same trace.
+func (q *Queue) AddRateLimited(item any) {
+	q.mu.Lock()
+	var parent ddtrace.SpanContext
+	if span, ok := q.processing[item]; ok {
+		span.SetTag(tagRequeued, true)
+		parent = span.Context()
+	}
+	q.mu.Unlock()
+	q.record(item, parent)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+// record records the time the given item was added to the queue. The queue
+// deduplicates the items waiting to be processed, so only the first addition is
+// recorded.
+func (q *Queue) record(item any, parent ddtrace.SpanContext) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.added[item]; ok {
+		return
+	}
+	q.added[item] = addition{time: time.Now(), parent: parent}
+}
+
+// Get blocks until it can return an item to be processed, and starts tracing
+// its processing. Done must be called with the item once it is processed.
+func (q *Queue) Get() (item any, shutdown bool) {
+	_, item, shutdown = q.GetContext(context.Background())
+	return item, shutdown
+}
+
+// GetContext is like Get but also returns a context derived from ctx holding
+// the span of the processing of the item, to be used by the reconcile loop.
+func (q *Queue) GetContext(ctx context.Context) (context.Context, any, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+	if shutdown {
+		return ctx, item, shutdown
+	}
+	now := time.Now()
+	q.mu.Lock()
+	a, ok := q.added[item]
+	delete(q.added, item)
+	q.mu.Unlock()
+
+	var parent ddtrace.SpanContext
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		parent = span.Context()
+	}
+	if ok {
+		if a.parent != nil {
+			parent = a.parent
+		}
+		opts := append(q.spanOptions(item), tracer.StartTime(a.time))
+		if parent != nil {
+			opts = append(opts, tracer.ChildOf(parent))
+		}
+		wait := tracer.StartSpan(spanWait, opts...)
+		wait.Finish(tracer.FinishTime(now))
+		parent = wait.Context()
+	}
+	opts := append(q.spanOptions(item),
+		tracer.StartTime(now),
+		tracer.Tag(tagRetries, q.NumRequeues(item)),
+	)
+	if parent != nil {
+		opts = append(opts, tracer.ChildOf(parent))
+	}
+	span := tracer.StartSpan(spanProcess, opts...)
+	q.mu.Lock()
+	q.processing[item] = span
+	q.mu.Unlock()
+	return tracer.ContextWithSpan(ctx, span), item, shutdown
+}
+
+// Done marks the given item as processed, and finishes its processing span.
+func (q *Queue) Done(item any) {
+	q.mu.Lock()
+	span, ok := q.processing[item]
+	delete(q.processing, item)
+	q.mu.Unlock()
+	if ok {
+		span.Finish()
+	}
+	q.RateLimitingInterface.Done(item)
+}
+
+func (q *Queue) spanOptions(item any) []ddtrace.StartSpanOption {
+	opts := []ddtrace.StartSpanOption{
+		tracer.Tag(ext.Component, componentName),
+		tracer.Measured(),
+	}
+	if q.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(q.cfg.serviceName))
+	}
+	if q.cfg.queueName != "" {
+		opts = append(opts,
+			tracer.ResourceName(q.cfg.queueName),
+			tracer.Tag(tagQueueName, q.cfg.queueName),
+		)
+	}
+	switch key := item.(type) {
+	case string:
+		opts = append(opts, tracer.Tag(tagKey, key))
+	case fmt.Stringer:
+		opts = append(opts, tracer.Tag(tagKey, key.String()))
+	}
+	if !math.IsNaN(q.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, q.cfg.analyticsRate))
+	}
+	return opts
+}
@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package workqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newQueue(opts ...Option) *Queue {
+	rl := workqueue.NewItemFastSlowRateLimiter(time.Millisecond, time.Millisecond, 10)
+	return WrapQueue(workqueue.NewNamedRateLimitingQueue(rl, "test"), opts...)
+}
+
+func TestQueue(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	q := newQueue(WithQueueName("test"), WithServiceName("controller"))
+	defer q.ShutDown()
+	q.Add("default/pod")
+	// Duplicated items are processed once
+	q.Add("default/pod")
+
+	ctx, item, shutdown := q.GetContext(context.Background())
+	require.False(t, shutdown)
+	assert.Equal(t, "default/pod", item)
+	child, _ := tracer.StartSpanFromContext(ctx, "reconcile")
+	child.Finish()
+	q.Forget(item)
+	q.Done(item)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	wait, reconcile, process := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "k8s.workqueue.wait", wait.OperationName())
+	assert.Equal(t, "k8s.workqueue.process", process.OperationName())
+	for _, s := range []mocktracer.Span{wait, process} {
+		assert.Equal(t, "test", s.Tag(ext.ResourceName))
+		assert.Equal(t, "test", s.Tag(tagQueueName))
+		assert.Equal(t, "controller", s.Tag(ext.ServiceName))
+		assert.Equal(t, "default/pod", s.Tag(tagKey))
+		assert.Equal(t, componentName, s.Tag(ext.Component))
+	}
+	assert.Equal(t, 0, process.Tag(tagRetries))
+	assert.Nil(t, process.Tag(tagRequeued))
+	assert.Equal(t, wait.SpanID(), process.ParentID())
+	assert.Equal(t, process.SpanID(), reconcile.ParentID())
+	assert.Equal(t, wait.FinishTime(), process.StartTime())
+}
+
+func TestQueueAddContext(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	q := newQueue()
+	defer q.ShutDown()
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "informer")
+	q.AddContext(ctx, "default/pod")
+	parent.Finish()
+
+	item, _ := q.Get()
+	q.Done(item)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	wait, process := spans[1], spans[2]
+	assert.Equal(t, parent.Context().SpanID(), wait.ParentID())
+	assert.Equal(t, wait.SpanID(), process.ParentID())
+	assert.Equal(t, "k8s.workqueue.process", process.Tag(ext.ResourceName))
+	assert.Nil(t, process.Tag(tagQueueName))
+}
+
+func TestQueueRetries(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	q := newQueue()
+	defer q.ShutDown()
+	q.Add("default/pod")
+
+	item, _ := q.Get()
+	// The processing failed
+	q.AddRateLimited(item)
+	q.Done(item)
+
+	item, _ = q.Get()
+	q.Forget(item)
+	q.Done(item)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	process1, wait2, process2 := spans[1], spans[2], spans[3]
+	assert.Equal(t, true, process1.Tag(tagRequeued))
+	assert.Equal(t, 0, process1.Tag(tagRetries))
+	assert.Equal(t, process1.SpanID(), wait2.ParentID())
+	assert.Equal(t, process1.TraceID(), process2.TraceID())
+	assert.Equal(t, 1, process2.Tag(tagRetries))
+}
+
+func TestQueueShutDown(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	q := newQueue()
+	q.ShutDown()
+	_, shutdown := q.Get()
+	assert.True(t, shutdown)
+	assert.Empty(t, mt.FinishedSpans())
+}
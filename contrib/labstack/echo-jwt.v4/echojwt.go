@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package echojwt provides an AppSec adapter of the labstack/echo-jwt package
+// (https://github.com/labstack/echo-jwt) tracking the authenticated users and
+// the failed authentications.
+package echojwt // import "github.com/nowfred/dd-trace-go/contrib/labstack/echo-jwt.v4"
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"github.com/golang-jwt/jwt/v5"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+const componentName = "labstack/echo-jwt.v4"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("github.com/labstack/echo-jwt/v4")
+}
+
+// blockedUserKey is the echo context key of the error returned when the
+// authenticated user is blocked.
+const blockedUserKey = "dd-trace-go:appsec:echojwt:blocked"
+
+// WithConfig returns the echo-jwt middleware of the given configuration, along
+// with the automated user events tracking when AppSec is enabled with the env
+// var DD_APPSEC_AUTOMATED_USER_EVENTS_TRACKING set to safe or extended:
+//   - The user ID of valid tokens is set as the authenticated user of the
+//     request, which is blocked when the user is in your denylist.
+//   - Invalid tokens are reported as user login failure events, along with
+//     their user ID when it can be read out of the token.
+//
+// The user IDs are anonymized in safe mode. The middleware must be used after
+// the echo tracing middleware of contrib/labstack/echo.v4. It panics when the
+// configuration is invalid, as echojwt.WithConfig does.
+func WithConfig(cfg echojwt.Config, opts ...Option) echo.MiddlewareFunc {
+	c := new(config)
+	defaults(c)
+	for _, fn := range opts {
+		fn(c)
+	}
+
+	successHandler := cfg.SuccessHandler
+	cfg.SuccessHandler = func(ctx echo.Context) {
+		if successHandler != nil {
+			successHandler(ctx)
+		}
+		if err := c.trackUser(ctx, ctx.Get(contextKey(cfg))); err != nil {
+			ctx.Set(blockedUserKey, err)
+		}
+	}
+	errorHandler := cfg.ErrorHandler
+	cfg.ErrorHandler = func(ctx echo.Context, err error) error {
+		c.trackLoginFailure(ctx, err)
+		if errorHandler != nil {
+			return errorHandler(ctx, err)
+		}
+		// Default echojwt error response when no error handler is configured
+		var parsingErr *echojwt.TokenParsingError
+		if errors.As(err, &parsingErr) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt").SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt").SetInternal(err)
+	}
+
+	mw := echojwt.WithConfig(cfg)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return mw(func(ctx echo.Context) error {
+			if err, ok := ctx.Get(blockedUserKey).(error); ok {
+				return err
+			}
+			return next(ctx)
+		})
+	}
+}
+
+// contextKey returns the echo context key of the token set by the echojwt
+// middleware of the given configuration.
+func contextKey(cfg echojwt.Config) string {
+	if cfg.ContextKey == "" {
+		return "user"
+	}
+	return cfg.ContextKey
+}
+
+// trackUser sets the user ID of the given token as the authenticated user of
+// the request, and returns an error when the user is blocked.
+func (c *config) trackUser(ctx echo.Context, token any) error {
+	mode := appsec.UserEventsMode()
+	if !mode.Enabled() {
+		return nil
+	}
+	t, _ := token.(*jwt.Token)
+	uid := mode.UserID(c.userID(t))
+	span, ok := tracer.SpanFromContext(ctx.Request().Context())
+	if uid == "" || !ok {
+		return nil
+	}
+	trace.SetAutoUserTags(span, uid, string(mode))
+	return sharedsec.MonitorUser(ctx.Request().Context(), uid)
+}
+
+// trackLoginFailure reports the invalid tokens as user login failures.
+// Missing tokens are not reported.
+func (c *config) trackLoginFailure(ctx echo.Context, err error) {
+	mode := appsec.UserEventsMode()
+	if !mode.Enabled() {
+		return
+	}
+	var parsingErr *echojwt.TokenParsingError
+	if !errors.As(err, &parsingErr) {
+		return
+	}
+	span, ok := tracer.SpanFromContext(ctx.Request().Context())
+	if !ok {
+		return
+	}
+	var uid string
+	if tokenErr := (*echojwt.TokenError)(nil); errors.As(err, &tokenErr) {
+		uid = mode.UserID(c.userID(tokenErr.Token))
+	}
+	trace.SetAutoUserLoginFailureTags(span, uid, string(mode))
+}
+
+// userID returns the user ID claim of the token, or an empty string when the
+// claim isn't a string.
+func (c *config) userID(token *jwt.Token) string {
+	if token == nil || token.Claims == nil {
+		return ""
+	}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		id, _ := claims[c.userIDClaim].(string)
+		return id
+	}
+	if c.userIDClaim == "sub" {
+		id, _ := token.Claims.GetSubject()
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package echojwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echotrace "github.com/nowfred/dd-trace-go/contrib/labstack/echo.v4"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	appsecconfig "github.com/nowfred/dd-trace-go/internal/appsec/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var signingKey = []byte("secret")
+
+func newToken(t *testing.T, key []byte, subject string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": subject}).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestUserEvents(t *testing.T) {
+	t.Setenv(appsecconfig.EnvUserEventsTracking, "extended")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	e := echo.New()
+	e.Use(echotrace.Middleware())
+	e.Use(WithConfig(echojwt.Config{SigningKey: signingKey}))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "Hello World!\n")
+	})
+
+	for _, tc := range []struct {
+		name     string
+		token    string
+		status   int
+		expected map[string]any
+	}{
+		{
+			name:     "valid",
+			token:    newToken(t, signingKey, "user"),
+			status:   http.StatusOK,
+			expected: map[string]any{"usr.id": "user", "_dd.appsec.user.collection_mode": "extended"},
+		},
+		{
+			name:   "invalid",
+			token:  newToken(t, []byte("other secret"), "user"),
+			status: http.StatusUnauthorized,
+			expected: map[string]any{
+				"appsec.events.users.login.failure.track":  true,
+				"appsec.events.users.login.failure.usr.id": "user",
+			},
+		},
+		{
+			name:   "missing",
+			status: http.StatusUnauthorized,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, tc.status, rec.Code)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			for k, v := range tc.expected {
+				require.Equal(t, v, spans[0].Tag(k), k)
+			}
+			if tc.expected == nil {
+				require.Nil(t, spans[0].Tag("usr.id"))
+				require.Nil(t, spans[0].Tag("appsec.events.users.login.failure.track"))
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package echojwt_test
+
+import (
+	echojwttrace "github.com/nowfred/dd-trace-go/contrib/labstack/echo-jwt.v4"
+	echotrace "github.com/nowfred/dd-trace-go/contrib/labstack/echo.v4"
+
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// To track the authenticated users and the failed authentications, replace
+// echojwt.WithConfig with the adapter after the echo tracing middleware.
+func Example() {
+	r := echo.New()
+	r.Use(echotrace.Middleware())
+	r.Use(echojwttrace.WithConfig(echojwt.Config{SigningKey: []byte("secret")}, echojwttrace.WithUserIDClaim("sub")))
+
+	r.GET("/hello", func(c echo.Context) error {
+		return c.String(200, "hello world!")
+	})
+	r.Start(":8080")
+}
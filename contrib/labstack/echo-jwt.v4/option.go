@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package echojwt
+
+type config struct {
+	userIDClaim string
+}
+
+// Option represents an option that can be passed to WithConfig.
+type Option func(*config)
+
+func defaults(cfg *config) {
+	cfg.userIDClaim = "sub"
+}
+
+// WithUserIDClaim sets the name of the token claim holding the user ID.
+// Defaults to the subject claim "sub". Claims other than the subject can only
+// be read out of jwt.MapClaims tokens.
+func WithUserIDClaim(claim string) Option {
+	return func(cfg *config) {
+		cfg.userIDClaim = claim
+	}
+}
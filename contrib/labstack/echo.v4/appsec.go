@@ -6,6 +6,8 @@
 package echo
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -23,6 +25,14 @@ func withAppSec(next echo.HandlerFunc, span tracer.Span) echo.HandlerFunc {
 		var err error
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			c.SetRequest(r)
+			if httpsec.IsResponseBodyMonitored(w) {
+				// The handlers write the response through c.Response() rather than w, which must be used for the
+				// response to be monitored
+				res := c.Response()
+				writer := res.Writer
+				res.Writer = &appsecResponseWriter{ResponseWriter: writer, monitor: w}
+				defer func() { res.Writer = writer }()
+			}
 			err = next(c)
 			// If the error is a monitoring one, it means appsec actions will take care of writing the response
 			// and handling the error. Don't call the echo error handler in this case
@@ -31,7 +41,7 @@ func withAppSec(next echo.HandlerFunc, span tracer.Span) echo.HandlerFunc {
 			}
 		})
 		// Wrap the echo response to allow monitoring of the response status code in httpsec.WrapHandler()
-		httpsec.WrapHandler(handler, span, params).ServeHTTP(&statusResponseWriter{Response: c.Response()}, c.Request())
+		httpsec.WrapHandler(handler, span, params).ServeHTTP(newStatusResponseWriter(c.Response()), c.Request())
 		// If an error occurred, wrap it under an echo.HTTPError. We need to do this so that APM doesn't override
 		// the response code tag with 500 in case it doesn't recognize the error type.
 		if _, ok := err.(*echo.HTTPError); !ok && err != nil {
@@ -45,12 +55,96 @@ func withAppSec(next echo.HandlerFunc, span tracer.Span) echo.HandlerFunc {
 }
 
 // statusResponseWriter wraps an echo response to allow tracking/retrieving its status code through a Status() method
-// without having to rely on the echo error handlers
+// without having to rely on the echo error handlers. The response is written to the underlying http.ResponseWriter of
+// the echo response so that httpsec.WrapHandler can still write it when the echo response writer is replaced in order
+// to monitor the response written by the handlers.
 type statusResponseWriter struct {
 	*echo.Response
+	writer http.ResponseWriter
+}
+
+func newStatusResponseWriter(res *echo.Response) *statusResponseWriter {
+	return &statusResponseWriter{Response: res, writer: res.Writer}
 }
 
 // Status returns the status code of the response
 func (w *statusResponseWriter) Status() int {
 	return w.Response.Status
 }
+
+// Header implements http.ResponseWriter.
+func (w *statusResponseWriter) Header() http.Header {
+	return w.writer.Header()
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *statusResponseWriter) WriteHeader(code int) {
+	w.Response.Status = code
+	w.Response.Committed = true
+	w.writer.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if !w.Response.Committed {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.writer.Write(b)
+	w.Response.Size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer does.
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.writer.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// appsecResponseWriter replaces the echo response writer to write the response
+// through the AppSec monitoring of httpsec.WrapHandler, which buffers it until
+// the handler operation is finished.
+type appsecResponseWriter struct {
+	http.ResponseWriter
+	monitor http.ResponseWriter
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *appsecResponseWriter) WriteHeader(code int) {
+	w.monitor.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *appsecResponseWriter) Write(b []byte) (int, error) {
+	return w.monitor.Write(b)
+}
+
+// Flush implements http.Flusher, as expected by echo.Response.Flush(), when
+// the monitored writer does.
+func (w *appsecResponseWriter) Flush() {
+	if f, ok := w.monitor.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, as expected by echo.Response.Hijack(),
+// when the original writer does.
+func (w *appsecResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the original http.ResponseWriter.
+func (w *appsecResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -629,3 +629,26 @@ func TestBlocking(t *testing.T) {
 		})
 	}
 }
+
+// basicResponseWriter only implements http.ResponseWriter.
+type basicResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestResponseWritersUnsupportedInterfaces(t *testing.T) {
+	w := basicResponseWriter{httptest.NewRecorder()}
+
+	t.Run("status", func(t *testing.T) {
+		sw := newStatusResponseWriter(echo.NewResponse(w, echo.New()))
+		require.NotPanics(t, sw.Flush)
+		_, _, err := sw.Hijack()
+		require.ErrorIs(t, err, http.ErrNotSupported)
+	})
+
+	t.Run("appsec", func(t *testing.T) {
+		aw := &appsecResponseWriter{ResponseWriter: w, monitor: w}
+		require.NotPanics(t, aw.Flush)
+		_, _, err := aw.Hijack()
+		require.ErrorIs(t, err, http.ErrNotSupported)
+	})
+}
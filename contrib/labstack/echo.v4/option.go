@@ -9,6 +9,7 @@ import (
 	"errors"
 	"math"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -39,7 +40,7 @@ type IgnoreRequestFunc func(c echo.Context) bool
 func defaults(cfg *config) {
 	cfg.serviceName = namingschema.ServiceName(defaultServiceName)
 	cfg.analyticsRate = math.NaN()
-	cfg.isStatusError = isServerError
+	cfg.isStatusError = httptrace.IsServerError
 	cfg.headerTags = globalconfig.HeaderTagMap()
 	cfg.tags = make(map[string]interface{})
 	cfg.translateError = func(err error) (*echo.HTTPError, bool) {
@@ -114,10 +115,6 @@ func WithStatusCheck(fn func(statusCode int) bool) Option {
 	}
 }
 
-func isServerError(statusCode int) bool {
-	return statusCode >= 500 && statusCode < 600
-}
-
 // WithHeaderTags enables the integration to attach HTTP request headers as span tags.
 // Warning:
 // Using this feature can risk exposing sensitive data such as authorization tokens to Datadog.
@@ -8,6 +8,7 @@ package echo
 import (
 	"math"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/internal"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
@@ -35,7 +36,7 @@ func defaults(cfg *config) {
 		cfg.analyticsRate = math.NaN()
 	}
 	cfg.headerTags = globalconfig.HeaderTagMap()
-	cfg.isStatusError = isServerError
+	cfg.isStatusError = httptrace.IsServerError
 }
 
 // WithServiceName sets the given service name for the system.
@@ -85,10 +86,6 @@ func WithStatusCheck(fn func(statusCode int) bool) Option {
 	}
 }
 
-func isServerError(statusCode int) bool {
-	return statusCode >= 500 && statusCode < 600
-}
-
 // WithHeaderTags enables the integration to attach HTTP request headers as span tags.
 // Warning:
 // Using this feature can risk exposing sensitive data such as authorization tokens to Datadog.
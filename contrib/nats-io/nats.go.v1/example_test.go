@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package nats_test
+
+import (
+	"log"
+	"time"
+
+	natstrace "github.com/nowfred/dd-trace-go/contrib/nats-io/nats.go.v1"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+)
+
+func ExampleConn() {
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn := natstrace.WrapConn(nc, natstrace.WithServiceName("my-service"))
+	defer conn.Close()
+
+	_, err = conn.Subscribe("some-subject", func(msg *nats.Msg) {
+		// create a child span using the span context found in the message headers
+		spanContext, err := natstrace.ExtractSpanContext(msg)
+		if err != nil {
+			return
+		}
+		s := tracer.StartSpan("child-span", tracer.ChildOf(spanContext))
+		defer s.Finish()
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := conn.Publish("some-subject", []byte("hello")); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleSubscription_Fetch() {
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn := natstrace.WrapConn(nc, natstrace.WithDataStreams())
+	defer conn.Close()
+
+	js, err := conn.JetStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+	sub, err := js.PullSubscribe("some-subject", "some-durable")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	msgs, err := sub.Fetch(10, nats.MaxWait(time.Second))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, msg := range msgs {
+		msg.Ack()
+	}
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package nats
+
+import (
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+)
+
+// A MessageCarrier injects and extracts traces from a nats.Msg.
+type MessageCarrier struct {
+	msg *nats.Msg
+}
+
+var _ interface {
+	tracer.TextMapReader
+	tracer.TextMapWriter
+} = (*MessageCarrier)(nil)
+
+// NewMessageCarrier creates a new MessageCarrier.
+func NewMessageCarrier(msg *nats.Msg) MessageCarrier {
+	return MessageCarrier{msg}
+}
+
+// ForeachKey iterates over every header.
+func (c MessageCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c.msg.Header {
+		for _, v := range vs {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Set sets a header.
+func (c MessageCarrier) Set(key, val string) {
+	if c.msg.Header == nil {
+		c.msg.Header = nats.Header{}
+	}
+	c.msg.Header.Set(key, val)
+}
+
+// ExtractSpanContext retrieves the SpanContext from a nats.Msg.
+func ExtractSpanContext(msg *nats.Msg) (ddtrace.SpanContext, error) {
+	return tracer.Extract(NewMessageCarrier(msg))
+}
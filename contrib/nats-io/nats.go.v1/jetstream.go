@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+)
+
+// JetStreamContext wraps a nats.JetStreamContext so that published and
+// received messages are traced.
+type JetStreamContext struct {
+	nats.JetStreamContext
+	conn *nats.Conn
+	cfg  *config
+}
+
+// Publish calls nats.JetStreamContext.PublishMsg with a message built from subj
+// and data, and traces the request.
+func (js *JetStreamContext) Publish(subj string, data []byte, opts ...nats.PubOpt) (*nats.PubAck, error) {
+	return js.PublishMsgWithContext(context.Background(), &nats.Msg{Subject: subj, Data: data}, opts...)
+}
+
+// PublishMsg calls nats.JetStreamContext.PublishMsg and traces the request.
+func (js *JetStreamContext) PublishMsg(msg *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error) {
+	return js.PublishMsgWithContext(context.Background(), msg, opts...)
+}
+
+// PublishMsgWithContext is like PublishMsg, but the started span is a child of
+// any span found in ctx.
+func (js *JetStreamContext) PublishMsgWithContext(ctx context.Context, msg *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error) {
+	if msg == nil {
+		return js.JetStreamContext.PublishMsg(msg, opts...)
+	}
+	span := startProducerSpan(ctx, js.cfg, js.conn, "Publish "+msg.Subject, msg)
+	ack, err := js.JetStreamContext.PublishMsg(msg, opts...)
+	if ack != nil {
+		span.SetTag(ext.MessagingNATSStream, ack.Stream)
+		span.SetTag(ext.MessagingNATSSequence, ack.Sequence)
+	}
+	span.Finish(tracer.WithError(err))
+	return ack, err
+}
+
+// Subscribe calls nats.JetStreamContext.Subscribe and traces every message
+// handled by cb. The span context of the consume span is re-injected into the
+// message headers so that it can be extracted by cb.
+func (js *JetStreamContext) Subscribe(subj string, cb nats.MsgHandler, opts ...nats.SubOpt) (*nats.Subscription, error) {
+	return js.JetStreamContext.Subscribe(subj, wrapHandler(js.cfg, cb), opts...)
+}
+
+// QueueSubscribe calls nats.JetStreamContext.QueueSubscribe and traces every
+// message handled by cb. The span context of the consume span is re-injected
+// into the message headers so that it can be extracted by cb.
+func (js *JetStreamContext) QueueSubscribe(subj, queue string, cb nats.MsgHandler, opts ...nats.SubOpt) (*nats.Subscription, error) {
+	return js.JetStreamContext.QueueSubscribe(subj, queue, wrapHandler(js.cfg, cb), opts...)
+}
+
+// PullSubscribe calls nats.JetStreamContext.PullSubscribe and wraps the
+// resulting subscription so that fetched messages are traced.
+func (js *JetStreamContext) PullSubscribe(subj, durable string, opts ...nats.SubOpt) (*Subscription, error) {
+	sub, err := js.JetStreamContext.PullSubscribe(subj, durable, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		Subscription: sub,
+		cfg:          js.cfg,
+	}, nil
+}
+
+// Subscription wraps a pull based *nats.Subscription. Each message returned by
+// Fetch is traced by a span which is finished on the next call to Fetch, or
+// when the subscription is unsubscribed or drained.
+type Subscription struct {
+	*nats.Subscription
+	cfg *config
+
+	mu   sync.Mutex
+	prev []ddtrace.Span
+}
+
+// Fetch calls nats.Subscription.Fetch and traces every returned message. The
+// span context of each consume span is re-injected into the message headers.
+func (s *Subscription) Fetch(batch int, opts ...nats.PullOpt) ([]*nats.Msg, error) {
+	s.finishPrev()
+	msgs, err := s.Subscription.Fetch(batch, opts...)
+	if len(msgs) == 0 {
+		return msgs, err
+	}
+	spans := make([]ddtrace.Span, len(msgs))
+	for i, msg := range msgs {
+		spans[i] = startConsumerSpan(s.cfg, msg)
+	}
+	s.mu.Lock()
+	s.prev = spans
+	s.mu.Unlock()
+	return msgs, err
+}
+
+// Unsubscribe calls nats.Subscription.Unsubscribe and finishes any remaining
+// span.
+func (s *Subscription) Unsubscribe() error {
+	err := s.Subscription.Unsubscribe()
+	s.finishPrev()
+	return err
+}
+
+// Drain calls nats.Subscription.Drain and finishes any remaining span.
+func (s *Subscription) Drain() error {
+	err := s.Subscription.Drain()
+	s.finishPrev()
+	return err
+}
+
+func (s *Subscription) finishPrev() {
+	s.mu.Lock()
+	prev := s.prev
+	s.prev = nil
+	s.mu.Unlock()
+	for _, span := range prev {
+		span.Finish()
+	}
+}
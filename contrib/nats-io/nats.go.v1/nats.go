@@ -0,0 +1,255 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+// Package nats provides functions to trace the nats-io/nats.go package (https://github.com/nats-io/nats.go).
+package nats // import "github.com/nowfred/dd-trace-go/contrib/nats-io/nats.go.v1"
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/datastreams/options"
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"github.com/nats-io/nats.go"
+)
+
+const componentName = "nats-io/nats.go.v1"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("github.com/nats-io/nats.go")
+}
+
+// Conn wraps a *nats.Conn so that published, requested and received messages
+// are traced. Trace context is propagated through message headers, which
+// requires a server version supporting them (nats-server v2.2.0+). Messages are
+// still traced, without propagation, when headers are not supported.
+type Conn struct {
+	*nats.Conn
+	cfg *config
+}
+
+// WrapConn wraps a *nats.Conn so that messages are traced.
+func WrapConn(nc *nats.Conn, opts ...Option) *Conn {
+	cfg := newConfig(opts...)
+	log.Debug("contrib/nats-io/nats.go.v1: Wrapping Conn: %#v", cfg)
+	return &Conn{
+		Conn: nc,
+		cfg:  cfg,
+	}
+}
+
+// Publish calls nats.Conn.PublishMsg with a message built from subj and data,
+// and traces the request.
+func (c *Conn) Publish(subj string, data []byte) error {
+	return c.PublishMsgWithContext(context.Background(), &nats.Msg{Subject: subj, Data: data})
+}
+
+// PublishWithContext is like Publish, but the started span is a child of any
+// span found in ctx.
+func (c *Conn) PublishWithContext(ctx context.Context, subj string, data []byte) error {
+	return c.PublishMsgWithContext(ctx, &nats.Msg{Subject: subj, Data: data})
+}
+
+// PublishMsg calls nats.Conn.PublishMsg and traces the request.
+func (c *Conn) PublishMsg(msg *nats.Msg) error {
+	return c.PublishMsgWithContext(context.Background(), msg)
+}
+
+// PublishMsgWithContext is like PublishMsg, but the started span is a child of
+// any span found in ctx.
+func (c *Conn) PublishMsgWithContext(ctx context.Context, msg *nats.Msg) error {
+	if msg == nil {
+		return c.Conn.PublishMsg(msg)
+	}
+	span := startProducerSpan(ctx, c.cfg, c.Conn, "Publish "+msg.Subject, msg)
+	err := c.Conn.PublishMsg(msg)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// Request calls nats.Conn.RequestMsg with a message built from subj and data,
+// and traces the request.
+func (c *Conn) Request(subj string, data []byte, timeout time.Duration) (*nats.Msg, error) {
+	return c.RequestMsg(&nats.Msg{Subject: subj, Data: data}, timeout)
+}
+
+// RequestMsg calls nats.Conn.RequestMsg and traces the request.
+func (c *Conn) RequestMsg(msg *nats.Msg, timeout time.Duration) (*nats.Msg, error) {
+	if msg == nil {
+		return c.Conn.RequestMsg(msg, timeout)
+	}
+	span := startProducerSpan(context.Background(), c.cfg, c.Conn, "Request "+msg.Subject, msg)
+	resp, err := c.Conn.RequestMsg(msg, timeout)
+	span.Finish(tracer.WithError(err))
+	return resp, err
+}
+
+// RequestWithContext calls nats.Conn.RequestMsgWithContext with a message built
+// from subj and data, and traces the request.
+func (c *Conn) RequestWithContext(ctx context.Context, subj string, data []byte) (*nats.Msg, error) {
+	return c.RequestMsgWithContext(ctx, &nats.Msg{Subject: subj, Data: data})
+}
+
+// RequestMsgWithContext calls nats.Conn.RequestMsgWithContext and traces the
+// request. The started span is a child of any span found in ctx.
+func (c *Conn) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	if msg == nil {
+		return c.Conn.RequestMsgWithContext(ctx, msg)
+	}
+	span := startProducerSpan(ctx, c.cfg, c.Conn, "Request "+msg.Subject, msg)
+	resp, err := c.Conn.RequestMsgWithContext(ctx, msg)
+	span.Finish(tracer.WithError(err))
+	return resp, err
+}
+
+// Subscribe calls nats.Conn.Subscribe and traces every message handled by cb.
+// The span context of the consume span is re-injected into the message headers
+// so that it can be extracted by cb.
+func (c *Conn) Subscribe(subj string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.Subscribe(subj, wrapHandler(c.cfg, cb))
+}
+
+// QueueSubscribe calls nats.Conn.QueueSubscribe and traces every message handled
+// by cb. The span context of the consume span is re-injected into the message
+// headers so that it can be extracted by cb.
+func (c *Conn) QueueSubscribe(subj, queue string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.QueueSubscribe(subj, queue, wrapHandler(c.cfg, cb))
+}
+
+// JetStream calls nats.Conn.JetStream and wraps the resulting JetStreamContext.
+func (c *Conn) JetStream(opts ...nats.JSOpt) (*JetStreamContext, error) {
+	js, err := c.Conn.JetStream(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &JetStreamContext{
+		JetStreamContext: js,
+		conn:             c.Conn,
+		cfg:              c.cfg,
+	}, nil
+}
+
+func wrapHandler(cfg *config, cb nats.MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		span := startConsumerSpan(cfg, msg)
+		defer span.Finish()
+		cb(msg)
+	}
+}
+
+// startProducerSpan starts the span of a message published on nc. The span
+// context and the data streams pathway are only propagated through the message
+// headers when nc supports them, as publishing a message with headers fails
+// otherwise.
+func startProducerSpan(ctx context.Context, cfg *config, nc *nats.Conn, resource string, msg *nats.Msg) ddtrace.Span {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.producerServiceName),
+		tracer.ResourceName(resource),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemNATS),
+		tracer.Tag(ext.MessagingDestinationName, msg.Subject),
+	}
+	if !math.IsNaN(cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, cfg.analyticsRate))
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, cfg.producerSpanName, opts...)
+	if !nc.HeadersSupported() {
+		return span
+	}
+	if err := tracer.Inject(span.Context(), NewMessageCarrier(msg)); err != nil {
+		log.Debug("contrib/nats-io/nats.go.v1: Failed to inject span context into message headers: %v", err)
+	}
+	setProduceCheckpoint(cfg.dataStreamsEnabled, msg)
+	return span
+}
+
+func startConsumerSpan(cfg *config, msg *nats.Msg) ddtrace.Span {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.consumerServiceName),
+		tracer.ResourceName("Consume " + msg.Subject),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemNATS),
+		tracer.Tag(ext.MessagingDestinationName, msg.Subject),
+		tracer.Measured(),
+	}
+	if !math.IsNaN(cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, cfg.analyticsRate))
+	}
+	group := ""
+	if msg.Sub != nil && msg.Sub.Queue != "" {
+		group = msg.Sub.Queue
+		opts = append(opts, tracer.Tag(ext.MessagingNATSQueueGroup, group))
+	}
+	if meta, err := msg.Metadata(); err == nil {
+		// the message was delivered by a JetStream consumer
+		group = meta.Consumer
+		opts = append(opts,
+			tracer.Tag(ext.MessagingNATSStream, meta.Stream),
+			tracer.Tag(ext.MessagingNATSConsumer, meta.Consumer),
+			tracer.Tag(ext.MessagingNATSSequence, meta.Sequence.Stream),
+		)
+	}
+	carrier := NewMessageCarrier(msg)
+	if spanctx, err := tracer.Extract(carrier); err == nil {
+		opts = append(opts, tracer.ChildOf(spanctx))
+	}
+	span := tracer.StartSpan(cfg.consumerSpanName, opts...)
+	// reinject the span context so message handlers can pick it up
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		log.Debug("contrib/nats-io/nats.go.v1: Failed to inject span context into message headers: %v", err)
+	}
+	setConsumeCheckpoint(cfg.dataStreamsEnabled, group, msg)
+	return span
+}
+
+func setProduceCheckpoint(enabled bool, msg *nats.Msg) {
+	if !enabled || msg == nil {
+		return
+	}
+	edges := []string{"direction:out", "topic:" + msg.Subject, "type:nats"}
+	carrier := NewMessageCarrier(msg)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func setConsumeCheckpoint(enabled bool, group string, msg *nats.Msg) {
+	if !enabled || msg == nil {
+		return
+	}
+	edges := []string{"direction:in", "topic:" + msg.Subject, "type:nats"}
+	if group != "" {
+		edges = append(edges, "group:"+group)
+	}
+	carrier := NewMessageCarrier(msg)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func getMsgSize(msg *nats.Msg) (size int64) {
+	for k, vs := range msg.Header {
+		for _, v := range vs {
+			size += int64(len(k) + len(v))
+		}
+	}
+	return size + int64(len(msg.Data))
+}
@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSubject = "dd.test"
+	testStream  = "DDTEST"
+	testDurable = "ddtest"
+)
+
+func newTestConn(t *testing.T, opts ...Option) *Conn {
+	sopts := natsserver.DefaultTestOptions
+	sopts.JetStream = true
+	sopts.StoreDir = t.TempDir()
+	return newTestConnWithServerOptions(t, sopts, opts...)
+}
+
+func newTestConnWithServerOptions(t *testing.T, sopts server.Options, opts ...Option) *Conn {
+	sopts.Port = -1
+	srv := natsserver.RunServer(&sopts)
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return WrapConn(nc, opts...)
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	nc := newTestConn(t, WithAnalyticsRate(0.5))
+	received := make(chan *nats.Msg, 1)
+	sub, err := nc.QueueSubscribe(testSubject, "workers", func(msg *nats.Msg) {
+		received <- msg
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	err = nc.PublishWithContext(ctx, testSubject, []byte("hello"))
+	require.NoError(t, err)
+	root.Finish()
+
+	var msg *nats.Msg
+	select {
+	case msg = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	assert.Equal(t, "hello", string(msg.Data))
+	require.NoError(t, nc.Flush())
+
+	// the consume span is finished right after the handler returns
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	spans := mt.FinishedSpans()
+	var produce, consume mocktracer.Span
+	for _, s := range spans {
+		switch s.OperationName() {
+		case "nats.publish":
+			produce = s
+		case "nats.consume":
+			consume = s
+		}
+	}
+	require.NotNil(t, produce)
+	require.NotNil(t, consume)
+
+	assert.Equal(t, root.Context().SpanID(), produce.ParentID())
+	assert.Equal(t, "nats", produce.Tag(ext.ServiceName))
+	assert.Equal(t, "Publish "+testSubject, produce.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeMessageProducer, produce.Tag(ext.SpanType))
+	assert.Equal(t, ext.SpanKindProducer, produce.Tag(ext.SpanKind))
+	assert.Equal(t, componentName, produce.Tag(ext.Component))
+	assert.Equal(t, ext.MessagingSystemNATS, produce.Tag(ext.MessagingSystem))
+	assert.Equal(t, testSubject, produce.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, 0.5, produce.Tag(ext.EventSampleRate))
+
+	assert.Equal(t, produce.SpanID(), consume.ParentID())
+	assert.Equal(t, produce.TraceID(), consume.TraceID())
+	assert.Equal(t, "nats", consume.Tag(ext.ServiceName))
+	assert.Equal(t, "Consume "+testSubject, consume.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, consume.Tag(ext.SpanKind))
+	assert.Equal(t, "workers", consume.Tag(ext.MessagingNATSQueueGroup))
+
+	// the consume span context is available to the handler
+	spanctx, err := ExtractSpanContext(msg)
+	require.NoError(t, err)
+	assert.Equal(t, consume.SpanID(), spanctx.SpanID())
+}
+
+func TestRequest(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	nc := newTestConn(t)
+	sub, err := nc.Subscribe(testSubject, func(msg *nats.Msg) {
+		msg.Respond([]byte("pong"))
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	resp, err := nc.Request(testSubject, []byte("ping"), 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(resp.Data))
+
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	spans := mt.FinishedSpans()
+	var request, consume mocktracer.Span
+	for _, s := range spans {
+		switch s.OperationName() {
+		case "nats.publish":
+			request = s
+		case "nats.consume":
+			consume = s
+		}
+	}
+	require.NotNil(t, request)
+	require.NotNil(t, consume)
+	assert.Equal(t, "Request "+testSubject, request.Tag(ext.ResourceName))
+	assert.Equal(t, request.SpanID(), consume.ParentID())
+
+	t.Run("error", func(t *testing.T) {
+		mt.Reset()
+		_, err := nc.Request("dd.nobody", []byte("ping"), 100*time.Millisecond)
+		require.Error(t, err)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, err, spans[0].Tag(ext.Error))
+	})
+}
+
+func TestPublishNoHeaderSupport(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	sopts := natsserver.DefaultTestOptions
+	sopts.NoHeaderSupport = true
+	nc := newTestConnWithServerOptions(t, sopts, WithDataStreams())
+	require.False(t, nc.HeadersSupported())
+
+	msg := &nats.Msg{Subject: testSubject, Data: []byte("hello")}
+	require.NoError(t, nc.PublishMsg(msg))
+	assert.Nil(t, msg.Header)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "nats.publish", spans[0].OperationName())
+	assert.Nil(t, spans[0].Tag(ext.Error))
+}
+
+func TestJetStreamPullSubscribe(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	nc := newTestConn(t, WithDataStreams())
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	_, err = js.AddStream(&nats.StreamConfig{Name: testStream, Subjects: []string{testSubject}})
+	require.NoError(t, err)
+
+	ack, err := js.Publish(testSubject, []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), ack.Sequence)
+
+	sub, err := js.PullSubscribe(testSubject, testDurable)
+	require.NoError(t, err)
+	msgs, err := sub.Fetch(1, nats.MaxWait(5*time.Second))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.NoError(t, msgs[0].Ack())
+	// the consume span is only finished on the next fetch or on unsubscribe
+	assert.Len(t, mt.FinishedSpans(), 1)
+	require.NoError(t, sub.Unsubscribe())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	produce, consume := spans[0], spans[1]
+	assert.Equal(t, "nats.publish", produce.OperationName())
+	assert.Equal(t, testStream, produce.Tag(ext.MessagingNATSStream))
+	assert.Equal(t, uint64(1), produce.Tag(ext.MessagingNATSSequence))
+
+	assert.Equal(t, "nats.consume", consume.OperationName())
+	assert.Equal(t, produce.SpanID(), consume.ParentID())
+	assert.Equal(t, testStream, consume.Tag(ext.MessagingNATSStream))
+	assert.Equal(t, testDurable, consume.Tag(ext.MessagingNATSConsumer))
+	assert.Equal(t, uint64(1), consume.Tag(ext.MessagingNATSSequence))
+
+	p, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), NewMessageCarrier(msgs[0])))
+	require.True(t, ok)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:"+testSubject, "type:nats")
+	expectedCtx, _ := tracer.SetDataStreamsCheckpoint(ctx, "direction:in", "topic:"+testSubject, "type:nats", "group:"+testDurable)
+	expected, _ := datastreams.PathwayFromContext(expectedCtx)
+	assert.NotEqual(t, expected.GetHash(), 0)
+	assert.Equal(t, expected.GetHash(), p.GetHash())
+}
+
+func TestNamingSchema(t *testing.T) {
+	genSpans := func(t *testing.T, serviceOverride string) []mocktracer.Span {
+		var opts []Option
+		if serviceOverride != "" {
+			opts = append(opts, WithServiceName(serviceOverride))
+		}
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		nc := newTestConn(t, opts...)
+		done := make(chan struct{})
+		sub, err := nc.Subscribe(testSubject, func(*nats.Msg) { close(done) })
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+		require.NoError(t, nc.Publish(testSubject, []byte("hello")))
+		<-done
+
+		require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, 5*time.Second, 10*time.Millisecond)
+		spans := mt.FinishedSpans()
+		// order spans as producer then consumer
+		if spans[0].Tag(ext.SpanKind) == ext.SpanKindConsumer {
+			spans[0], spans[1] = spans[1], spans[0]
+		}
+		return spans
+	}
+	assertOpV0 := func(t *testing.T, spans []mocktracer.Span) {
+		require.Len(t, spans, 2)
+		assert.Equal(t, "nats.publish", spans[0].OperationName())
+		assert.Equal(t, "nats.consume", spans[1].OperationName())
+	}
+	assertOpV1 := func(t *testing.T, spans []mocktracer.Span) {
+		require.Len(t, spans, 2)
+		assert.Equal(t, "nats.send", spans[0].OperationName())
+		assert.Equal(t, "nats.process", spans[1].OperationName())
+	}
+	wantServiceNameV0 := namingschematest.ServiceNameAssertions{
+		WithDefaults:             []string{"nats", "nats"},
+		WithDDService:            []string{"nats", namingschematest.TestDDService},
+		WithDDServiceAndOverride: []string{namingschematest.TestServiceOverride, namingschematest.TestServiceOverride},
+	}
+	t.Run("ServiceName", namingschematest.NewServiceNameTest(genSpans, wantServiceNameV0))
+	t.Run("SpanName", namingschematest.NewSpanNameTest(genSpans, assertOpV0, assertOpV1))
+}
@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+)
+
+// Span tags set on the request span when client tracing is enabled with
+// RTWithClientTrace. Durations are reported in milliseconds.
+const (
+	tagDNSDuration     = "http.dns.duration_ms"
+	tagConnectDuration = "http.connect.duration_ms"
+	tagTLSDuration     = "http.tls.duration_ms"
+	tagConnWait        = "http.conn.wait_ms"
+	tagTTFB            = "http.time_to_first_byte_ms"
+	tagConnReused      = "http.conn.reused"
+)
+
+// clientTimings records the low-level phases of an outgoing request. The
+// httptrace hooks may be called from other goroutines, e.g. when several
+// addresses are dialed concurrently, so all accesses are guarded by mu.
+type clientTimings struct {
+	mu sync.Mutex
+
+	start                    time.Time
+	dnsStart, dnsDone        time.Time
+	connectStart, connectEnd time.Time
+	tlsStart, tlsDone        time.Time
+	gotConn, firstByte       time.Time
+	reused, hasConn          bool
+}
+
+// withClientTrace returns a copy of ctx carrying an httptrace.ClientTrace
+// which records the request timings into the returned clientTimings. Hooks
+// from a ClientTrace already present in ctx are still called.
+func withClientTrace(ctx context.Context) (context.Context, *clientTimings) {
+	ct := &clientTimings{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			ct.record(&ct.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			ct.record(&ct.dnsDone)
+		},
+		ConnectStart: func(_, _ string) {
+			ct.recordFirst(&ct.connectStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				ct.recordFirst(&ct.connectEnd)
+			}
+		},
+		TLSHandshakeStart: func() {
+			ct.record(&ct.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			ct.record(&ct.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.gotConn = time.Now()
+			ct.reused = info.Reused
+			ct.hasConn = true
+		},
+		GotFirstResponseByte: func() {
+			ct.record(&ct.firstByte)
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), ct
+}
+
+func (ct *clientTimings) record(t *time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	*t = time.Now()
+}
+
+// recordFirst is like record but keeps the first value, since the connect
+// hooks are called once per dialed address.
+func (ct *clientTimings) recordFirst(t *time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if t.IsZero() {
+		*t = time.Now()
+	}
+}
+
+// setTags sets the recorded timings as tags on span. Phases which did not
+// happen, such as DNS resolution on a reused connection, are omitted.
+func (ct *clientTimings) setTags(span ddtrace.Span) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	setDuration(span, tagDNSDuration, ct.dnsStart, ct.dnsDone)
+	setDuration(span, tagConnectDuration, ct.connectStart, ct.connectEnd)
+	setDuration(span, tagTLSDuration, ct.tlsStart, ct.tlsDone)
+	setDuration(span, tagConnWait, ct.start, ct.gotConn)
+	setDuration(span, tagTTFB, ct.start, ct.firstByte)
+	if ct.hasConn {
+		span.SetTag(tagConnReused, ct.reused)
+	}
+}
+
+func setDuration(span ddtrace.Span, tag string, start, end time.Time) {
+	if start.IsZero() || end.IsZero() {
+		return
+	}
+	span.SetTag(tag, float64(end.Sub(start))/float64(time.Millisecond))
+}
@@ -55,6 +55,18 @@ func ExampleWrapClient() {
 	http.ListenAndServe(":8080", mux)
 }
 
+// ExampleWrapRouter shows how to trace a third-party router, reporting the
+// route it matched with SetRoute.
+func ExampleWrapRouter() {
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A real router would report the route template it matched,
+		// typically from a middleware registered on it.
+		httptrace.SetRoute(r, "/users/{id}")
+		w.Write([]byte("Hello World!\n"))
+	})
+	http.ListenAndServe(":8080", httptrace.WrapRouter(router))
+}
+
 func traceMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, route := mux.Handler(r)
@@ -7,7 +7,10 @@
 package http // import "github.com/nowfred/dd-trace-go/contrib/net/http"
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
@@ -49,7 +52,8 @@ func (mux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// get the resource associated to this request
-	_, route := mux.Handler(r)
+	_, pattern := mux.Handler(r)
+	route := patternRoute(pattern)
 	resource := mux.cfg.resourceNamer(r)
 	if resource == "" {
 		resource = r.Method + " " + route
@@ -58,13 +62,27 @@ func (mux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	copy(so, mux.cfg.spanOpts)
 	so = append(so, httptrace.HeaderTagsFromRequest(r, mux.cfg.headerTags))
 	TraceAndServe(mux.ServeMux, w, r, &ServeConfig{
-		Service:  mux.cfg.serviceName,
-		Resource: resource,
-		SpanOpts: so,
-		Route:    route,
+		Service:       mux.cfg.serviceName,
+		Resource:      resource,
+		SpanOpts:      so,
+		Route:         route,
+		IsStatusError: mux.cfg.isStatusError,
 	})
 }
 
+// patternRoute returns the route of a ServeMux pattern, i.e. the pattern
+// without the method and host parts Go 1.22 patterns such as
+// "GET example.com/users/{id}" may hold.
+func patternRoute(pattern string) string {
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 && !strings.Contains(pattern[:i], "/") {
+		pattern = strings.TrimLeft(pattern[i+1:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
 // WrapHandler wraps an http.Handler with tracing using the given service and resource.
 // If the WithResourceNamer option is provided as part of opts, it will take precedence over the resource argument.
 func WrapHandler(h http.Handler, service, resource string, opts ...Option) http.Handler {
@@ -89,10 +107,91 @@ func WrapHandler(h http.Handler, service, resource string, opts ...Option) http.
 		copy(so, cfg.spanOpts)
 		so = append(so, httptrace.HeaderTagsFromRequest(req, cfg.headerTags))
 		TraceAndServe(h, w, req, &ServeConfig{
-			Service:    service,
-			Resource:   resc,
-			FinishOpts: cfg.finishOpts,
-			SpanOpts:   so,
+			Service:       service,
+			Resource:      resc,
+			FinishOpts:    cfg.finishOpts,
+			SpanOpts:      so,
+			IsStatusError: cfg.isStatusError,
 		})
 	})
 }
+
+// WrapRouter wraps h, typically a third-party router, with tracing. Since the
+// matched route is only known once the router has dispatched the request, it
+// is reported by calling SetRoute from a handler or middleware registered on
+// the router. The request span is then tagged with it and named after the
+// request method and route, unless the WithResourceNamer option is provided.
+func WrapRouter(h http.Handler, opts ...Option) http.Handler {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	cfg.spanOpts = append(cfg.spanOpts, tracer.Tag(ext.SpanKind, ext.SpanKindServer))
+	cfg.spanOpts = append(cfg.spanOpts, tracer.Tag(ext.Component, componentName))
+	log.Debug("contrib/net/http: Wrapping Router: %#v", cfg)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if cfg.ignoreRequest(req) {
+			h.ServeHTTP(w, req)
+			return
+		}
+		resource := cfg.resourceNamer(req)
+		so := make([]ddtrace.StartSpanOption, len(cfg.spanOpts), len(cfg.spanOpts)+1)
+		copy(so, cfg.spanOpts)
+		so = append(so, httptrace.HeaderTagsFromRequest(req, cfg.headerTags))
+		rh := new(routeHolder)
+		req = req.WithContext(context.WithValue(req.Context(), routeKey{}, rh))
+		TraceAndServe(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				route := rh.get()
+				if route == "" {
+					return
+				}
+				span, ok := tracer.SpanFromContext(r.Context())
+				if !ok {
+					return
+				}
+				span.SetTag(ext.HTTPRoute, route)
+				if resource == "" {
+					span.SetTag(ext.ResourceName, r.Method+" "+route)
+				}
+			}()
+			h.ServeHTTP(w, r)
+		}), w, req, &ServeConfig{
+			Service:       cfg.serviceName,
+			Resource:      resource,
+			FinishOpts:    cfg.finishOpts,
+			SpanOpts:      so,
+			IsStatusError: cfg.isStatusError,
+		})
+	})
+}
+
+// SetRoute reports route as the route matched for r to the enclosing
+// WrapRouter handler. It does nothing when r is not served by WrapRouter.
+func SetRoute(r *http.Request, route string) {
+	if rh, ok := r.Context().Value(routeKey{}).(*routeHolder); ok {
+		rh.set(route)
+	}
+}
+
+type routeKey struct{}
+
+// routeHolder holds the route reported with SetRoute, which may be called
+// from another goroutine than the one serving the request.
+type routeHolder struct {
+	mu    sync.Mutex
+	route string
+}
+
+func (rh *routeHolder) set(route string) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	rh.route = route
+}
+
+func (rh *routeHolder) get() string {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	return rh.route
+}
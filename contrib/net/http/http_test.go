@@ -3,11 +3,16 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2016 Datadog, Inc.
 
+// The module targets go 1.19, under which ServeMux keeps the Go 1.21 behavior.
+// The Go 1.22 patterns are enabled for the tests of the ServeMux routes.
+//go:debug httpmuxgo121=0
+
 package http
 
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -20,6 +25,7 @@ import (
 	"github.com/nowfred/dd-trace-go/internal/normalizer"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithHeaderTags(t *testing.T) {
@@ -232,6 +238,33 @@ func TestWrapHandler200(t *testing.T) {
 	assert.Equal("net/http", s.Tag(ext.Component))
 }
 
+func TestWrapHandlerStatusCheck(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	isStatusError := func(statusCode int) bool { return statusCode == http.StatusTooManyRequests }
+	for _, tc := range []struct {
+		status    int
+		wantError bool
+	}{
+		{status: http.StatusTooManyRequests, wantError: true},
+		{status: http.StatusServiceUnavailable, wantError: false},
+	} {
+		t.Run(strconv.Itoa(tc.status), func(t *testing.T) {
+			mt.Reset()
+			handler := WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}), "my-service", "my-resource", WithStatusCheck(isStatusError))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, strconv.Itoa(tc.status), spans[0].Tag(ext.HTTPCode))
+			assert.Equal(t, tc.wantError, spans[0].Tag(ext.Error) != nil)
+		})
+	}
+}
+
 func TestNoStack(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
@@ -476,6 +509,91 @@ func TestServerNamingSchema(t *testing.T) {
 	namingschematest.NewHTTPServerTest(genSpans, "http.router")(t)
 }
 
+func TestPatternRoute(t *testing.T) {
+	for pattern, want := range map[string]string{
+		"":                           "",
+		"/":                          "/",
+		"/users/":                    "/users/",
+		"/users/{id}":                "/users/{id}",
+		"GET /users/{id}":            "/users/{id}",
+		"POST  /files/{path...}":     "/files/{path...}",
+		"example.com/":               "/",
+		"GET example.com/users/{id}": "/users/{id}",
+		"DELETE\t/users/{id}/{$}":    "/users/{id}/{$}",
+	} {
+		assert.Equal(t, want, patternRoute(pattern), pattern)
+	}
+}
+
+func TestServeMuxPatterns(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	mux := NewServeMux()
+	mux.HandleFunc("GET /users/{id}", handler200)
+	_, p := mux.Handler(httptest.NewRequest("GET", "/users/1", nil))
+	require.Equal(t, "GET /users/{id}", p, "ServeMux must support method and wildcard patterns")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/users/123", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /users/{id}", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "/users/{id}", spans[0].Tag(ext.HTTPRoute))
+}
+
+func TestWrapRouter(t *testing.T) {
+	// userRouter mimics a third-party router matching /users/:id.
+	userRouter := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/users/") {
+			http.NotFound(w, r)
+			return
+		}
+		SetRoute(r, "/users/:id")
+		w.Write([]byte("OK\n"))
+	})
+
+	t.Run("route", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		WrapRouter(userRouter, WithServiceName("my-service")).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/123", nil))
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "my-service", spans[0].Tag(ext.ServiceName))
+		assert.Equal(t, "GET /users/:id", spans[0].Tag(ext.ResourceName))
+		assert.Equal(t, "/users/:id", spans[0].Tag(ext.HTTPRoute))
+		assert.Equal(t, "200", spans[0].Tag(ext.HTTPCode))
+	})
+
+	t.Run("no-route", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		WrapRouter(userRouter).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Nil(t, spans[0].Tag(ext.HTTPRoute))
+		assert.Equal(t, "404", spans[0].Tag(ext.HTTPCode))
+	})
+
+	t.Run("resource-namer", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		namer := func(*http.Request) string { return "custom" }
+		WrapRouter(userRouter, WithResourceNamer(namer)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/123", nil))
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "custom", spans[0].Tag(ext.ResourceName))
+		assert.Equal(t, "/users/:id", spans[0].Tag(ext.HTTPRoute))
+	})
+}
+
 func router(muxOpts ...Option) http.Handler {
 	defaultOpts := []Option{
 		WithServiceName("my-service"),
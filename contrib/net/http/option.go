@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -28,6 +29,7 @@ type config struct {
 	ignoreRequest func(*http.Request) bool
 	resourceNamer func(*http.Request) string
 	headerTags    *internal.LockMap
+	isStatusError func(statusCode int) bool
 }
 
 // MuxOption has been deprecated in favor of Option.
@@ -50,6 +52,7 @@ func defaults(cfg *config) {
 	}
 	cfg.ignoreRequest = func(_ *http.Request) bool { return false }
 	cfg.resourceNamer = func(_ *http.Request) string { return "" }
+	cfg.isStatusError = httptrace.IsServerError
 }
 
 // WithIgnoreRequest holds the function to use for determining if the
@@ -118,6 +121,15 @@ func WithResourceNamer(namer func(req *http.Request) string) Option {
 	}
 }
 
+// WithStatusCheck specifies a function fn which reports whether the passed
+// response status code should be considered an error. It defaults to the
+// status codes configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES, 5xx if unset.
+func WithStatusCheck(fn func(statusCode int) bool) Option {
+	return func(cfg *config) {
+		cfg.isStatusError = fn
+	}
+}
+
 // NoDebugStack prevents stack traces from being attached to spans finishing
 // with an error. This is useful in situations where errors are frequent and
 // performance is critical.
@@ -146,6 +158,8 @@ type roundTripperConfig struct {
 	spanOpts      []ddtrace.StartSpanOption
 	propagation   bool
 	errCheck      func(err error) bool
+	isStatusError func(statusCode int) bool
+	clientTrace   bool
 }
 
 func newRoundTripperConfig() *roundTripperConfig {
@@ -163,6 +177,7 @@ func newRoundTripperConfig() *roundTripperConfig {
 		propagation:   true,
 		spanNamer:     defaultSpanNamer,
 		ignoreRequest: func(_ *http.Request) bool { return false },
+		isStatusError: httptrace.IsClientError,
 	}
 }
 
@@ -264,3 +279,24 @@ func RTWithErrorCheck(fn func(err error) bool) RoundTripperOption {
 		cfg.errCheck = fn
 	}
 }
+
+// RTWithStatusCheck specifies a function fn which reports whether the passed
+// response status code should be considered an error. It defaults to the
+// status codes configured with DD_TRACE_HTTP_CLIENT_ERROR_STATUSES, 5xx if unset.
+func RTWithStatusCheck(fn func(statusCode int) bool) RoundTripperOption {
+	return func(cfg *roundTripperConfig) {
+		cfg.isStatusError = fn
+	}
+}
+
+// RTWithClientTrace enables or disables the recording of low-level request
+// timings using a net/http/httptrace.ClientTrace. When enabled, the time
+// spent in DNS resolution, TCP connect, TLS handshake, waiting for a
+// connection from the pool and until the first response byte is received
+// is set in milliseconds on the request span, along with whether the
+// connection was reused. It is disabled by default.
+func RTWithClientTrace(enabled bool) RoundTripperOption {
+	return func(cfg *roundTripperConfig) {
+		cfg.clientTrace = enabled
+	}
+}
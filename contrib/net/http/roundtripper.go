@@ -15,6 +15,8 @@ import (
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
 )
 
 type roundTripper struct {
@@ -53,7 +55,14 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (res *http.Response, err er
 		opts = append(opts, rt.cfg.spanOpts...)
 	}
 	span, ctx := tracer.StartSpanFromContext(req.Context(), spanName, opts...)
+	var timings *clientTimings
+	if rt.cfg.clientTrace {
+		ctx, timings = withClientTrace(ctx)
+	}
 	defer func() {
+		if timings != nil {
+			timings.setTags(span)
+		}
 		if rt.cfg.after != nil {
 			rt.cfg.after(res, span)
 		}
@@ -75,6 +84,18 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (res *http.Response, err er
 			fmt.Fprintf(os.Stderr, "contrib/net/http.Roundtrip: failed to inject http headers: %v\n", err)
 		}
 	}
+	if appsec.Enabled() {
+		// Check the destination URL against the SSRF rules when the request is
+		// performed by a monitored request handler.
+		if err = sharedsec.MonitorOutboundRequest(ctx, r2.URL.String()); err != nil {
+			span.SetTag("http.errors", err.Error())
+			// RoundTrip must always close the body, including on errors
+			if r2.Body != nil {
+				r2.Body.Close()
+			}
+			return nil, err
+		}
+	}
 	res, err = rt.base.RoundTrip(r2)
 	if err != nil {
 		span.SetTag("http.errors", err.Error())
@@ -83,8 +104,7 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (res *http.Response, err er
 		}
 	} else {
 		span.SetTag(ext.HTTPCode, strconv.Itoa(res.StatusCode))
-		// treat 5XX as errors
-		if res.StatusCode/100 == 5 {
+		if rt.cfg.isStatusError(res.StatusCode) {
 			span.SetTag("http.errors", res.Status)
 			span.SetTag(ext.Error, fmt.Errorf("%d: %s", res.StatusCode, http.StatusText(res.StatusCode)))
 		}
@@ -7,7 +7,9 @@ package http
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -16,11 +18,13 @@ import (
 	"testing"
 	"time"
 
+	pappsec "github.com/nowfred/dd-trace-go/appsec"
 	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
 	"github.com/nowfred/dd-trace-go/internal/globalconfig"
 
 	"github.com/stretchr/testify/assert"
@@ -151,6 +155,42 @@ func TestRoundTripperServerError(t *testing.T) {
 	assert.Equal(t, "net/http", s1.Tag(ext.Component))
 }
 
+func TestRoundTripperStatusCheck(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		w.WriteHeader(code)
+	}))
+	defer s.Close()
+
+	for _, tc := range []struct {
+		name      string
+		opts      []RoundTripperOption
+		status    int
+		wantError bool
+	}{
+		{name: "default-5xx", status: 503, wantError: true},
+		{name: "default-4xx", status: 429, wantError: false},
+		{name: "custom-4xx", opts: []RoundTripperOption{RTWithStatusCheck(func(c int) bool { return c >= 400 })}, status: 429, wantError: true},
+		{name: "custom-5xx", opts: []RoundTripperOption{RTWithStatusCheck(func(c int) bool { return c == 429 })}, status: 503, wantError: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt.Reset()
+			client := &http.Client{Transport: WrapRoundTripper(http.DefaultTransport, tc.opts...)}
+			resp, err := client.Get(s.URL + "/" + strconv.Itoa(tc.status))
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, strconv.Itoa(tc.status), spans[0].Tag(ext.HTTPCode))
+			assert.Equal(t, tc.wantError, spans[0].Tag(ext.Error) != nil)
+		})
+	}
+}
+
 func TestRoundTripperNetworkError(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
@@ -619,3 +659,104 @@ func TestClientNamingSchema(t *testing.T) {
 	t.Run("ServiceName", namingschematest.NewServiceNameTest(genSpans, wantServiceNameV0))
 	t.Run("SpanName", namingschematest.NewSpanNameTest(genSpans, assertOpV0, assertOpV1))
 }
+
+func TestRoundTripperClientTrace(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("Hello World"))
+	}))
+	defer s.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		client := WrapClient(s.Client(), RTWithClientTrace(true))
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(s.URL)
+			require.NoError(t, err)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		first, second := spans[0], spans[1]
+		assert.Equal(t, false, first.Tag(tagConnReused))
+		for _, tag := range []string{tagConnectDuration, tagTLSDuration, tagConnWait, tagTTFB} {
+			assert.IsType(t, float64(0), first.Tag(tag), tag)
+		}
+		assert.Equal(t, true, second.Tag(tagConnReused))
+		assert.Nil(t, second.Tag(tagConnectDuration))
+		assert.Nil(t, second.Tag(tagTLSDuration))
+		assert.IsType(t, float64(0), second.Tag(tagTTFB))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		resp, err := WrapClient(s.Client()).Get(s.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Nil(t, spans[0].Tag(tagConnReused))
+		assert.Nil(t, spans[0].Tag(tagTTFB))
+	})
+}
+
+func TestRoundTripperAppSecSSRF(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/ssrf.json")
+	appsec.Start()
+	defer appsec.Stop()
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		url     string
+		blocked bool
+	}{
+		{name: "block", url: "http://169.254.169.254/latest/meta-data", blocked: true},
+		{name: "no-block", url: "http://127.0.0.1:1/"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			client := WrapClient(&http.Client{Timeout: 100 * time.Millisecond})
+			var outboundErr error
+			mux := NewServeMux()
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, tc.url, nil)
+				var res *http.Response
+				if res, outboundErr = client.Do(req); outboundErr == nil {
+					res.Body.Close()
+				}
+				w.WriteHeader(http.StatusBadGateway)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			res, err := http.Get(srv.URL)
+			require.NoError(t, err)
+			res.Body.Close()
+
+			var blockedErr *pappsec.BlockedOutboundRequestError
+			require.Equal(t, tc.blocked, errors.As(outboundErr, &blockedErr))
+			if !tc.blocked {
+				return
+			}
+			require.Equal(t, tc.url, blockedErr.URL)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 2)
+			assert.Equal(t, ext.SpanKindClient, spans[0].Tag(ext.SpanKind))
+			assert.NotNil(t, spans[0].Tag(ext.Error))
+			event, _ := spans[1].Tag("_dd.appsec.json").(string)
+			assert.True(t, strings.Contains(event, "ssrf-001-001"))
+		})
+	}
+}
@@ -46,6 +46,9 @@ type ServeConfig struct {
 	FinishOpts []ddtrace.FinishOption
 	// SpanOpts specifies any options to be applied to the request starting span.
 	SpanOpts []ddtrace.StartSpanOption
+	// IsStatusError reports whether the response status code should mark the request span as an error.
+	// If nil, the status codes configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES (5xx by default) are errors.
+	IsStatusError func(statusCode int) bool
 }
 
 // TraceAndServe serves the handler h using the given ResponseWriter and Request, applying tracing
@@ -67,7 +70,7 @@ func TraceAndServe(h http.Handler, w http.ResponseWriter, r *http.Request, cfg *
 	span, ctx := httptrace.StartRequestSpan(r, opts...)
 	rw, ddrw := wrapResponseWriter(w)
 	defer func() {
-		httptrace.FinishRequestSpan(span, ddrw.status, cfg.FinishOpts...)
+		httptrace.FinishRequestSpan(span, ddrw.status, cfg.IsStatusError, cfg.FinishOpts...)
 	}()
 
 	if appsec.Enabled() {
@@ -0,0 +1,238 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+// Package amqp091 provides functions to trace the rabbitmq/amqp091-go package (https://github.com/rabbitmq/amqp091-go).
+package amqp091 // import "github.com/nowfred/dd-trace-go/contrib/rabbitmq/amqp091-go.v1"
+
+import (
+	"context"
+	"math"
+
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/datastreams/options"
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const componentName = "rabbitmq/amqp091-go.v1"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("github.com/rabbitmq/amqp091-go")
+}
+
+// Channel wraps an *amqp.Channel so that published, consumed and fetched
+// messages are traced.
+type Channel struct {
+	*amqp.Channel
+	cfg *config
+}
+
+// WrapChannel wraps an *amqp.Channel so that messages are traced.
+func WrapChannel(ch *amqp.Channel, opts ...Option) *Channel {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	log.Debug("contrib/rabbitmq/amqp091-go.v1: Wrapping Channel: %#v", cfg)
+	return &Channel{
+		Channel: ch,
+		cfg:     cfg,
+	}
+}
+
+// Publish calls amqp.Channel.PublishWithContext with a background context and
+// traces the request.
+func (ch *Channel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	return ch.PublishWithContext(context.Background(), exchange, key, mandatory, immediate, msg)
+}
+
+// PublishWithContext calls amqp.Channel.PublishWithContext and traces the
+// request. The trace context is injected into a copy of the message headers so
+// that consumers can pick it up.
+func (ch *Channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	// msg is a copy, but its headers table is shared with the caller
+	headers := make(amqp.Table, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+	resource := "Publish " + exchangeName(exchange)
+	if ch.cfg.routingKeyResource {
+		resource += " -> " + key
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(ch.cfg.producerServiceName),
+		tracer.ResourceName(resource),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemRabbitMQ),
+		tracer.Tag(ext.MessagingRabbitMQExchange, exchange),
+		tracer.Tag(ext.MessagingRabbitMQRoutingKey, key),
+	}
+	if !math.IsNaN(ch.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, ch.cfg.analyticsRate))
+	}
+	carrier := TableCarrier(msg.Headers)
+	span, ctx := tracer.StartSpanFromContext(ctx, ch.cfg.producerSpanName, opts...)
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		log.Debug("contrib/rabbitmq/amqp091-go.v1: Failed to inject span context into message headers: %v", err)
+	}
+	setProduceCheckpoint(ch.cfg.dataStreamsEnabled, exchange, key, &msg)
+	err := ch.Channel.PublishWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// Consume calls amqp.Channel.Consume and traces every delivery. A delivery's
+// span is finished once the delivery is received from the returned channel.
+// The span context is re-injected into the delivery headers so that it can be
+// extracted by the consumer.
+func (ch *Channel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	deliveries, err := ch.Channel.Consume(queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	if err != nil {
+		return deliveries, err
+	}
+	return ch.traceDeliveries(context.Background(), queue, deliveries), nil
+}
+
+// ConsumeWithContext calls amqp.Channel.ConsumeWithContext and traces every
+// delivery in the same way as Consume.
+func (ch *Channel) ConsumeWithContext(ctx context.Context, queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	deliveries, err := ch.Channel.ConsumeWithContext(ctx, queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	if err != nil {
+		return deliveries, err
+	}
+	return ch.traceDeliveries(ctx, queue, deliveries), nil
+}
+
+// traceDeliveries returns a channel forwarding the traced deliveries. It stops
+// forwarding when ctx is done or the channel is closed, so that it does not
+// block forever once the consumer stopped receiving the deliveries.
+func (ch *Channel) traceDeliveries(ctx context.Context, queue string, deliveries <-chan amqp.Delivery) <-chan amqp.Delivery {
+	closed := ch.Channel.NotifyClose(make(chan *amqp.Error, 1))
+	return ch.forwardDeliveries(ctx, queue, deliveries, closed)
+}
+
+func (ch *Channel) forwardDeliveries(ctx context.Context, queue string, deliveries <-chan amqp.Delivery, closed <-chan *amqp.Error) <-chan amqp.Delivery {
+	wrapped := make(chan amqp.Delivery)
+	go func() {
+		defer close(wrapped)
+		for d := range deliveries {
+			span := ch.startConsumerSpan("Consume "+queue, queue, &d)
+			select {
+			case wrapped <- d:
+				span.Finish()
+			case <-ctx.Done():
+				span.Finish()
+				return
+			case <-closed:
+				span.Finish()
+				return
+			}
+		}
+	}()
+	return wrapped
+}
+
+// Get calls amqp.Channel.Get and traces the request. When a message is
+// returned, the span context is re-injected into its headers so that it can be
+// extracted by the caller.
+func (ch *Channel) Get(queue string, autoAck bool) (amqp.Delivery, bool, error) {
+	d, ok, err := ch.Channel.Get(queue, autoAck)
+	if err != nil || !ok {
+		return d, ok, err
+	}
+	span := ch.startConsumerSpan("Get "+queue, queue, &d)
+	span.Finish()
+	return d, ok, err
+}
+
+func (ch *Channel) startConsumerSpan(resource, queue string, d *amqp.Delivery) ddtrace.Span {
+	if d.Headers == nil {
+		d.Headers = amqp.Table{}
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(ch.cfg.consumerServiceName),
+		tracer.ResourceName(resource),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemRabbitMQ),
+		tracer.Tag(ext.MessagingRabbitMQExchange, d.Exchange),
+		tracer.Tag(ext.MessagingRabbitMQRoutingKey, d.RoutingKey),
+		tracer.Tag(ext.MessagingRabbitMQQueue, queue),
+		tracer.Measured(),
+	}
+	if !math.IsNaN(ch.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, ch.cfg.analyticsRate))
+	}
+	carrier := TableCarrier(d.Headers)
+	if spanctx, err := tracer.Extract(carrier); err == nil {
+		opts = append(opts, tracer.ChildOf(spanctx))
+	}
+	span := tracer.StartSpan(ch.cfg.consumerSpanName, opts...)
+	// reinject the span context so consumers can pick it up
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		log.Debug("contrib/rabbitmq/amqp091-go.v1: Failed to inject span context into delivery headers: %v", err)
+	}
+	setConsumeCheckpoint(ch.cfg.dataStreamsEnabled, queue, d)
+	return span
+}
+
+// exchangeName returns a printable name for exchange, the default exchange
+// being named by the empty string.
+func exchangeName(exchange string) string {
+	if exchange == "" {
+		return "<default>"
+	}
+	return exchange
+}
+
+func setProduceCheckpoint(enabled bool, exchange, key string, msg *amqp.Publishing) {
+	if !enabled {
+		return
+	}
+	edges := []string{"direction:out", "exchange:" + exchange, "type:rabbitmq"}
+	if key != "" {
+		edges = append(edges, "has_routing_key:true")
+	}
+	carrier := TableCarrier(msg.Headers)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg.Headers, msg.Body)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func setConsumeCheckpoint(enabled bool, queue string, d *amqp.Delivery) {
+	if !enabled {
+		return
+	}
+	edges := []string{"direction:in", "topic:" + queue, "type:rabbitmq"}
+	carrier := TableCarrier(d.Headers)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(d.Headers, d.Body)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func getMsgSize(headers amqp.Table, body []byte) (size int64) {
+	for k, v := range headers {
+		size += int64(len(k))
+		if s, ok := v.(string); ok {
+			size += int64(len(s))
+		}
+	}
+	return size + int64(len(body))
+}
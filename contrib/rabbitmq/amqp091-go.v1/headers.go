@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package amqp091
+
+import (
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// A TableCarrier injects and extracts traces from an amqp.Table, such as the
+// headers of an amqp.Publishing or an amqp.Delivery.
+type TableCarrier amqp.Table
+
+var _ interface {
+	tracer.TextMapReader
+	tracer.TextMapWriter
+} = (*TableCarrier)(nil)
+
+// ForeachKey iterates over every header holding a string value.
+func (c TableCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if err := handler(k, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets a header.
+func (c TableCarrier) Set(key, val string) {
+	c[key] = val
+}
+
+// ExtractSpanContext retrieves the SpanContext from an amqp.Delivery.
+func ExtractSpanContext(d amqp.Delivery) (ddtrace.SpanContext, error) {
+	return tracer.Extract(TableCarrier(d.Headers))
+}
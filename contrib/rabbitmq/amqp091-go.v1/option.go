@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package amqp091
+
+import (
+	"math"
+
+	"github.com/nowfred/dd-trace-go/internal"
+	"github.com/nowfred/dd-trace-go/internal/namingschema"
+)
+
+const defaultServiceName = "rabbitmq"
+
+type config struct {
+	consumerServiceName string
+	producerServiceName string
+	consumerSpanName    string
+	producerSpanName    string
+	analyticsRate       float64
+	dataStreamsEnabled  bool
+	routingKeyResource  bool
+}
+
+func defaults(cfg *config) {
+	cfg.consumerServiceName = namingschema.ServiceName(defaultServiceName)
+	cfg.producerServiceName = namingschema.ServiceNameOverrideV0(defaultServiceName, defaultServiceName)
+
+	cfg.consumerSpanName = namingschema.OpName(namingschema.RabbitMQInbound)
+	cfg.producerSpanName = namingschema.OpName(namingschema.RabbitMQOutbound)
+
+	cfg.dataStreamsEnabled = internal.BoolEnv("DD_DATA_STREAMS_ENABLED", false)
+	cfg.routingKeyResource = true
+
+	// cfg.analyticsRate = globalconfig.AnalyticsRate()
+	if internal.BoolEnv("DD_TRACE_RABBITMQ_ANALYTICS_ENABLED", false) {
+		cfg.analyticsRate = 1.0
+	} else {
+		cfg.analyticsRate = math.NaN()
+	}
+}
+
+// An Option is used to customize the config for the traced channels.
+type Option func(cfg *config)
+
+// WithServiceName sets the given service name for the publish and consume
+// spans of the channel.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.consumerServiceName = name
+		cfg.producerServiceName = name
+	}
+}
+
+// WithRoutingKeyResource reports whether the routing key is part of the
+// resource name of the publish spans, as in "Publish exchange -> key". It is
+// enabled by default. Disabling it is useful with topic and headers exchanges,
+// whose routing keys often hold identifiers which would make the number of
+// resources grow unbounded. The routing key is always set in the
+// "messaging.rabbitmq.routing_key" tag.
+func WithRoutingKeyResource(on bool) Option {
+	return func(cfg *config) {
+		cfg.routingKeyResource = on
+	}
+}
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() Option {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
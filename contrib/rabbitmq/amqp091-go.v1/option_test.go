@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023 Datadog, Inc.
+
+package amqp091
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/globalconfig"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConfig(opts ...Option) *config {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+func TestServiceName(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg := newTestConfig()
+		assert.Equal(t, "rabbitmq", cfg.consumerServiceName)
+		assert.Equal(t, "rabbitmq", cfg.producerServiceName)
+	})
+	t.Run("global", func(t *testing.T) {
+		prev := globalconfig.ServiceName()
+		defer globalconfig.SetServiceName(prev)
+		globalconfig.SetServiceName("my-service")
+
+		// consumers report to the service of the application, while
+		// producers report to the service of the broker
+		cfg := newTestConfig()
+		assert.Equal(t, "my-service", cfg.consumerServiceName)
+		assert.Equal(t, "rabbitmq", cfg.producerServiceName)
+	})
+	t.Run("override", func(t *testing.T) {
+		cfg := newTestConfig(WithServiceName("my-rabbitmq"))
+		assert.Equal(t, "my-rabbitmq", cfg.consumerServiceName)
+		assert.Equal(t, "my-rabbitmq", cfg.producerServiceName)
+	})
+}
+
+func TestRoutingKeyResource(t *testing.T) {
+	assert.True(t, newTestConfig().routingKeyResource)
+	assert.False(t, newTestConfig(WithRoutingKeyResource(false)).routingKeyResource)
+}
+
+func TestDataStreamsActivation(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert.False(t, newTestConfig().dataStreamsEnabled)
+	})
+	t.Run("withOption", func(t *testing.T) {
+		assert.True(t, newTestConfig(WithDataStreams()).dataStreamsEnabled)
+	})
+	t.Run("withEnv", func(t *testing.T) {
+		t.Setenv("DD_DATA_STREAMS_ENABLED", "true")
+		assert.True(t, newTestConfig().dataStreamsEnabled)
+	})
+}
+
+func TestAnalyticsSettings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		assert.True(t, math.IsNaN(newTestConfig().analyticsRate))
+	})
+	t.Run("withEnv", func(t *testing.T) {
+		t.Setenv("DD_TRACE_RABBITMQ_ANALYTICS_ENABLED", "true")
+		assert.Equal(t, 1.0, newTestConfig().analyticsRate)
+	})
+	t.Run("override", func(t *testing.T) {
+		t.Setenv("DD_TRACE_RABBITMQ_ANALYTICS_ENABLED", "true")
+		assert.Equal(t, 0.2, newTestConfig(WithAnalyticsRate(0.2)).analyticsRate)
+		assert.True(t, math.IsNaN(newTestConfig(WithAnalytics(false)).analyticsRate))
+	})
+}
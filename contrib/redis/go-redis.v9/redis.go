@@ -13,6 +13,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
@@ -62,6 +63,17 @@ func WrapClient(client redis.UniversalClient, opts ...ClientOption) {
 	}
 
 	client.AddHook(&datadogHook{params: hookParams})
+	// The cluster and ring clients dispatch the commands to a client per node,
+	// whose hooks report the address of the node to the span of the command.
+	if c, ok := client.(nodesClient); ok {
+		c.OnNewNode(func(node *redis.Client) {
+			node.AddHook(newNodeHook(node.Options().Addr))
+		})
+	}
+}
+
+type nodesClient interface {
+	OnNewNode(fn func(rdb *redis.Client))
 }
 
 type clientOptions interface {
@@ -79,6 +91,7 @@ func additionalTagOptions(client redis.UniversalClient) []ddtrace.StartSpanOptio
 		if opt.Addr == "FailoverClient" {
 			additionalTags = []ddtrace.StartSpanOption{
 				tracer.Tag("out.db", strconv.Itoa(opt.DB)),
+				tracer.Tag(ext.RedisDatabaseIndex, opt.DB),
 			}
 		} else {
 			host, port, err := net.SplitHostPort(opt.Addr)
@@ -90,6 +103,7 @@ func additionalTagOptions(client redis.UniversalClient) []ddtrace.StartSpanOptio
 				tracer.Tag(ext.TargetHost, host),
 				tracer.Tag(ext.TargetPort, port),
 				tracer.Tag("out.db", strconv.Itoa(opt.DB)),
+				tracer.Tag(ext.RedisDatabaseIndex, opt.DB),
 			}
 		}
 	} else if clientOptions, ok := client.(clusterOptions); ok {
@@ -151,9 +165,11 @@ func (ddh *datadogHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 			startOpts = append(startOpts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 		}
 		span, ctx := tracer.StartSpanFromContext(ctx, p.config.spanName, startOpts...)
+		ctx, nodes := withNodes(ctx)
 
 		err := hook(ctx, cmd)
 
+		nodes.setTags(span)
 		var finishOpts []ddtrace.FinishOption
 		if err != nil && err != redis.Nil && ddh.config.errCheck(err) {
 			finishOpts = append(finishOpts, tracer.WithError(err))
@@ -171,6 +187,8 @@ func (ddh *datadogHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redi
 			tracer.ServiceName(p.config.serviceName),
 			tracer.ResourceName("redis.pipeline"),
 			tracer.Tag("redis.pipeline_length", strconv.Itoa(len(cmds))),
+			tracer.Tag(tagPipelineCommands, len(cmds)),
+			tracer.Tag(tagPipelineArgs, pipelineArgs(cmds)),
 		)
 		if !p.config.skipRaw {
 			raw := commandsToString(cmds)
@@ -181,9 +199,11 @@ func (ddh *datadogHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redi
 			startOpts = append(startOpts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 		}
 		span, ctx := tracer.StartSpanFromContext(ctx, p.config.spanName, startOpts...)
+		ctx, nodes := withNodes(ctx)
 
 		err := hook(ctx, cmds)
 
+		nodes.setTags(span)
 		var finishOpts []ddtrace.FinishOption
 		if err != nil && err != redis.Nil && ddh.config.errCheck(err) {
 			finishOpts = append(finishOpts, tracer.WithError(err))
@@ -204,3 +224,95 @@ func commandsToString(cmds []redis.Cmder) string {
 	}
 	return b.String()
 }
+
+const (
+	// tagPipelineCommands is the metric of the number of commands of a pipeline.
+	tagPipelineCommands = "redis.pipeline.commands"
+	// tagPipelineArgs is the metric of the total number of arguments of the commands of a pipeline.
+	tagPipelineArgs = "redis.pipeline.args"
+	// tagNodes is the tag of the comma-separated addresses of the cluster or ring nodes a command or pipeline was sent
+	// to. A cluster command may be sent to several nodes when redirected, and a cluster pipeline is split by node.
+	tagNodes = "redis.nodes"
+)
+
+// pipelineArgs returns the total number of arguments of the given commands.
+func pipelineArgs(cmds []redis.Cmder) int {
+	var n int
+	for _, cmd := range cmds {
+		n += len(cmd.Args())
+	}
+	return n
+}
+
+type nodesKey struct{}
+
+// nodes holds the addresses of the nodes a command or pipeline was sent to, reported by the node hooks.
+type nodes struct {
+	mu    sync.Mutex
+	addrs []string
+}
+
+// withNodes returns a context holding a new nodes to be filled by the node hooks.
+func withNodes(ctx context.Context) (context.Context, *nodes) {
+	n := new(nodes)
+	return context.WithValue(ctx, nodesKey{}, n), n
+}
+
+func (n *nodes) add(addr string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, a := range n.addrs {
+		if a == addr {
+			return
+		}
+	}
+	n.addrs = append(n.addrs, addr)
+}
+
+// setTags sets the node addresses on the given span. The target host and port are set when a single node was used.
+func (n *nodes) setTags(span ddtrace.Span) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.addrs) == 0 {
+		return
+	}
+	span.SetTag(tagNodes, strings.Join(n.addrs, ","))
+	if len(n.addrs) > 1 {
+		return
+	}
+	if host, port, err := net.SplitHostPort(n.addrs[0]); err == nil {
+		span.SetTag(ext.TargetHost, host)
+		span.SetTag(ext.TargetPort, port)
+	}
+}
+
+// nodeHook is the hook of the client of a cluster or ring node, reporting its address to the span of the command.
+type nodeHook struct {
+	addr string
+}
+
+func newNodeHook(addr string) *nodeHook {
+	return &nodeHook{addr: addr}
+}
+
+func (h *nodeHook) DialHook(hook redis.DialHook) redis.DialHook {
+	return hook
+}
+
+func (h *nodeHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if n, ok := ctx.Value(nodesKey{}).(*nodes); ok {
+			n.add(h.addr)
+		}
+		return hook(ctx, cmd)
+	}
+}
+
+func (h *nodeHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if n, ok := ctx.Value(nodesKey{}).(*nodes); ok {
+			n.add(h.addr)
+		}
+		return hook(ctx, cmds)
+	}
+}
@@ -235,13 +235,14 @@ func TestAdditionalTagsFromClient(t *testing.T) {
 		simpleClient := redis.NewUniversalClient(simpleClientOpts)
 		config := &ddtrace.StartSpanConfig{}
 		expectedTags := map[string]interface{}{
-			"component": "redis/go-redis.v9",
-			"db.system": "redis",
-			"out.db":    "0",
-			"out.host":  "127.0.0.1",
-			"out.port":  "6379",
-			"span.kind": "client",
-			"span.type": "redis",
+			"component":               "redis/go-redis.v9",
+			"db.system":               "redis",
+			"out.db":                  "0",
+			"db.redis.database_index": 0,
+			"out.host":                "127.0.0.1",
+			"out.port":                "6379",
+			"span.kind":               "client",
+			"span.type":               "redis",
 		}
 
 		additionalTagOptions := additionalTagOptions(simpleClient)
@@ -261,11 +262,12 @@ func TestAdditionalTagsFromClient(t *testing.T) {
 		failoverClient := redis.NewUniversalClient(failoverClientOpts)
 		config := &ddtrace.StartSpanConfig{}
 		expectedTags := map[string]interface{}{
-			"out.db":    "0",
-			"component": "redis/go-redis.v9",
-			"db.system": "redis",
-			"span.kind": "client",
-			"span.type": "redis",
+			"out.db":                  "0",
+			"db.redis.database_index": 0,
+			"component":               "redis/go-redis.v9",
+			"db.system":               "redis",
+			"span.kind":               "client",
+			"span.type":               "redis",
 		}
 
 		additionalTagOptions := additionalTagOptions(failoverClient)
@@ -300,6 +302,43 @@ func TestAdditionalTagsFromClient(t *testing.T) {
 	})
 }
 
+func TestNodeHook(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	process := func(ctx context.Context, addrs ...string) mocktracer.Span {
+		span, ctx := tracer.StartSpanFromContext(ctx, "redis.command")
+		ctx, n := withNodes(ctx)
+		for _, addr := range addrs {
+			hook := newNodeHook(addr).ProcessHook(func(context.Context, redis.Cmder) error { return nil })
+			hook(ctx, redis.NewStatusCmd(ctx, "ping"))
+		}
+		n.setTags(span)
+		span.Finish()
+		spans := mt.FinishedSpans()
+		mt.Reset()
+		return spans[0]
+	}
+
+	t.Run("single-node", func(t *testing.T) {
+		span := process(context.Background(), "10.0.0.1:7000", "10.0.0.1:7000")
+		assert.Equal(t, "10.0.0.1:7000", span.Tag("redis.nodes"))
+		assert.Equal(t, "10.0.0.1", span.Tag(ext.TargetHost))
+		assert.Equal(t, "7000", span.Tag(ext.TargetPort))
+	})
+
+	t.Run("redirected", func(t *testing.T) {
+		span := process(context.Background(), "10.0.0.1:7000", "10.0.0.2:7001")
+		assert.Equal(t, "10.0.0.1:7000,10.0.0.2:7001", span.Tag("redis.nodes"))
+		assert.Nil(t, span.Tag(ext.TargetHost))
+	})
+
+	t.Run("no-node", func(t *testing.T) {
+		span := process(context.Background())
+		assert.Nil(t, span.Tag("redis.nodes"))
+	})
+}
+
 func TestPipeline(t *testing.T) {
 	ctx := context.Background()
 	opts := &redis.Options{Addr: "127.0.0.1:6379"}
@@ -331,6 +370,8 @@ func TestPipeline(t *testing.T) {
 	assert.Equal("127.0.0.1", span.Tag(ext.TargetHost))
 	assert.Equal("6379", span.Tag(ext.TargetPort))
 	assert.Equal("1", span.Tag("redis.pipeline_length"))
+	assert.Equal(1, span.Tag("redis.pipeline.commands"))
+	assert.Equal(3, span.Tag("redis.pipeline.args"))
 	assert.Equal("redis/go-redis.v9", span.Tag(ext.Component))
 	assert.Equal(ext.SpanKindClient, span.Tag(ext.SpanKind))
 	assert.Equal("redis", span.Tag(ext.DBSystem))
@@ -351,6 +392,8 @@ func TestPipeline(t *testing.T) {
 	assert.Equal("my-redis", span.Tag(ext.ServiceName))
 	assert.Equal("redis.pipeline", span.Tag(ext.ResourceName))
 	assert.Equal("2", span.Tag("redis.pipeline_length"))
+	assert.Equal(2, span.Tag("redis.pipeline.commands"))
+	assert.Equal(6, span.Tag("redis.pipeline.args"))
 	assert.Equal("redis/go-redis.v9", span.Tag(ext.Component))
 	assert.Equal(ext.SpanKindClient, span.Tag(ext.SpanKind))
 	assert.Equal("redis", span.Tag(ext.DBSystem))
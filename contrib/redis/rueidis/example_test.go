@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package rueidis_test
+
+import (
+	"context"
+	"log"
+
+	rueidistrace "github.com/nowfred/dd-trace-go/contrib/redis/rueidis"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/redis/rueidis"
+)
+
+// To start tracing Redis, simply create a new client using this package and continue
+// using it as you normally would.
+func Example() {
+	ctx := context.Background()
+	c, err := rueidistrace.NewClient(rueidis.ClientOption{InitAddress: []string{"127.0.0.1:6379"}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	// any command emits a span
+	c.Do(ctx, c.B().Set().Key("test_key").Value("test_value").Build())
+
+	// optionally, create a new root span
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "parent.request",
+		tracer.SpanType(ext.SpanTypeRedis),
+		tracer.ServiceName("web"),
+		tracer.ResourceName("/home"),
+	)
+
+	// further commands inherit from the parent in the context, and pipelines
+	// emit a single span.
+	c.DoMulti(ctx,
+		c.B().Set().Key("food").Value("cheese").Build(),
+		c.B().Get().Key("food").Build(),
+	)
+	root.Finish()
+}
@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package rueidis provides functions to trace the redis/rueidis package (https://github.com/redis/rueidis).
+package rueidis // import "github.com/nowfred/dd-trace-go/contrib/redis/rueidis"
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nowfred/dd-trace-go/ddtrace"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/log"
+	"github.com/nowfred/dd-trace-go/internal/telemetry"
+
+	"github.com/redis/rueidis"
+	"github.com/redis/rueidis/rueidishook"
+)
+
+const componentName = "redis/rueidis"
+
+func init() {
+	telemetry.LoadIntegration(componentName)
+	tracer.MarkIntegrationImported("github.com/redis/rueidis")
+}
+
+const (
+	// tagPipelineCommands is the metric of the number of commands of a pipeline.
+	tagPipelineCommands = "redis.pipeline.commands"
+	// tagPipelineArgs is the metric of the total number of arguments of the commands of a pipeline.
+	tagPipelineArgs = "redis.pipeline.args"
+	// tagSlot is the tag of the cluster hash slot of the keys of a command, identifying the shard it is sent to.
+	tagSlot = "redis.cluster.slot"
+	// tagCacheHit reports whether a command was served by the client side cache.
+	tagCacheHit = "redis.client_cache_hit"
+)
+
+// NewClient returns a new rueidis.Client for the given options, traced with the
+// default tracer under the service name "redis.client".
+func NewClient(option rueidis.ClientOption, opts ...ClientOption) (rueidis.Client, error) {
+	client, err := rueidis.NewClient(option)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(clientConfig)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	log.Debug("contrib/redis/rueidis: Configuring Client: %#v", cfg)
+	return rueidishook.WithHook(client, &datadogHook{
+		config:         cfg,
+		additionalTags: additionalTagOptions(option),
+	}), nil
+}
+
+// datadogHook is the hook tracing the commands of a client, including the ones
+// of its dedicated clients.
+type datadogHook struct {
+	config         *clientConfig
+	additionalTags []ddtrace.StartSpanOption
+}
+
+var _ rueidishook.Hook = (*datadogHook)(nil)
+
+func additionalTagOptions(option rueidis.ClientOption) []ddtrace.StartSpanOption {
+	var additionalTags []ddtrace.StartSpanOption
+	if len(option.InitAddress) == 1 && option.Sentinel.MasterSet == "" {
+		host, port, err := net.SplitHostPort(option.InitAddress[0])
+		if err != nil {
+			host = option.InitAddress[0]
+			port = "6379"
+		}
+		additionalTags = append(additionalTags,
+			tracer.Tag(ext.TargetHost, host),
+			tracer.Tag(ext.TargetPort, port),
+		)
+	} else if len(option.InitAddress) > 1 {
+		additionalTags = append(additionalTags,
+			tracer.Tag("addrs", strings.Join(option.InitAddress, ", ")),
+		)
+	}
+	return append(additionalTags,
+		tracer.Tag("out.db", strconv.Itoa(option.SelectDB)),
+		tracer.Tag(ext.RedisDatabaseIndex, option.SelectDB),
+		tracer.SpanType(ext.SpanTypeRedis),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.DBSystem, ext.DBSystemRedis),
+	)
+}
+
+// startSpan starts the span of a command, given as its keywords and arguments.
+// The command must be read before it is sent, as it is recycled afterwards.
+func (h *datadogHook) startSpan(ctx context.Context, cmd []string, slot uint16) (ddtrace.Span, context.Context) {
+	var resource string
+	if len(cmd) > 0 {
+		resource = cmd[0]
+	}
+	opts := make([]ddtrace.StartSpanOption, 0, 4+1+len(h.additionalTags)+1) // 4 options below + redis.raw_command + h.additionalTags + analyticsRate
+	opts = append(opts,
+		tracer.ServiceName(h.config.serviceName),
+		tracer.ResourceName(resource),
+		tracer.Tag("redis.args_length", strconv.Itoa(len(cmd)-1)),
+	)
+	if slot < 16384 {
+		opts = append(opts, tracer.Tag(tagSlot, int(slot)))
+	}
+	if !h.config.skipRaw {
+		opts = append(opts, tracer.Tag("redis.raw_command", strings.Join(cmd, " ")))
+	}
+	return h.start(ctx, opts)
+}
+
+// startPipelineSpan starts the span of a pipeline of commands.
+func (h *datadogHook) startPipelineSpan(ctx context.Context, cmds [][]string) (ddtrace.Span, context.Context) {
+	var args int
+	for _, cmd := range cmds {
+		args += len(cmd)
+	}
+	opts := make([]ddtrace.StartSpanOption, 0, 5+1+len(h.additionalTags)+1) // 5 options below + redis.raw_command + h.additionalTags + analyticsRate
+	opts = append(opts,
+		tracer.ServiceName(h.config.serviceName),
+		tracer.ResourceName("redis.pipeline"),
+		tracer.Tag("redis.pipeline_length", strconv.Itoa(len(cmds))),
+		tracer.Tag(tagPipelineCommands, len(cmds)),
+		tracer.Tag(tagPipelineArgs, args),
+	)
+	if !h.config.skipRaw {
+		raw := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			raw[i] = strings.Join(cmd, " ")
+		}
+		opts = append(opts, tracer.Tag("redis.raw_command", strings.Join(raw, "\n")))
+	}
+	return h.start(ctx, opts)
+}
+
+func (h *datadogHook) start(ctx context.Context, opts []ddtrace.StartSpanOption) (ddtrace.Span, context.Context) {
+	opts = append(opts, h.additionalTags...)
+	if !math.IsNaN(h.config.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, h.config.analyticsRate))
+	}
+	return tracer.StartSpanFromContext(ctx, h.config.spanName, opts...)
+}
+
+// finishSpan finishes the span of a command with the given error. Redis nil
+// replies are not errors.
+func (h *datadogHook) finishSpan(span ddtrace.Span, err error) {
+	var opts []ddtrace.FinishOption
+	if err != nil && !rueidis.IsRedisNil(err) && h.config.errCheck(err) {
+		opts = append(opts, tracer.WithError(err))
+	}
+	span.Finish(opts...)
+}
+
+// firstError returns the first error of the given results which is not a
+// redis nil reply.
+func firstError(resps []rueidis.RedisResult) error {
+	for _, resp := range resps {
+		if err := resp.Error(); err != nil && !rueidis.IsRedisNil(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do implements rueidishook.Hook.
+func (h *datadogHook) Do(client rueidis.Client, ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	span, ctx := h.startSpan(ctx, cmd.Commands(), cmd.Slot())
+	resp := client.Do(ctx, cmd)
+	h.finishSpan(span, resp.Error())
+	return resp
+}
+
+// DoMulti implements rueidishook.Hook.
+func (h *datadogHook) DoMulti(client rueidis.Client, ctx context.Context, multi ...rueidis.Completed) []rueidis.RedisResult {
+	cmds := make([][]string, len(multi))
+	for i := range multi {
+		cmds[i] = multi[i].Commands()
+	}
+	span, ctx := h.startPipelineSpan(ctx, cmds)
+	resps := client.DoMulti(ctx, multi...)
+	h.finishSpan(span, firstError(resps))
+	return resps
+}
+
+// DoCache implements rueidishook.Hook.
+func (h *datadogHook) DoCache(client rueidis.Client, ctx context.Context, cmd rueidis.Cacheable, ttl time.Duration) rueidis.RedisResult {
+	span, ctx := h.startSpan(ctx, cmd.Commands(), cmd.Slot())
+	resp := client.DoCache(ctx, cmd, ttl)
+	if resp.NonRedisError() == nil {
+		span.SetTag(tagCacheHit, resp.IsCacheHit())
+	}
+	h.finishSpan(span, resp.Error())
+	return resp
+}
+
+// DoMultiCache implements rueidishook.Hook.
+func (h *datadogHook) DoMultiCache(client rueidis.Client, ctx context.Context, multi ...rueidis.CacheableTTL) []rueidis.RedisResult {
+	cmds := make([][]string, len(multi))
+	for i := range multi {
+		cmds[i] = multi[i].Cmd.Commands()
+	}
+	span, ctx := h.startPipelineSpan(ctx, cmds)
+	resps := client.DoMultiCache(ctx, multi...)
+	h.finishSpan(span, firstError(resps))
+	return resps
+}
+
+// Receive implements rueidishook.Hook. The span lasts as long as the subscription.
+func (h *datadogHook) Receive(client rueidis.Client, ctx context.Context, subscribe rueidis.Completed, fn func(msg rueidis.PubSubMessage)) error {
+	span, ctx := h.startSpan(ctx, subscribe.Commands(), subscribe.Slot())
+	err := client.Receive(ctx, subscribe, fn)
+	h.finishSpan(span, err)
+	return err
+}
@@ -0,0 +1,242 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package rueidis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addr = "127.0.0.1:6379"
+
+func TestMain(m *testing.M) {
+	_, ok := os.LookupEnv("INTEGRATION")
+	if !ok {
+		fmt.Println("--- SKIP: to enable integration test, set the INTEGRATION environment variable")
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newClient(t *testing.T, opts ...ClientOption) rueidis.Client {
+	client, err := NewClient(rueidis.ClientOption{InitAddress: []string{addr}, SelectDB: 1}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient(t *testing.T) {
+	client := newClient(t, WithServiceName("my-redis"))
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	err := client.Do(context.Background(), client.B().Set().Key("test_key").Value("test_value").Build()).Error()
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "redis.command", span.OperationName())
+	assert.Equal(t, "SET", span.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeRedis, span.Tag(ext.SpanType))
+	assert.Equal(t, "my-redis", span.Tag(ext.ServiceName))
+	assert.Equal(t, "127.0.0.1", span.Tag(ext.TargetHost))
+	assert.Equal(t, "6379", span.Tag(ext.TargetPort))
+	assert.Equal(t, "1", span.Tag("out.db"))
+	assert.Equal(t, 1, span.Tag(ext.RedisDatabaseIndex))
+	assert.Equal(t, "SET test_key test_value", span.Tag("redis.raw_command"))
+	assert.Equal(t, "2", span.Tag("redis.args_length"))
+	assert.NotNil(t, span.Tag(tagSlot))
+	assert.Equal(t, componentName, span.Tag(ext.Component))
+	assert.Equal(t, ext.SpanKindClient, span.Tag(ext.SpanKind))
+	assert.Equal(t, ext.DBSystemRedis, span.Tag(ext.DBSystem))
+	assert.Nil(t, span.Tag(ext.Error))
+}
+
+func TestSkipRaw(t *testing.T) {
+	client := newClient(t, WithSkipRawCommand(true))
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx := context.Background()
+	client.Do(ctx, client.B().Set().Key("test_key").Value("test_value").Build())
+	client.DoMulti(ctx, client.B().Get().Key("test_key").Build())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		_, ok := span.Tags()["redis.raw_command"]
+		assert.False(t, ok)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	client := newClient(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	resps := client.DoMulti(context.Background(),
+		client.B().Set().Key("pipeline_key").Value("value").Build(),
+		client.B().Get().Key("pipeline_key").Build(),
+	)
+	require.Len(t, resps, 2)
+	for _, resp := range resps {
+		require.NoError(t, resp.Error())
+	}
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "redis.command", span.OperationName())
+	assert.Equal(t, "redis.pipeline", span.Tag(ext.ResourceName))
+	assert.Equal(t, "2", span.Tag("redis.pipeline_length"))
+	assert.Equal(t, 2, span.Tag(tagPipelineCommands))
+	assert.Equal(t, 5, span.Tag(tagPipelineArgs))
+	assert.Equal(t, "SET pipeline_key value\nGET pipeline_key", span.Tag("redis.raw_command"))
+}
+
+func TestChildSpan(t *testing.T) {
+	client := newClient(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	client.Do(ctx, client.B().Get().Key("test_key").Build())
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, root.Context().SpanID(), spans[0].ParentID())
+}
+
+func TestError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		client := newClient(t)
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		ctx := context.Background()
+		client.Do(ctx, client.B().Del().Key("missing_key").Build())
+		err := client.Do(ctx, client.B().Get().Key("missing_key").Build()).Error()
+		require.True(t, rueidis.IsRedisNil(err))
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		assert.Nil(t, spans[1].Tag(ext.Error))
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		client := newClient(t)
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		ctx := context.Background()
+		client.Do(ctx, client.B().Set().Key("error_key").Value("value").Build())
+		err := client.Do(ctx, client.B().Incr().Key("error_key").Build()).Error()
+		require.Error(t, err)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, err, spans[1].Tag(ext.Error))
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		client := newClient(t)
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		client.DoMulti(context.Background(),
+			client.B().Set().Key("error_key").Value("value").Build(),
+			client.B().Incr().Key("error_key").Build(),
+		)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.NotNil(t, spans[0].Tag(ext.Error))
+	})
+
+	t.Run("check", func(t *testing.T) {
+		client := newClient(t, WithErrorCheck(func(err error) bool {
+			var redisErr *rueidis.RedisError
+			return !errors.As(err, &redisErr)
+		}))
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		ctx := context.Background()
+		client.Do(ctx, client.B().Set().Key("error_key").Value("value").Build())
+		err := client.Do(ctx, client.B().Incr().Key("error_key").Build()).Error()
+		require.Error(t, err)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		assert.Nil(t, spans[1].Tag(ext.Error))
+	})
+}
+
+func TestAnalyticsSettings(t *testing.T) {
+	assertRate := func(t *testing.T, mt mocktracer.Tracer, rate interface{}, opts ...ClientOption) {
+		client := newClient(t, opts...)
+		client.Do(context.Background(), client.B().Get().Key("test_key").Build())
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, rate, spans[0].Tag(ext.EventSampleRate))
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		assertRate(t, mt, nil)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		assertRate(t, mt, 1.0, WithAnalytics(true))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		t.Setenv("DD_TRACE_REDIS_ANALYTICS_ENABLED", "true")
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		assertRate(t, mt, 0.23, WithAnalyticsRate(0.23))
+	})
+}
+
+func TestNamingSchema(t *testing.T) {
+	genSpans := namingschematest.GenSpansFn(func(t *testing.T, serviceOverride string) []mocktracer.Span {
+		var opts []ClientOption
+		if serviceOverride != "" {
+			opts = append(opts, WithServiceName(serviceOverride))
+		}
+		client := newClient(t, opts...)
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		err := client.Do(context.Background(), client.B().Set().Key("test_key").Value("test_value").Build()).Error()
+		require.NoError(t, err)
+
+		return mt.FinishedSpans()
+	})
+
+	namingschematest.NewRedisTest(genSpans, "redis.client")(t)
+}
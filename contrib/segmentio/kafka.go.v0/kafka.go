@@ -9,7 +9,10 @@ import (
 	"context"
 	"math"
 	"strings"
+	"sync"
 
+	"github.com/nowfred/dd-trace-go/datastreams"
+	"github.com/nowfred/dd-trace-go/datastreams/options"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
@@ -46,6 +49,7 @@ func WrapReader(c *kafka.Reader, opts ...Option) *Reader {
 	if c.Config().Brokers != nil {
 		wrapped.bootstrapServers = strings.Join(c.Config().Brokers, ",")
 	}
+	wrapped.groupID = c.Config().GroupID
 	log.Debug("contrib/segmentio/kafka-go.v0/kafka: Wrapping Reader: %#v", wrapped.cfg)
 	return wrapped
 }
@@ -59,8 +63,9 @@ type kafkaConfig struct {
 type Reader struct {
 	*kafka.Reader
 	kafkaConfig
-	cfg  *config
-	prev ddtrace.Span
+	cfg     *config
+	groupID string
+	prev    ddtrace.Span
 }
 
 func (r *Reader) startSpan(ctx context.Context, msg *kafka.Message) ddtrace.Span {
@@ -90,6 +95,7 @@ func (r *Reader) startSpan(ctx context.Context, msg *kafka.Message) ddtrace.Span
 	if err := tracer.Inject(span.Context(), carrier); err != nil {
 		log.Debug("contrib/segmentio/kafka.go.v0: Failed to inject span context into carrier in reader, %v", err)
 	}
+	setConsumeCheckpoint(r.cfg.dataStreamsEnabled, r.groupID, msg)
 	return span
 }
 
@@ -115,6 +121,10 @@ func (r *Reader) ReadMessage(ctx context.Context) (kafka.Message, error) {
 		return kafka.Message{}, err
 	}
 	r.prev = r.startSpan(ctx, &msg)
+	if r.cfg.dataStreamsEnabled && r.groupID != "" {
+		// ReadMessage commits the offset automatically when a consumer group is set.
+		tracer.TrackKafkaCommitOffset(r.groupID, msg.Topic, int32(msg.Partition), msg.Offset)
+	}
 	return msg, nil
 }
 
@@ -132,7 +142,24 @@ func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
 	return msg, nil
 }
 
+// CommitMessages calls kafka.Reader.CommitMessages and, if Data Streams is
+// enabled, tracks the committed offsets.
+func (r *Reader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	err := r.Reader.CommitMessages(ctx, msgs...)
+	if err == nil && r.cfg.dataStreamsEnabled {
+		for _, msg := range msgs {
+			tracer.TrackKafkaCommitOffset(r.groupID, msg.Topic, int32(msg.Partition), msg.Offset)
+		}
+	}
+	return err
+}
+
 // WrapWriter wraps a kafka.Writer so requests are traced.
+//
+// When Data Streams is enabled, the Completion function of w is replaced by one
+// tracking the produced offsets and then calling the previous function, as the
+// produced offsets are only reported to it: w is modified in place. Wrapping the
+// same writer again does not track the offsets twice.
 func WrapWriter(w *kafka.Writer, opts ...Option) *Writer {
 	writer := &Writer{
 		Writer: w,
@@ -142,10 +169,42 @@ func WrapWriter(w *kafka.Writer, opts ...Option) *Writer {
 	if w.Addr.String() != "" {
 		writer.bootstrapServers = w.Addr.String()
 	}
+	if writer.cfg.dataStreamsEnabled {
+		// The Completion function is only replaced the first time the writer
+		// is wrapped with Data Streams enabled.
+		if _, tracked := trackedWriters.LoadOrStore(w, struct{}{}); !tracked {
+			w.Completion = trackProduceOffsets(w.Completion)
+		}
+	}
 	log.Debug("contrib/segmentio/kafka.go.v0: Wrapping Writer: %#v", writer.cfg)
 	return writer
 }
 
+// trackedWriters holds the writers whose Completion function was replaced by
+// trackProduceOffsets, until they are closed through Writer.Close.
+var trackedWriters sync.Map // map[*kafka.Writer]struct{}
+
+// trackProduceOffset tracks a produced offset. It is a variable so that tests
+// can observe the tracked offsets.
+var trackProduceOffset = tracer.TrackKafkaProduceOffset
+
+// trackProduceOffsets returns a Completion function tracking the produced
+// offsets and then calling completion. The messages passed to the Completion
+// function have their partition and offset set, so this is where produced
+// offsets can be tracked.
+func trackProduceOffsets(completion func([]kafka.Message, error)) func([]kafka.Message, error) {
+	return func(msgs []kafka.Message, err error) {
+		if err == nil {
+			for _, msg := range msgs {
+				trackProduceOffset(msg.Topic, int32(msg.Partition), msg.Offset)
+			}
+		}
+		if completion != nil {
+			completion(msgs, err)
+		}
+	}
+}
+
 // Writer wraps a kafka.Writer with tracing config data
 type Writer struct {
 	*kafka.Writer
@@ -162,11 +221,11 @@ func (w *Writer) startSpan(ctx context.Context, msg *kafka.Message) ddtrace.Span
 		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemKafka),
 		tracer.Tag(ext.KafkaBootstrapServers, w.bootstrapServers),
 	}
+	topic := msg.Topic
 	if w.Writer.Topic != "" {
-		opts = append(opts, tracer.ResourceName("Produce Topic "+w.Writer.Topic))
-	} else {
-		opts = append(opts, tracer.ResourceName("Produce Topic "+msg.Topic))
+		topic = w.Writer.Topic
 	}
+	opts = append(opts, tracer.ResourceName("Produce Topic "+topic))
 	if !math.IsNaN(w.cfg.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, w.cfg.analyticsRate))
 	}
@@ -175,6 +234,7 @@ func (w *Writer) startSpan(ctx context.Context, msg *kafka.Message) ddtrace.Span
 	if err := tracer.Inject(span.Context(), carrier); err != nil {
 		log.Debug("contrib/segmentio/kafka.go.v0: Failed to inject span context into carrier in writer, %v", err)
 	}
+	setProduceCheckpoint(w.cfg.dataStreamsEnabled, topic, msg)
 	return span
 }
 
@@ -184,6 +244,12 @@ func finishSpan(span ddtrace.Span, partition int, offset int64, err error) {
 	span.Finish(tracer.WithError(err))
 }
 
+// Close calls kafka.go.v0.Writer.Close and forgets the writer.
+func (w *Writer) Close() error {
+	trackedWriters.Delete(w.Writer)
+	return w.Writer.Close()
+}
+
 // WriteMessages calls kafka.go.v0.Writer.WriteMessages and traces the requests.
 func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
 	// although there's only one call made to the SyncProducer, the messages are
@@ -198,3 +264,39 @@ func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error
 	}
 	return err
 }
+
+func setProduceCheckpoint(enabled bool, topic string, msg *kafka.Message) {
+	if !enabled || msg == nil {
+		return
+	}
+	edges := []string{"direction:out", "topic:" + topic, "type:kafka"}
+	carrier := messageCarrier{msg}
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func setConsumeCheckpoint(enabled bool, groupID string, msg *kafka.Message) {
+	if !enabled || msg == nil {
+		return
+	}
+	edges := []string{"direction:in", "topic:" + msg.Topic, "type:kafka"}
+	if groupID != "" {
+		edges = append(edges, "group:"+groupID)
+	}
+	carrier := messageCarrier{msg}
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+func getMsgSize(msg *kafka.Message) (size int64) {
+	for _, header := range msg.Headers {
+		size += int64(len(header.Key) + len(header.Value))
+	}
+	return size + int64(len(msg.Value)+len(msg.Key))
+}
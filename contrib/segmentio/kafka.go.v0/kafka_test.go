@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/nowfred/dd-trace-go/contrib/internal/namingschematest"
+	"github.com/nowfred/dd-trace-go/datastreams"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
 
 	kafka "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
@@ -177,6 +179,37 @@ func TestFetchMessageFunctional(t *testing.T) {
 	assert.Equal(t, "localhost:9092,localhost:9093,localhost:9094", s1.Tag(ext.KafkaBootstrapServers))
 }
 
+func TestDataStreams(t *testing.T) {
+	var readMsg kafka.Message
+	genIntegrationTestSpans(
+		t,
+		func(t *testing.T, w *Writer) {
+			err := w.WriteMessages(context.Background(), testMessages...)
+			require.NoError(t, err, "Expected to write message to topic")
+		},
+		func(t *testing.T, r *Reader) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			var err error
+			readMsg, err = r.FetchMessage(ctx)
+			require.NoError(t, err, "Expected to consume message")
+
+			err = r.CommitMessages(context.Background(), readMsg)
+			assert.NoError(t, err, "Expected CommitMessages to not return an error")
+		},
+		[]Option{WithDataStreams()},
+		[]Option{WithDataStreams()},
+	)
+
+	p, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), messageCarrier{&readMsg}))
+	require.True(t, ok)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:"+testTopic, "type:kafka")
+	expectedCtx, _ := tracer.SetDataStreamsCheckpoint(ctx, "direction:in", "topic:"+testTopic, "type:kafka", "group:"+testGroupID)
+	expected, _ := datastreams.PathwayFromContext(expectedCtx)
+	assert.NotEqual(t, expected.GetHash(), 0)
+	assert.Equal(t, expected.GetHash(), p.GetHash())
+}
+
 func TestNamingSchema(t *testing.T) {
 	genSpans := func(t *testing.T, serviceOverride string) []mocktracer.Span {
 		var opts []Option
@@ -233,3 +266,39 @@ func BenchmarkWriterStartSpan(b *testing.B) {
 		w.startSpan(nil, &testMessages[0])
 	}
 }
+
+func TestWrapWriterCompletion(t *testing.T) {
+	var offsets []int64
+	defer func(track func(string, int32, int64)) { trackProduceOffset = track }(trackProduceOffset)
+	trackProduceOffset = func(_ string, _ int32, offset int64) {
+		offsets = append(offsets, offset)
+	}
+
+	var calls int
+	kw := &kafka.Writer{
+		Addr: kafka.TCP("localhost:9092"),
+		Completion: func([]kafka.Message, error) {
+			calls++
+		},
+	}
+	w := WrapWriter(kw, WithDataStreams())
+	// wrapping the writer again does not track the offsets twice
+	WrapWriter(kw, WithDataStreams())
+	kw.Completion([]kafka.Message{{Topic: testTopic, Offset: 1}}, nil)
+	assert.Equal(t, []int64{1}, offsets)
+	assert.Equal(t, 1, calls)
+
+	// failed writes are not tracked
+	kw.Completion([]kafka.Message{{Topic: testTopic, Offset: 2}}, assert.AnError)
+	assert.Equal(t, []int64{1}, offsets)
+	assert.Equal(t, 2, calls)
+
+	require.NoError(t, w.Close())
+	_, tracked := trackedWriters.Load(kw)
+	assert.False(t, tracked)
+
+	// the Completion function is left untouched without Data Streams
+	kw = &kafka.Writer{Addr: kafka.TCP("localhost:9092")}
+	WrapWriter(kw)
+	assert.Nil(t, kw.Completion)
+}
@@ -7,7 +7,6 @@
 package negroni
 
 import (
-	"fmt"
 	"math"
 	"net/http"
 
@@ -45,18 +44,11 @@ func (m *DatadogMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, ne
 	span, ctx := httptrace.StartRequestSpan(r, opts...)
 	defer func() {
 		// check if the responseWriter is of type negroni.ResponseWriter
-		var (
-			status int
-			opts   []tracer.FinishOption
-		)
-		responseWriter, ok := w.(negroni.ResponseWriter)
-		if ok {
+		var status int
+		if responseWriter, ok := w.(negroni.ResponseWriter); ok {
 			status = responseWriter.Status()
-			if m.cfg.isStatusError(status) {
-				opts = []tracer.FinishOption{tracer.WithError(fmt.Errorf("%d: %s", status, http.StatusText(status)))}
-			}
 		}
-		httptrace.FinishRequestSpan(span, status, opts...)
+		httptrace.FinishRequestSpan(span, status, m.cfg.isStatusError)
 	}()
 
 	next(w, r.WithContext(ctx))
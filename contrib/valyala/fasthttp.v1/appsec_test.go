@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package fasthttp
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/mocktracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestAppSec(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	appsec.Start()
+	defer appsec.Stop()
+
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	var called bool
+	handler := WrapHandler(func(fctx *fasthttp.RequestCtx) {
+		called = true
+		fctx.SetBodyString("Hello " + string(fctx.QueryArgs().Peek("name")) + "!\n")
+	})
+
+	for _, tc := range []struct {
+		name    string
+		query   string
+		blocked bool
+	}{
+		{name: "query-blocking", query: "$globals", blocked: true},
+		{name: "no-attack", query: "bob"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			called = false
+
+			var req fasthttp.Request
+			req.SetRequestURI("/hello?name=" + url.QueryEscape(tc.query))
+			var fctx fasthttp.RequestCtx
+			fctx.Init(&req, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil)
+			handler(&fctx)
+
+			finished := mt.FinishedSpans()
+			require.Len(t, finished, 1)
+			require.Equal(t, !tc.blocked, called)
+			if !tc.blocked {
+				require.Equal(t, http.StatusOK, fctx.Response.StatusCode())
+				require.Equal(t, "Hello bob!\n", string(fctx.Response.Body()))
+				return
+			}
+			require.Equal(t, http.StatusForbidden, fctx.Response.StatusCode())
+			require.Equal(t, "403", finished[0].Tag("http.status_code"))
+			require.Equal(t, true, finished[0].Tag("appsec.blocked"))
+			event := finished[0].Tag("_dd.appsec.json")
+			require.Contains(t, event, "crs-933-130-block")
+			require.Contains(t, event, "server.request.query")
+		})
+	}
+}
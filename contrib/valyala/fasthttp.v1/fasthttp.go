@@ -7,6 +7,7 @@
 package fasthttp // import "github.com/nowfred/dd-trace-go/contrib/valyala/fasthttp.v1"
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/ddtrace/ext"
 	"github.com/nowfred/dd-trace-go/ddtrace/tracer"
+	"github.com/nowfred/dd-trace-go/internal/appsec"
 	"github.com/nowfred/dd-trace-go/internal/log"
 	"github.com/nowfred/dd-trace-go/internal/telemetry"
 	"github.com/valyala/fasthttp"
@@ -50,7 +52,14 @@ func WrapHandler(h fasthttp.RequestHandler, opts ...Option) fasthttp.RequestHand
 		}
 		span := fasthttptrace.StartSpanFromContext(fctx, "http.request", spanOpts...)
 		defer span.Finish()
-		h(fctx)
+		if appsec.Enabled() {
+			// fasthttp has no router and therefore no path parameters to monitor
+			fasthttptrace.WithAppSec(fctx, fctx, span, nil, func(context.Context) {
+				h(fctx)
+			})
+		} else {
+			h(fctx)
+		}
 		span.SetTag(ext.ResourceName, cfg.resourceNamer(fctx))
 		status := fctx.Response.StatusCode()
 		if cfg.isStatusError(status) {
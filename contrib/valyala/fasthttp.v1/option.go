@@ -6,6 +6,7 @@
 package fasthttp
 
 import (
+	"github.com/nowfred/dd-trace-go/contrib/internal/httptrace"
 	"github.com/nowfred/dd-trace-go/ddtrace"
 	"github.com/nowfred/dd-trace-go/internal/namingschema"
 	"github.com/valyala/fasthttp"
@@ -28,7 +29,7 @@ func newConfig() *config {
 	return &config{
 		serviceName:   namingschema.ServiceName(defaultServiceName),
 		spanName:      namingschema.OpName(namingschema.HTTPServer),
-		isStatusError: defaultIsServerError,
+		isStatusError: httptrace.IsServerError,
 		resourceNamer: defaultResourceNamer,
 		ignoreRequest: defaultIgnoreRequest,
 	}
@@ -72,10 +73,6 @@ func WithIgnoreRequest(f func(fctx *fasthttp.RequestCtx) bool) Option {
 	}
 }
 
-func defaultIsServerError(statusCode int) bool {
-	return statusCode >= 500 && statusCode < 600
-}
-
 func defaultResourceNamer(fctx *fasthttp.RequestCtx) string {
 	return string(fctx.Method()) + " " + string(fctx.Path())
 }
@@ -62,11 +62,12 @@ func Middleware(opts ...Option) func(*web.C, http.Handler) http.Handler {
 				})
 			}
 			httptrace.TraceAndServe(h, w, r, &httptrace.ServeConfig{
-				Service:    cfg.serviceName,
-				Resource:   resource,
-				FinishOpts: cfg.finishOpts,
-				SpanOpts:   cfg.spanOpts,
-				Route:      route,
+				Service:       cfg.serviceName,
+				Resource:      resource,
+				FinishOpts:    cfg.finishOpts,
+				SpanOpts:      cfg.spanOpts,
+				Route:         route,
+				IsStatusError: cfg.isStatusError,
 			})
 		})
 	}
@@ -22,6 +22,7 @@ type config struct {
 	spanOpts      []ddtrace.StartSpanOption
 	finishOpts    []ddtrace.FinishOption
 	analyticsRate float64
+	isStatusError func(statusCode int) bool
 }
 
 // Option represents an option that can be passed to New.
@@ -81,3 +82,12 @@ func WithAnalyticsRate(rate float64) Option {
 		}
 	}
 }
+
+// WithStatusCheck specifies a function fn which reports whether the passed
+// statusCode should be considered an error. It defaults to the status codes
+// configured with DD_TRACE_HTTP_SERVER_ERROR_STATUSES, 5xx if unset.
+func WithStatusCheck(fn func(statusCode int) bool) Option {
+	return func(cfg *config) {
+		cfg.isStatusError = fn
+	}
+}
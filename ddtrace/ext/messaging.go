@@ -8,12 +8,16 @@ package ext
 const (
 	// MessagingSystem identifies which messaging system created this span (kafka, rabbitmq, amazonsqs, googlepubsub...)
 	MessagingSystem = "messaging.system"
+	// MessagingDestinationName identifies the message destination, such as a topic, queue or subject.
+	MessagingDestinationName = "messaging.destination.name"
 )
 
 // Available values for messaging.system.
 const (
 	MessagingSystemGCPPubsub = "googlepubsub"
 	MessagingSystemKafka     = "kafka"
+	MessagingSystemNATS      = "nats"
+	MessagingSystemRabbitMQ  = "rabbitmq"
 )
 
 // Kafka tags.
@@ -23,3 +27,25 @@ const (
 	// KafkaBootstrapServers holds a comma separated list of bootstrap servers as defined in producer or consumer config.
 	KafkaBootstrapServers = "messaging.kafka.bootstrap.servers"
 )
+
+// NATS tags.
+const (
+	// MessagingNATSQueueGroup defines the queue group a NATS subscription belongs to.
+	MessagingNATSQueueGroup = "messaging.nats.queue_group"
+	// MessagingNATSStream defines the JetStream stream a message was stored in.
+	MessagingNATSStream = "messaging.nats.stream"
+	// MessagingNATSConsumer defines the JetStream consumer a message was delivered to.
+	MessagingNATSConsumer = "messaging.nats.consumer"
+	// MessagingNATSSequence defines the JetStream stream sequence of a message.
+	MessagingNATSSequence = "messaging.nats.sequence"
+)
+
+// RabbitMQ tags.
+const (
+	// MessagingRabbitMQExchange defines the exchange a message was published to.
+	MessagingRabbitMQExchange = "messaging.rabbitmq.exchange"
+	// MessagingRabbitMQRoutingKey defines the routing key a message was published with.
+	MessagingRabbitMQRoutingKey = "messaging.rabbitmq.routing_key"
+	// MessagingRabbitMQQueue defines the queue a message was consumed from.
+	MessagingRabbitMQQueue = "messaging.rabbitmq.queue"
+)
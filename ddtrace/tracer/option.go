@@ -45,6 +45,7 @@ var contribIntegrations = map[string]struct {
 	"github.com/bradfitz/gomemcache":                {"Memcache", false},
 	"cloud.google.com/go/pubsub.v1":                 {"Pub/Sub", false},
 	"github.com/confluentinc/confluent-kafka-go":    {"Kafka (confluent)", false},
+	"connectrpc.com/connect":                        {"Connect", false},
 	"github.com/confluentinc/confluent-kafka-go/v2": {"Kafka (confluent) v2", false},
 	"database/sql":                                  {"SQL", false},
 	"github.com/dimfeld/httptreemux/v5":             {"HTTP Treemux", false},
@@ -61,6 +62,7 @@ var contribIntegrations = map[string]struct {
 	"github.com/go-redis/redis/v7":                  {"Redis v7", false},
 	"github.com/go-redis/redis/v8":                  {"Redis v8", false},
 	"go.mongodb.org/mongo-driver":                   {"MongoDB", false},
+	"go.temporal.io/sdk":                            {"Temporal", false},
 	"github.com/gocql/gocql":                        {"Cassandra", false},
 	"github.com/gofiber/fiber/v2":                   {"Fiber", false},
 	"github.com/gomodule/redigo":                    {"Redigo", false},
@@ -69,6 +71,7 @@ var contribIntegrations = map[string]struct {
 	"google.golang.org/grpc/v12":                    {"gRPC v12", false},
 	"gopkg.in/jinzhu/gorm.v1":                       {"Gorm (gopkg)", false},
 	"github.com/gorilla/mux":                        {"Gorilla Mux", false},
+	"github.com/gorilla/sessions":                   {"Gorilla Sessions", false},
 	"gorm.io/gorm.v1":                               {"Gorm v1", false},
 	"github.com/graph-gophers/graphql-go":           {"GraphQL", false},
 	"github.com/hashicorp/consul/api":               {"Consul", false},
@@ -79,11 +82,15 @@ var contribIntegrations = map[string]struct {
 	"k8s.io/client-go/kubernetes":                   {"Kubernetes", false},
 	"github.com/labstack/echo":                      {"echo", false},
 	"github.com/labstack/echo/v4":                   {"echo v4", false},
+	"github.com/labstack/echo-jwt/v4":               {"echo-jwt v4", false},
 	"github.com/miekg/dns":                          {"miekg/dns", false},
+	"github.com/nats-io/nats.go":                    {"NATS", false},
 	"net/http":                                      {"HTTP", false},
 	"gopkg.in/olivere/elastic.v5":                   {"Elasticsearch v5", false},
 	"gopkg.in/olivere/elastic.v3":                   {"Elasticsearch v3", false},
+	"github.com/rabbitmq/amqp091-go":                {"RabbitMQ", false},
 	"github.com/redis/go-redis/v9":                  {"Redis v9", false},
+	"github.com/redis/rueidis":                      {"Redis rueidis", false},
 	"github.com/segmentio/kafka-go":                 {"Kafka v0", false},
 	"github.com/IBM/sarama":                         {"IBM sarama", false},
 	"github.com/Shopify/sarama":                     {"Shopify sarama", false},
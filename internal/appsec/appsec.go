@@ -14,6 +14,7 @@ import (
 	waf "github.com/DataDog/go-libddwaf/v2"
 	"github.com/nowfred/dd-trace-go/internal/appsec/config"
 	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener/httpsec"
 	"github.com/nowfred/dd-trace-go/internal/log"
 )
 
@@ -25,6 +26,17 @@ func Enabled() bool {
 	return activeAppSec != nil && activeAppSec.started
 }
 
+// UserEventsMode returns the mode of the automated user events tracking. It
+// returns config.UserEventsModeDisabled when AppSec is not running.
+func UserEventsMode() config.UserEventsMode {
+	mu.RLock()
+	defer mu.RUnlock()
+	if activeAppSec == nil || !activeAppSec.started {
+		return config.UserEventsModeDisabled
+	}
+	return activeAppSec.cfg.UserEventsMode
+}
+
 // Start AppSec when enabled is enabled by both using the appsec build tag and
 // setting the environment variable DD_APPSEC_ENABLED to true.
 func Start(opts ...config.StartOption) {
@@ -130,6 +142,11 @@ type appsec struct {
 	limiter   *limiter.TokenTicker
 	wafHandle *wafHandle
 	started   bool
+	// rulesMu serializes the security rules updates coming from remote config and the rules file watcher
+	rulesMu      sync.Mutex
+	rulesWatcher *rulesFileWatcher
+	// clientIPRateLimiter limits the number of requests per second of each client IP, nil when disabled
+	clientIPRateLimiter *httpsec.ClientIPRateLimiter
 }
 
 func newAppSec(cfg *config.Config) *appsec {
@@ -155,6 +172,10 @@ func (a *appsec) start(telemetry *appsecTelemetry) error {
 	a.limiter = limiter.NewTokenTicker(a.cfg.TraceRateLimit, a.cfg.TraceRateLimit)
 	a.limiter.Start()
 
+	if a.cfg.ClientIPRateLimit > 0 {
+		a.clientIPRateLimiter = httpsec.NewClientIPRateLimiter(a.cfg.ClientIPRateLimit, a.cfg.ClientIPRateLimitBurst)
+	}
+
 	// Register the WAF operation event listener
 	if err := a.swapWAF(a.cfg.RulesManager.Latest); err != nil {
 		return err
@@ -163,6 +184,7 @@ func (a *appsec) start(telemetry *appsecTelemetry) error {
 	a.enableRCBlocking()
 
 	a.started = true
+	a.startRulesFileWatcher()
 	log.Info("appsec: up and running")
 
 	// TODO: log the config like the APM tracer does but we first need to define
@@ -180,6 +202,8 @@ func (a *appsec) stop() {
 	telemetry := newAppsecTelemetry()
 	defer telemetry.emit()
 
+	// Stop reloading the rules file before anything else so that it cannot swap the WAF handle anymore
+	a.stopRulesFileWatcher()
 	a.started = false
 	// Disable RC blocking first so that the following is guaranteed not to be concurrent anymore.
 	a.disableRCBlocking()
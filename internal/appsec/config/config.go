@@ -12,11 +12,32 @@ import (
 	"time"
 
 	internal "github.com/DataDog/appsec-internal-go/appsec"
+	"github.com/nowfred/dd-trace-go/internal/log"
 	"github.com/nowfred/dd-trace-go/internal/remoteconfig"
 )
 
-// EnvEnabled is the env var used to enable/disable appsec
-const EnvEnabled = "DD_APPSEC_ENABLED"
+const (
+	// EnvEnabled is the env var used to enable/disable appsec
+	EnvEnabled = "DD_APPSEC_ENABLED"
+	// EnvMaxResponseBodySize is the env var used to set the maximum size of the
+	// JSON response bodies buffered and inspected by the WAF.
+	EnvMaxResponseBodySize = "DD_APPSEC_MAX_RESPONSE_BODY_SIZE"
+	// EnvRulesReloadInterval is the env var used to set the interval at which
+	// the rules file set with DD_APPSEC_RULES is checked for changes in order
+	// to reload the security rules.
+	EnvRulesReloadInterval = "DD_APPSEC_RULES_RELOAD_INTERVAL"
+	// EnvClientIPRateLimit is the env var used to set the maximum number of
+	// requests per second of each client IP, above which its requests are
+	// blocked.
+	EnvClientIPRateLimit = "DD_APPSEC_CLIENT_IP_RATE_LIMIT"
+	// EnvClientIPRateLimitBurst is the env var used to set the number of
+	// requests a client IP can send at once above its rate limit.
+	EnvClientIPRateLimitBurst = "DD_APPSEC_CLIENT_IP_RATE_LIMIT_BURST"
+)
+
+// DefaultMaxResponseBodySize is the default maximum size of the JSON response
+// bodies buffered and inspected by the WAF.
+const DefaultMaxResponseBodySize = 64 * 1024
 
 // StartOption is used to customize the AppSec configuration when invoked with appsec.Start()
 type StartOption func(c *Config)
@@ -26,6 +47,15 @@ type Config struct {
 	// rules loaded via the env var DD_APPSEC_RULES. When not set, the builtin rules will be used
 	// and live-updated with remote configuration.
 	RulesManager *RulesManager
+	// Path of the rules file set with DD_APPSEC_RULES, or empty when the builtin rules are used.
+	RulesFile string
+	// Interval at which RulesFile is checked for changes in order to reload the rules without remote configuration.
+	// The rules file is not reloaded when zero (default).
+	RulesReloadInterval time.Duration
+	// UserEventsMode is the mode of the automated user events tracking.
+	UserEventsMode UserEventsMode
+	// LoginRules are the rules detecting the outcome of the login requests for the automated user events tracking.
+	LoginRules []LoginRule
 	// Maximum WAF execution time
 	WAFTimeout time.Duration
 	// AppSec trace rate limit (traces per second).
@@ -34,6 +64,14 @@ type Config struct {
 	Obfuscator internal.ObfuscatorConfig
 	// APISec configuration
 	APISec internal.APISecConfig
+	// Maximum size of the JSON response bodies buffered and inspected by the WAF. Larger response bodies are written
+	// as they come and are not inspected.
+	MaxResponseBodySize int
+	// Maximum number of requests per second of each client IP, above which its requests are blocked. The client IPs
+	// are not rate limited when zero (default).
+	ClientIPRateLimit int
+	// Number of requests a client IP can send at once above its rate limit. Defaults to ClientIPRateLimit.
+	ClientIPRateLimitBurst int
 	// RC is the remote configuration client used to receive product configuration updates. Nil if RC is disabled (default)
 	RC *remoteconfig.ClientConfig
 }
@@ -71,11 +109,66 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	rateLimit := positiveIntFromEnv(EnvClientIPRateLimit, 0)
 	return &Config{
 		RulesManager:   r,
 		WAFTimeout:     internal.WAFTimeoutFromEnv(),
 		TraceRateLimit: int64(internal.RateLimitFromEnv()),
 		Obfuscator:     internal.NewObfuscatorConfig(),
 		APISec:         internal.NewAPISecConfig(),
+
+		RulesFile:           os.Getenv(internal.EnvRules),
+		RulesReloadInterval: rulesReloadIntervalFromEnv(),
+		MaxResponseBodySize: maxResponseBodySizeFromEnv(),
+		UserEventsMode:      userEventsModeFromEnv(),
+		LoginRules:          loginRulesFromEnv(),
+
+		ClientIPRateLimit:      rateLimit,
+		ClientIPRateLimitBurst: positiveIntFromEnv(EnvClientIPRateLimitBurst, rateLimit),
 	}, nil
 }
+
+// maxResponseBodySizeFromEnv reads and parses the maximum response body size
+// set through the env. If not set, it defaults to DefaultMaxResponseBodySize.
+func maxResponseBodySizeFromEnv() int {
+	value := os.Getenv(EnvMaxResponseBodySize)
+	if value == "" {
+		return DefaultMaxResponseBodySize
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Error("appsec: could not parse %s value `%s` as a strictly positive integer, using the default value %d", EnvMaxResponseBodySize, value, DefaultMaxResponseBodySize)
+		return DefaultMaxResponseBodySize
+	}
+	return parsed
+}
+
+// rulesReloadIntervalFromEnv reads and parses the rules file reload interval
+// set through the env. If not set, it defaults to zero, disabling the reload.
+func rulesReloadIntervalFromEnv() time.Duration {
+	value := os.Getenv(EnvRulesReloadInterval)
+	if value == "" {
+		return 0
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		log.Error("appsec: could not parse %s value `%s` as a positive duration, the rules file won't be reloaded", EnvRulesReloadInterval, value)
+		return 0
+	}
+	return parsed
+}
+
+// positiveIntFromEnv reads and parses the positive integer value of the given
+// env var. If not set or invalid, it defaults to def.
+func positiveIntFromEnv(env string, def int) int {
+	value := os.Getenv(env)
+	if value == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Error("appsec: could not parse %s value `%s` as a positive integer, using the default value %d", env, value, def)
+		return def
+	}
+	return parsed
+}
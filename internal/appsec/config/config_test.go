@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxResponseBodySizeFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "unset", expected: DefaultMaxResponseBodySize},
+		{name: "valid", value: "1024", expected: 1024},
+		{name: "zero", value: "0", expected: DefaultMaxResponseBodySize},
+		{name: "negative", value: "-1", expected: DefaultMaxResponseBodySize},
+		{name: "invalid", value: "64KB", expected: DefaultMaxResponseBodySize},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvMaxResponseBodySize, tc.value)
+			require.Equal(t, tc.expected, maxResponseBodySizeFromEnv())
+		})
+	}
+}
+
+func TestRulesReloadIntervalFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset"},
+		{name: "valid", value: "30s", expected: 30 * time.Second},
+		{name: "negative", value: "-1s"},
+		{name: "invalid", value: "30"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvRulesReloadInterval, tc.value)
+			require.Equal(t, tc.expected, rulesReloadIntervalFromEnv())
+		})
+	}
+}
+
+func TestPositiveIntFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "unset", expected: 10},
+		{name: "valid", value: "100", expected: 100},
+		{name: "zero", value: "0", expected: 0},
+		{name: "negative", value: "-1", expected: 10},
+		{name: "invalid", value: "100rps", expected: 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvClientIPRateLimit, tc.value)
+			require.Equal(t, tc.expected, positiveIntFromEnv(EnvClientIPRateLimit, 10))
+		})
+	}
+}
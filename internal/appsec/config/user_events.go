@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+const (
+	// EnvUserEventsTracking is the env var used to enable the automated
+	// tracking of the user login events, either in safe or extended mode.
+	EnvUserEventsTracking = "DD_APPSEC_AUTOMATED_USER_EVENTS_TRACKING"
+	// EnvUserEventsLoginRules is the env var used to set the JSON array of
+	// login rules detecting the outcome of the login requests.
+	EnvUserEventsLoginRules = "DD_APPSEC_AUTOMATED_USER_EVENTS_LOGIN_RULES"
+)
+
+// UserEventsMode is the mode of the automated user events tracking.
+type UserEventsMode string
+
+const (
+	// UserEventsModeDisabled disables the automated user events tracking (default).
+	UserEventsModeDisabled UserEventsMode = "disabled"
+	// UserEventsModeSafe tracks the user events with anonymized user IDs.
+	UserEventsModeSafe UserEventsMode = "safe"
+	// UserEventsModeExtended tracks the user events with the user IDs as is.
+	UserEventsModeExtended UserEventsMode = "extended"
+)
+
+// Enabled returns true when the automated user events tracking is enabled.
+func (m UserEventsMode) Enabled() bool {
+	return m == UserEventsModeSafe || m == UserEventsModeExtended
+}
+
+// UserID returns the user ID to report according to the mode. User IDs are
+// anonymized in safe mode, so that they can be correlated without being
+// collected.
+func (m UserEventsMode) UserID(id string) string {
+	if m != UserEventsModeSafe || id == "" {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	return "anon_" + hex.EncodeToString(sum[:16])
+}
+
+// userEventsModeFromEnv reads and parses the automated user events tracking
+// mode set through the env. If not set, it defaults to UserEventsModeDisabled.
+func userEventsModeFromEnv() UserEventsMode {
+	value := os.Getenv(EnvUserEventsTracking)
+	switch mode := UserEventsMode(strings.ToLower(value)); mode {
+	case "", UserEventsModeDisabled:
+		return UserEventsModeDisabled
+	case UserEventsModeSafe, UserEventsModeExtended:
+		return mode
+	default:
+		log.Error("appsec: unexpected %s value `%s`, expecting one of `disabled`, `safe` or `extended`: the automated user events tracking is disabled", EnvUserEventsTracking, value)
+		return UserEventsModeDisabled
+	}
+}
+
+// LoginRule describes a login route along with the response status codes and
+// headers telling the login outcome.
+type LoginRule struct {
+	// Method is the HTTP method of the login requests. Any method matches
+	// when empty.
+	Method string `json:"method,omitempty"`
+	// Path is the URL path of the login requests.
+	Path string `json:"path"`
+	// SuccessStatus is the list of response status codes of successful logins.
+	// Defaults to any 2xx or 3xx status code.
+	SuccessStatus []int `json:"success_status,omitempty"`
+	// FailureStatus is the list of response status codes of failed logins.
+	// Defaults to 401 and 403.
+	FailureStatus []int `json:"failure_status,omitempty"`
+	// SuccessHeader is the name of the response header that must be present
+	// for a login to be successful, such as Set-Cookie or Authorization.
+	SuccessHeader string `json:"success_header,omitempty"`
+	// UserIDHeader is the name of the response or request header holding the
+	// ID of the user logging in, the response header taking precedence.
+	UserIDHeader string `json:"user_id_header,omitempty"`
+}
+
+// Match returns true when the given request method and URL path are the
+// ones of the login route.
+func (r *LoginRule) Match(method, path string) bool {
+	return path == r.Path && (r.Method == "" || strings.EqualFold(method, r.Method))
+}
+
+// Success returns true when the response status code and headers are the
+// ones of a successful login. The header names must be lowercase.
+func (r *LoginRule) Success(status int, headers map[string][]string) bool {
+	if r.SuccessHeader != "" && headerValue(headers, r.SuccessHeader) == "" {
+		return false
+	}
+	if len(r.SuccessStatus) == 0 {
+		return status >= 200 && status < 400
+	}
+	return containsStatus(r.SuccessStatus, status)
+}
+
+// Failure returns true when the response status code is the one of a failed
+// login.
+func (r *LoginRule) Failure(status int) bool {
+	if len(r.FailureStatus) == 0 {
+		return status == http.StatusUnauthorized || status == http.StatusForbidden
+	}
+	return containsStatus(r.FailureStatus, status)
+}
+
+// UserID returns the ID of the user logging in out of the response or request
+// headers. The header names must be lowercase.
+func (r *LoginRule) UserID(reqHeaders, resHeaders map[string][]string) string {
+	if r.UserIDHeader == "" {
+		return ""
+	}
+	if id := headerValue(resHeaders, r.UserIDHeader); id != "" {
+		return id
+	}
+	return headerValue(reqHeaders, r.UserIDHeader)
+}
+
+// headerValue returns the first value of the given header out of headers
+// whose names are lowercase.
+func headerValue(headers map[string][]string, name string) string {
+	if values := headers[strings.ToLower(name)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// loginRulesFromEnv reads and parses the JSON array of login rules set through
+// the env. Invalid rules are logged and ignored.
+func loginRulesFromEnv() []LoginRule {
+	value := os.Getenv(EnvUserEventsLoginRules)
+	if value == "" {
+		return nil
+	}
+	var rules []LoginRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		log.Error("appsec: could not parse %s value as a JSON array of login rules: %v", EnvUserEventsLoginRules, err)
+		return nil
+	}
+	valid := rules[:0]
+	for _, r := range rules {
+		if r.Path == "" {
+			log.Error("appsec: ignoring the login rule of %s without path", EnvUserEventsLoginRules)
+			continue
+		}
+		valid = append(valid, r)
+	}
+	return valid
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEventsModeFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected UserEventsMode
+	}{
+		{value: "", expected: UserEventsModeDisabled},
+		{value: "disabled", expected: UserEventsModeDisabled},
+		{value: "safe", expected: UserEventsModeSafe},
+		{value: "EXTENDED", expected: UserEventsModeExtended},
+		{value: "invalid", expected: UserEventsModeDisabled},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv(EnvUserEventsTracking, tc.value)
+			mode := userEventsModeFromEnv()
+			require.Equal(t, tc.expected, mode)
+			require.Equal(t, tc.expected != UserEventsModeDisabled, mode.Enabled())
+		})
+	}
+}
+
+func TestUserEventsModeUserID(t *testing.T) {
+	require.Equal(t, "user", UserEventsModeExtended.UserID("user"))
+	require.Equal(t, "anon_04f8996da763b7a969b1028ee3007569", UserEventsModeSafe.UserID("user"))
+	require.Equal(t, "", UserEventsModeSafe.UserID(""))
+}
+
+func TestLoginRulesFromEnv(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv(EnvUserEventsLoginRules, "")
+		require.Nil(t, loginRulesFromEnv())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv(EnvUserEventsLoginRules, `{"path":"/login"}`)
+		require.Nil(t, loginRulesFromEnv())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv(EnvUserEventsLoginRules, `[{"method":"POST","path":"/login","success_status":[302],"user_id_header":"X-User-Id"},{"method":"POST"}]`)
+		require.Equal(t, []LoginRule{{Method: "POST", Path: "/login", SuccessStatus: []int{302}, UserIDHeader: "X-User-Id"}}, loginRulesFromEnv())
+	})
+}
+
+func TestLoginRule(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		r := LoginRule{Method: "POST", Path: "/login"}
+		require.True(t, r.Match("post", "/login"))
+		require.False(t, r.Match("GET", "/login"))
+		require.False(t, r.Match("POST", "/logout"))
+		require.True(t, (&LoginRule{Path: "/login"}).Match("GET", "/login"))
+	})
+
+	t.Run("default-status", func(t *testing.T) {
+		r := LoginRule{Path: "/login"}
+		require.True(t, r.Success(200, nil))
+		require.True(t, r.Success(302, nil))
+		require.False(t, r.Success(401, nil))
+		require.True(t, r.Failure(401))
+		require.True(t, r.Failure(403))
+		require.False(t, r.Failure(500))
+	})
+
+	t.Run("custom-status-and-headers", func(t *testing.T) {
+		r := LoginRule{Path: "/login", SuccessStatus: []int{200}, FailureStatus: []int{200, 400}, SuccessHeader: "Set-Cookie", UserIDHeader: "X-User-Id"}
+		require.True(t, r.Success(200, map[string][]string{"set-cookie": {"session=1"}}))
+		require.False(t, r.Success(200, nil))
+		require.False(t, r.Success(302, map[string][]string{"set-cookie": {"session=1"}}))
+		require.True(t, r.Failure(400))
+		require.False(t, r.Failure(401))
+		require.Equal(t, "res", r.UserID(map[string][]string{"x-user-id": {"req"}}, map[string][]string{"x-user-id": {"res"}}))
+		require.Equal(t, "req", r.UserID(map[string][]string{"x-user-id": {"req"}}, nil))
+	})
+}
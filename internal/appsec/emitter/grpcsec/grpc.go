@@ -14,6 +14,7 @@ import (
 	"reflect"
 
 	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
 	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
 	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
 
@@ -110,6 +111,14 @@ func StartHandlerOperation(ctx context.Context, args HandlerOperationArgs, paren
 	for _, l := range listeners {
 		op.OnData(l)
 	}
+	// Apply the actions of the block request SDK calls regardless of the security rules. They all block gRPC
+	// requests, including the redirections, so the request is tagged as blocked.
+	op.On(sharedsec.OnBlockRequestOperationStart(func(blockOp *sharedsec.BlockRequestOperation, blockArgs sharedsec.BlockRequestOperationArgs) {
+		op.SetTag(trace.BlockedRequestTag, true)
+		op.EmitData(blockArgs.Action)
+		code, err := blockArgs.Action.GRPC()(args.Metadata)
+		blockOp.EmitData(NewMonitoringError(err.Error(), code))
+	}))
 	newCtx := context.WithValue(ctx, listener.ContextKey{}, op)
 	dyngo.StartOperation(op, args)
 	return newCtx, op
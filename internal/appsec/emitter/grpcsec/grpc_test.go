@@ -7,11 +7,14 @@ package grpcsec_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
 	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/grpcsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
 
 	"github.com/stretchr/testify/require"
 )
@@ -83,3 +86,29 @@ func TestUsage(t *testing.T) {
 	// messages), and bidirectional RPCs like client streaming RPCs (N client
 	// messages, M server messages).
 }
+
+func TestBlockRequest(t *testing.T) {
+	var blocked bool
+	ctx, op := grpcsec.StartHandlerOperation(context.Background(), grpcsec.HandlerOperationArgs{}, nil, dyngo.NewDataListener(func(a *sharedsec.Action) {
+		blocked = a.Blocking()
+	}))
+	defer op.Finish(grpcsec.HandlerOperationRes{})
+
+	err := sharedsec.BlockRequest(ctx, sharedsec.NewBlockRequestAction(403, 7, "auto"))
+	var monitoringErr *grpcsec.MonitoringError
+	require.True(t, errors.As(err, &monitoringErr))
+	require.Equal(t, uint32(7), monitoringErr.GRPCStatus())
+	require.True(t, blocked)
+	require.Equal(t, true, op.Tags()[trace.BlockedRequestTag])
+
+	t.Run("redirect", func(t *testing.T) {
+		ctx, op := grpcsec.StartHandlerOperation(context.Background(), grpcsec.HandlerOperationArgs{}, nil)
+		defer op.Finish(grpcsec.HandlerOperationRes{})
+
+		// Redirections block gRPC requests
+		err := sharedsec.BlockRequest(ctx, sharedsec.NewRedirectRequestAction(303, "/blocked"))
+		var monitoringErr *grpcsec.MonitoringError
+		require.True(t, errors.As(err, &monitoringErr))
+		require.Equal(t, true, op.Tags()[trace.BlockedRequestTag])
+	})
+}
@@ -55,6 +55,9 @@ type (
 		// Status corresponds to the address `server.response.status`.
 		Status  int
 		Headers map[string][]string
+		// Body corresponds to the address `server.response.body`. It is the
+		// parsed JSON response body when it was monitored, nil otherwise.
+		Body any
 	}
 
 	// SDKBodyOperationArgs is the SDK body operation arguments.
@@ -134,8 +137,26 @@ func WrapHandler(handler http.Handler, span ddtrace.Span, pathParams map[string]
 		}))
 		r = r.WithContext(ctx)
 
+		var bodyRecorder *responseBodyRecorder
+		if maxSize := op.responseBodyMaxSize(); maxSize > 0 {
+			bodyRecorder = newResponseBodyRecorder(w, maxSize)
+		}
+
 		defer func() {
-			events := op.Finish(MakeHandlerOperationRes(w))
+			var res HandlerOperationRes
+			if bodyRecorder != nil {
+				res = MakeHandlerOperationRes(bodyRecorder)
+				res.Body = bodyRecorder.parsedBody()
+			} else {
+				res = MakeHandlerOperationRes(w)
+			}
+			// The actions sent while finishing the operation, after the WAF inspected the response, can only be
+			// applied when the response is still buffered.
+			handlerBypass, handlerBlocking := bypassHandler, blocking
+			events := op.Finish(res)
+			if bodyRecorder == nil || bodyRecorder.committed {
+				bypassHandler, blocking = handlerBypass, handlerBlocking
+			}
 
 			// Execute the onBlock functions to make sure blocking works properly
 			// in case we are instrumenting the Gin framework
@@ -147,7 +168,13 @@ func WrapHandler(handler http.Handler, span ddtrace.Span, pathParams map[string]
 			}
 
 			if bypassHandler != nil {
+				// Replace the buffered response, if any, with the bypass one
+				if bodyRecorder != nil {
+					bodyRecorder.discard()
+				}
 				bypassHandler.ServeHTTP(w, r)
+			} else if bodyRecorder != nil {
+				bodyRecorder.commit()
 			}
 
 			// Add the request headers span tags out of args.Headers instead of r.Header as it was normalized and some
@@ -165,6 +192,10 @@ func WrapHandler(handler http.Handler, span ddtrace.Span, pathParams map[string]
 			handler = bypassHandler
 			bypassHandler = nil
 		}
+		if bodyRecorder != nil {
+			handler.ServeHTTP(bodyRecorder, r)
+			return
+		}
 		handler.ServeHTTP(w, r)
 	})
 }
@@ -231,7 +262,8 @@ type (
 		dyngo.Operation
 		trace.TagsHolder
 		trace.SecurityEventsHolder
-		mu sync.RWMutex
+		mu                  sync.RWMutex
+		maxResponseBodySize int
 	}
 
 	// SDKBodyOperation type representing an SDK body
@@ -252,6 +284,13 @@ func StartOperation(ctx context.Context, args HandlerOperationArgs, listeners ..
 	for _, l := range listeners {
 		op.OnData(l)
 	}
+	// Apply the actions of the block request SDK calls regardless of the security rules. They all replace the
+	// response, including the redirections which aren't blocking actions, so the request is tagged as blocked.
+	op.On(sharedsec.OnBlockRequestOperationStart(func(blockOp *sharedsec.BlockRequestOperation, args sharedsec.BlockRequestOperationArgs) {
+		op.SetTag(trace.BlockedRequestTag, true)
+		op.EmitData(args.Action)
+		blockOp.EmitData(NewMonitoringError("Request blocked"))
+	}))
 	newCtx := context.WithValue(ctx, listener.ContextKey{}, op)
 	dyngo.StartOperation(op, args)
 	return newCtx, op
@@ -264,6 +303,25 @@ func fromContext(ctx context.Context) *Operation {
 	return op
 }
 
+// MonitorResponseBody asks for the JSON response body of the request handler
+// to be buffered, up to maxSize bytes, and provided in the
+// HandlerOperationRes.Body value. The actions sent to the operation while
+// finishing it are then applied to the buffered response. It must be called by
+// the handler operation start event listeners.
+func (op *Operation) MonitorResponseBody(maxSize int) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if maxSize > op.maxResponseBodySize {
+		op.maxResponseBodySize = maxSize
+	}
+}
+
+func (op *Operation) responseBodyMaxSize() int {
+	op.mu.RLock()
+	defer op.mu.RUnlock()
+	return op.maxResponseBodySize
+}
+
 // Finish the HTTP handler operation, along with the given results and emits a
 // finish event up in the operation stack.
 func (op *Operation) Finish(res HandlerOperationRes) []any {
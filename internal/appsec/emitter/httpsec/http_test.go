@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testlib "github.com/nowfred/dd-trace-go/internal/appsec/_testlib"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapHandlerBlockRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		action   *sharedsec.Action
+		status   int
+		location string
+		body     string
+	}{
+		{
+			name:   "block",
+			action: sharedsec.NewBlockRequestAction(http.StatusUnavailableForLegalReasons, 10, "json"),
+			status: http.StatusUnavailableForLegalReasons,
+			body:   "You've been blocked",
+		},
+		{
+			name:     "redirect",
+			action:   sharedsec.NewRedirectRequestAction(http.StatusSeeOther, "/blocked"),
+			status:   http.StatusSeeOther,
+			location: "/blocked",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The block request SDK calls don't depend on any security rule listener
+			dyngo.SwapRootOperation(dyngo.NewRootOperation())
+
+			var err error
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err = sharedsec.BlockRequest(r.Context(), tc.action); err != nil {
+					return
+				}
+				w.Write([]byte("Hello World!\n"))
+			})
+			span := &testlib.MockSpan{}
+			w := httptest.NewRecorder()
+			WrapHandler(handler, span, nil).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			var monitoringErr *MonitoringError
+			require.True(t, errors.As(err, &monitoringErr))
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, tc.location, w.Header().Get("Location"))
+			require.Contains(t, w.Body.String(), tc.body)
+			// The redirections of the SDK are also reported as blocked requests
+			require.Equal(t, true, span.Tags[trace.BlockedRequestTag])
+		})
+	}
+}
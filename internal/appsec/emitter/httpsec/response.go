@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// responseBodyRecorder is the response writer WrapHandler passes to the
+// handler when the handler operation listeners asked for the response body
+// with Operation.MonitorResponseBody(). JSON responses are buffered, along with
+// their status code, until they are committed to the underlying response
+// writer once the handler operation is finished. This allows the monitoring to
+// inspect the response body and to replace the response with a blocking one
+// when needed. Responses whose body is larger than maxSize bytes are committed
+// as soon as the limit is reached and are not inspected, while non-JSON
+// responses are neither buffered nor inspected.
+type responseBodyRecorder struct {
+	http.ResponseWriter
+	maxSize     int
+	status      int
+	wroteHeader bool
+	committed   bool
+	skipped     bool
+	body        bytes.Buffer
+}
+
+func newResponseBodyRecorder(w http.ResponseWriter, maxSize int) *responseBodyRecorder {
+	return &responseBodyRecorder{ResponseWriter: w, maxSize: maxSize}
+}
+
+// WriteHeader implements http.ResponseWriter. The status code of JSON
+// responses is buffered until the response gets committed.
+func (w *responseBodyRecorder) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if !isJSON(w.Header().Get("Content-Type")) {
+		w.skipped = true
+		w.commit()
+	}
+}
+
+// Write implements http.ResponseWriter. JSON response bodies are buffered until
+// the response gets committed or until they get larger than maxSize.
+func (w *responseBodyRecorder) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.committed && w.body.Len()+len(b) > w.maxSize {
+		w.commit()
+	}
+	w.record(b)
+	if !w.committed {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// record b as part of the response body, unless it is skipped or is now too
+// large to be inspected.
+func (w *responseBodyRecorder) record(b []byte) {
+	if w.skipped || len(b) == 0 {
+		return
+	}
+	if w.body.Len()+len(b) > w.maxSize {
+		w.skipped = true
+		w.body = bytes.Buffer{}
+		return
+	}
+	w.body.Write(b)
+}
+
+// commit writes the buffered status code and response body to the underlying
+// response writer. Further writes are then directly written to it.
+func (w *responseBodyRecorder) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// discard the buffered response, along with the response headers, so that
+// another response can be written instead. It returns false when the response
+// was already committed and can no longer be replaced.
+func (w *responseBodyRecorder) discard() bool {
+	if w.committed {
+		return false
+	}
+	header := w.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	w.status = 0
+	w.wroteHeader = false
+	w.skipped = false
+	w.body = bytes.Buffer{}
+	return true
+}
+
+// parsedBody returns the recorded JSON response body once parsed, or nil when
+// it wasn't recorded or isn't valid JSON.
+func (w *responseBodyRecorder) parsedBody() any {
+	if w.skipped || w.body.Len() == 0 {
+		return nil
+	}
+	var body any
+	if err := json.Unmarshal(w.body.Bytes(), &body); err != nil {
+		return nil
+	}
+	return body
+}
+
+// Status returns the status code of the response, including when it is still
+// buffered.
+func (w *responseBodyRecorder) Status() int {
+	if w.wroteHeader {
+		return w.status
+	}
+	if mw, ok := w.ResponseWriter.(interface{ Status() int }); ok {
+		return mw.Status()
+	}
+	return 0
+}
+
+// Flush implements http.Flusher when the underlying writer does. The response
+// gets committed and can no longer be blocked.
+func (w *responseBodyRecorder) Flush() {
+	w.commit()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer does.
+func (w *responseBodyRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.committed = true
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the underlying response writer, for http.ResponseController.
+func (w *responseBodyRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// IsResponseBodyMonitored returns true when w is the response writer passed by
+// WrapHandler to a handler whose response body is monitored. Framework
+// integrations writing the response with their own response writer instead of
+// w must then write the response status code and body through w for them to be
+// buffered and inspected.
+func IsResponseBodyMonitored(w http.ResponseWriter) bool {
+	_, ok := w.(*responseBodyRecorder)
+	return ok
+}
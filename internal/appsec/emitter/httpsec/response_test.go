@@ -0,0 +1,205 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testlib "github.com/nowfred/dd-trace-go/internal/appsec/_testlib"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxResponseBodySize = 1024
+
+func TestResponseBodyRecorder(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		expected    any
+		buffered    bool
+	}{
+		{
+			name:        "json",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"key":["value",1]}`,
+			expected:    map[string]any{"key": []any{"value", 1.0}},
+			buffered:    true,
+		},
+		{
+			name:        "json-suffix",
+			contentType: "application/problem+json",
+			body:        `{"title":"not found"}`,
+			expected:    map[string]any{"title": "not found"},
+			buffered:    true,
+		},
+		{
+			name:        "html",
+			contentType: "text/html",
+			body:        `<html></html>`,
+		},
+		{
+			name: "no-content-type",
+			body: `{"key":"value"}`,
+		},
+		{
+			name:        "invalid-json",
+			contentType: "application/json",
+			body:        `{"key":`,
+			buffered:    true,
+		},
+		{
+			name:        "too-large",
+			contentType: "application/json",
+			body:        `"` + strings.Repeat("a", testMaxResponseBodySize) + `"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rec := newResponseBodyRecorder(w, testMaxResponseBodySize)
+			if tc.contentType != "" {
+				rec.Header().Set("Content-Type", tc.contentType)
+			}
+			rec.WriteHeader(http.StatusCreated)
+			// Write the body in two chunks
+			half := len(tc.body) / 2
+			rec.Write([]byte(tc.body[:half]))
+			rec.Write([]byte(tc.body[half:]))
+			require.Equal(t, http.StatusCreated, rec.Status())
+
+			if tc.buffered {
+				require.False(t, w.Flushed)
+				require.Empty(t, w.Body.String())
+				rec.commit()
+			}
+			require.Equal(t, http.StatusCreated, w.Code)
+			require.Equal(t, tc.body, w.Body.String())
+			require.Equal(t, tc.expected, rec.parsedBody())
+		})
+	}
+
+	t.Run("flush", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec := newResponseBodyRecorder(w, testMaxResponseBodySize)
+		rec.Header().Set("Content-Type", "application/json")
+		rec.Write([]byte(`{"key":`))
+		rec.Flush()
+		require.True(t, w.Flushed)
+		require.Equal(t, `{"key":`, w.Body.String())
+		rec.Write([]byte(`"value"}`))
+		require.Equal(t, `{"key":"value"}`, w.Body.String())
+		require.Equal(t, map[string]any{"key": "value"}, rec.parsedBody())
+		require.False(t, rec.discard())
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rec := newResponseBodyRecorder(w, testMaxResponseBodySize)
+		rec.Header().Set("Content-Type", "application/json")
+		rec.Header().Set("Content-Length", "15")
+		rec.Write([]byte(`{"key":"value"}`))
+		require.True(t, rec.discard())
+		require.Empty(t, w.Header())
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("blocked"))
+		rec.commit()
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Equal(t, "blocked", w.Body.String())
+	})
+}
+
+func TestWrapHandlerResponseBody(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		monitored bool
+		block     bool
+		flush     bool
+		status    int
+		body      string
+	}{
+		{
+			name:      "monitored",
+			monitored: true,
+			status:    http.StatusOK,
+			body:      `{"key":"value"}`,
+		},
+		{
+			name:   "not-monitored",
+			status: http.StatusOK,
+			body:   `{"key":"value"}`,
+		},
+		{
+			name:      "blocked",
+			monitored: true,
+			block:     true,
+			status:    http.StatusForbidden,
+		},
+		{
+			name:   "not-monitored-blocking",
+			block:  true,
+			status: http.StatusOK,
+			body:   `{"key":"value"}`,
+		},
+		{
+			name:      "flushed-blocking",
+			monitored: true,
+			block:     true,
+			flush:     true,
+			status:    http.StatusOK,
+			body:      `{"key":"value"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := dyngo.NewRootOperation()
+			dyngo.SwapRootOperation(root)
+			defer dyngo.SwapRootOperation(dyngo.NewRootOperation())
+
+			var body any
+			root.On(OnHandlerOperationStart(func(op *Operation, _ HandlerOperationArgs) {
+				if tc.monitored {
+					op.MonitorResponseBody(testMaxResponseBodySize)
+				}
+				op.On(OnHandlerOperationFinish(func(op *Operation, res HandlerOperationRes) {
+					body = res.Body
+					if tc.block {
+						op.EmitData(sharedsec.NewBlockRequestAction(http.StatusForbidden, 0, "json"))
+					}
+				}))
+			}))
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				require.Equal(t, tc.monitored, IsResponseBodyMonitored(w))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"key":"value"}`))
+				if tc.flush {
+					w.(http.Flusher).Flush()
+				}
+			})
+			w := httptest.NewRecorder()
+			WrapHandler(handler, &testlib.MockSpan{}, nil).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			require.Equal(t, tc.status, w.Code)
+			if tc.status == http.StatusForbidden {
+				require.Contains(t, w.Body.String(), "You've been blocked")
+			} else {
+				require.Equal(t, tc.body, w.Body.String())
+			}
+			if tc.monitored {
+				require.Equal(t, map[string]any{"key": "value"}, body)
+			} else {
+				require.Nil(t, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+type (
+	// BlockRequestOperation type representing a call to appsec.BlockRequest()
+	// or appsec.RedirectRequest(). It gets both created and destroyed in a
+	// single call to ExecuteBlockRequestOperation.
+	BlockRequestOperation struct {
+		dyngo.Operation
+	}
+	// BlockRequestOperationArgs is the block request operation arguments.
+	BlockRequestOperationArgs struct {
+		// Action is the action to apply to the request instead of the request
+		// handler's response.
+		Action *Action
+	}
+	// BlockRequestOperationRes is the block request operation results.
+	BlockRequestOperationRes struct{}
+
+	// OnBlockRequestOperationStart function type, called when a block request
+	// operation starts.
+	OnBlockRequestOperationStart func(operation *BlockRequestOperation, args BlockRequestOperationArgs)
+)
+
+var blockRequestOperationArgsType = reflect.TypeOf((*BlockRequestOperationArgs)(nil)).Elem()
+
+// ExecuteBlockRequestOperation starts and finishes the block request operation
+// by emitting a dyngo start and finish events. The error to be returned by the
+// request handler, which the handler operation sends when applying the action,
+// is returned.
+func ExecuteBlockRequestOperation(parent dyngo.Operation, args BlockRequestOperationArgs) error {
+	var err error
+	op := &BlockRequestOperation{Operation: dyngo.NewOperation(parent)}
+	OnErrorData(op, func(e error) {
+		err = e
+	})
+	dyngo.StartOperation(op, args)
+	dyngo.FinishOperation(op, BlockRequestOperationRes{})
+	return err
+}
+
+// ListenedType returns the type a OnBlockRequestOperationStart event listener
+// listens to, which is the BlockRequestOperationArgs type.
+func (OnBlockRequestOperationStart) ListenedType() reflect.Type {
+	return blockRequestOperationArgsType
+}
+
+// Call the underlying event listener function by performing the type-assertion
+// on v whose type is the one returned by ListenedType().
+func (f OnBlockRequestOperationStart) Call(op dyngo.Operation, v interface{}) {
+	f(op.(*BlockRequestOperation), v.(BlockRequestOperationArgs))
+}
+
+// BlockRequest starts and finishes a block request operation applying the
+// given action to the request monitored by the handler operation stored in
+// ctx. The returned error must be returned by the request handler so that the
+// framework integration stops it. Calls made outside of a monitored request
+// handler are ignored and nil is returned.
+func BlockRequest(ctx context.Context, action *Action) error {
+	if parent, ok := ctx.Value(listener.ContextKey{}).(dyngo.Operation); ok {
+		return ExecuteBlockRequestOperation(parent, BlockRequestOperationArgs{Action: action})
+	}
+	log.Error("appsec: request blocking ignored: could not find the http handler instrumentation metadata in the request context: the request handler is not being monitored by a middleware function or the provided context is not the expected request context")
+	return nil
+}
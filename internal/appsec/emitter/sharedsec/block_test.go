@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRequest(t *testing.T) {
+	type (
+		rootArgs struct{}
+		rootRes  struct{}
+	)
+	errBlocked := errors.New("blocked")
+
+	t.Run("monitored", func(t *testing.T) {
+		root := dyngo.NewOperation(nil)
+		dyngo.StartOperation(root, rootArgs{})
+		defer dyngo.FinishOperation(root, rootRes{})
+
+		var applied *sharedsec.Action
+		root.OnData(dyngo.NewDataListener(func(a *sharedsec.Action) {
+			applied = a
+		}))
+		root.On(sharedsec.OnBlockRequestOperationStart(func(op *sharedsec.BlockRequestOperation, args sharedsec.BlockRequestOperationArgs) {
+			op.EmitData(args.Action)
+			op.EmitData(errBlocked)
+		}))
+
+		action := sharedsec.NewBlockRequestAction(403, 10, "auto")
+		err := sharedsec.BlockRequest(context.WithValue(context.Background(), listener.ContextKey{}, root), action)
+		require.Equal(t, errBlocked, err)
+		require.Equal(t, action, applied)
+	})
+
+	t.Run("unmonitored", func(t *testing.T) {
+		require.NoError(t, sharedsec.BlockRequest(context.Background(), sharedsec.NewBlockRequestAction(403, 10, "auto")))
+	})
+}
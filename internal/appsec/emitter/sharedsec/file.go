@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+)
+
+type (
+	// FileOpenOperation type representing a file about to be opened while
+	// handling a monitored request. It gets both created and destroyed in a
+	// single call to ExecuteFileOpenOperation.
+	FileOpenOperation struct {
+		dyngo.Operation
+	}
+	// FileOpenOperationArgs is the file open operation arguments.
+	FileOpenOperationArgs struct {
+		// Path corresponds to the address `server.io.fs.file`.
+		Path string
+	}
+	// FileOpenOperationRes is the file open operation results.
+	FileOpenOperationRes struct{}
+
+	// OnFileOpenOperationStart function type, called when a file open
+	// operation starts.
+	OnFileOpenOperationStart func(operation *FileOpenOperation, args FileOpenOperationArgs)
+
+	// BlockedFileOpenError is the error returned when opening a file is
+	// blocked, for instance because its path was built out of user input to
+	// perform a path traversal or a Local File Inclusion (LFI).
+	BlockedFileOpenError struct {
+		Path string
+	}
+)
+
+// Error implements the error interface.
+func (e *BlockedFileOpenError) Error() string {
+	return "appsec: opening file " + e.Path + " blocked"
+}
+
+var fileOpenOperationArgsType = reflect.TypeOf((*FileOpenOperationArgs)(nil)).Elem()
+
+// ExecuteFileOpenOperation starts and finishes the file open operation by
+// emitting a dyngo start and finish events. An error is returned if the file
+// must not be opened.
+func ExecuteFileOpenOperation(parent dyngo.Operation, args FileOpenOperationArgs) error {
+	var err error
+	op := &FileOpenOperation{Operation: dyngo.NewOperation(parent)}
+	OnErrorData(op, func(e *BlockedFileOpenError) {
+		err = e
+	})
+	dyngo.StartOperation(op, args)
+	dyngo.FinishOperation(op, FileOpenOperationRes{})
+	return err
+}
+
+// ListenedType returns the type a OnFileOpenOperationStart event listener
+// listens to, which is the FileOpenOperationArgs type.
+func (OnFileOpenOperationStart) ListenedType() reflect.Type { return fileOpenOperationArgsType }
+
+// Call the underlying event listener function by performing the type-assertion
+// on v whose type is the one returned by ListenedType().
+func (f OnFileOpenOperationStart) Call(op dyngo.Operation, v interface{}) {
+	f(op.(*FileOpenOperation), v.(FileOpenOperationArgs))
+}
+
+// MonitorFileOpen starts and finishes a file open operation for the given
+// path. A call to the WAF is made to check the path against the request's
+// addresses and a *BlockedFileOpenError is returned if the file must not be
+// opened. Files opened outside of a monitored HTTP or gRPC handler are ignored,
+// so the return value is nil in that case.
+func MonitorFileOpen(ctx context.Context, path string) error {
+	if parent, ok := ctx.Value(listener.ContextKey{}).(dyngo.Operation); ok {
+		return ExecuteFileOpenOperation(parent, FileOpenOperationArgs{Path: path})
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorFileOpen(t *testing.T) {
+	const traversalPath = "/var/www/../../../etc/passwd"
+
+	type (
+		rootArgs struct{}
+		rootRes  struct{}
+	)
+	newContext := func(t *testing.T) context.Context {
+		root := dyngo.NewOperation(nil)
+		dyngo.StartOperation(root, rootArgs{})
+		t.Cleanup(func() { dyngo.FinishOperation(root, rootRes{}) })
+		root.On(sharedsec.OnFileOpenOperationStart(func(op *sharedsec.FileOpenOperation, args sharedsec.FileOpenOperationArgs) {
+			if args.Path == traversalPath {
+				op.EmitData(&sharedsec.BlockedFileOpenError{Path: args.Path})
+			}
+		}))
+		return context.WithValue(context.Background(), listener.ContextKey{}, root)
+	}
+
+	t.Run("blocked", func(t *testing.T) {
+		err := sharedsec.MonitorFileOpen(newContext(t), traversalPath)
+		var blocked *sharedsec.BlockedFileOpenError
+		require.True(t, errors.As(err, &blocked))
+		require.Equal(t, traversalPath, blocked.Path)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorFileOpen(newContext(t), "/var/www/index.html"))
+	})
+
+	t.Run("unmonitored", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorFileOpen(context.Background(), traversalPath))
+	})
+}
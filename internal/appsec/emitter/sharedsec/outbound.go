@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+)
+
+type (
+	// OutboundRequestOperation type representing an outgoing HTTP or gRPC
+	// request performed while handling a monitored request. It gets both
+	// created and destroyed in a single call to ExecuteOutboundRequestOperation.
+	OutboundRequestOperation struct {
+		dyngo.Operation
+	}
+	// OutboundRequestOperationArgs is the outbound request operation arguments.
+	OutboundRequestOperationArgs struct {
+		// URL corresponds to the address `server.io.net.url`.
+		URL string
+	}
+	// OutboundRequestOperationRes is the outbound request operation results.
+	OutboundRequestOperationRes struct{}
+
+	// OnOutboundRequestOperationStart function type, called when an outbound
+	// request operation starts.
+	OnOutboundRequestOperationStart func(operation *OutboundRequestOperation, args OutboundRequestOperationArgs)
+
+	// BlockedOutboundRequestError is the error returned when an outbound
+	// request is blocked, for instance because its URL was built out of user
+	// input to perform a Server-Side Request Forgery (SSRF).
+	BlockedOutboundRequestError struct {
+		URL string
+	}
+)
+
+// Error implements the error interface.
+func (e *BlockedOutboundRequestError) Error() string {
+	return "appsec: outbound request to " + e.URL + " blocked"
+}
+
+var outboundRequestOperationArgsType = reflect.TypeOf((*OutboundRequestOperationArgs)(nil)).Elem()
+
+// ExecuteOutboundRequestOperation starts and finishes the outbound request
+// operation by emitting a dyngo start and finish events. An error is returned
+// if the outbound request must be blocked.
+func ExecuteOutboundRequestOperation(parent dyngo.Operation, args OutboundRequestOperationArgs) error {
+	var err error
+	op := &OutboundRequestOperation{Operation: dyngo.NewOperation(parent)}
+	OnErrorData(op, func(e *BlockedOutboundRequestError) {
+		err = e
+	})
+	dyngo.StartOperation(op, args)
+	dyngo.FinishOperation(op, OutboundRequestOperationRes{})
+	return err
+}
+
+// ListenedType returns the type a OnOutboundRequestOperationStart event
+// listener listens to, which is the OutboundRequestOperationArgs type.
+func (OnOutboundRequestOperationStart) ListenedType() reflect.Type {
+	return outboundRequestOperationArgsType
+}
+
+// Call the underlying event listener function by performing the type-assertion
+// on v whose type is the one returned by ListenedType().
+func (f OnOutboundRequestOperationStart) Call(op dyngo.Operation, v interface{}) {
+	f(op.(*OutboundRequestOperation), v.(OutboundRequestOperationArgs))
+}
+
+// MonitorOutboundRequest starts and finishes an outbound request operation
+// for the given destination URL. A call to the WAF is made to check the URL and
+// a *BlockedOutboundRequestError is returned if the request must not be
+// performed. Outbound requests performed outside of a monitored HTTP or gRPC
+// handler are ignored, so the return value is nil in that case.
+func MonitorOutboundRequest(ctx context.Context, url string) error {
+	if parent, ok := ctx.Value(listener.ContextKey{}).(dyngo.Operation); ok {
+		return ExecuteOutboundRequestOperation(parent, OutboundRequestOperationArgs{URL: url})
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorOutboundRequest(t *testing.T) {
+	const blockedURL = "http://169.254.169.254/latest/meta-data"
+
+	type (
+		rootArgs struct{}
+		rootRes  struct{}
+	)
+	newContext := func(t *testing.T) context.Context {
+		root := dyngo.NewOperation(nil)
+		dyngo.StartOperation(root, rootArgs{})
+		t.Cleanup(func() { dyngo.FinishOperation(root, rootRes{}) })
+		root.On(sharedsec.OnOutboundRequestOperationStart(func(op *sharedsec.OutboundRequestOperation, args sharedsec.OutboundRequestOperationArgs) {
+			if args.URL == blockedURL {
+				op.EmitData(&sharedsec.BlockedOutboundRequestError{URL: args.URL})
+			}
+		}))
+		return context.WithValue(context.Background(), listener.ContextKey{}, root)
+	}
+
+	t.Run("blocked", func(t *testing.T) {
+		err := sharedsec.MonitorOutboundRequest(newContext(t), blockedURL)
+		var blocked *sharedsec.BlockedOutboundRequestError
+		require.True(t, errors.As(err, &blocked))
+		require.Equal(t, blockedURL, blocked.URL)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorOutboundRequest(newContext(t), "https://example.com/"))
+	})
+
+	t.Run("unmonitored", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorOutboundRequest(context.Background(), blockedURL))
+	})
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+)
+
+type (
+	// SQLOperation type representing a SQL query about to be run while
+	// handling a monitored request. It gets both created and destroyed in a
+	// single call to ExecuteSQLOperation.
+	SQLOperation struct {
+		dyngo.Operation
+	}
+	// SQLOperationArgs is the SQL operation arguments.
+	SQLOperationArgs struct {
+		// Query corresponds to the address `server.db.statement`.
+		Query string
+		// System corresponds to the address `server.db.system`, such as
+		// mysql or postgresql.
+		System string
+	}
+	// SQLOperationRes is the SQL operation results.
+	SQLOperationRes struct{}
+
+	// OnSQLOperationStart function type, called when a SQL operation starts.
+	OnSQLOperationStart func(operation *SQLOperation, args SQLOperationArgs)
+
+	// BlockedSQLQueryError is the error returned when a SQL query is blocked,
+	// for instance because user input was injected into it.
+	BlockedSQLQueryError struct {
+		Query string
+	}
+)
+
+// Error implements the error interface.
+func (e *BlockedSQLQueryError) Error() string {
+	return "appsec: sql query blocked"
+}
+
+var sqlOperationArgsType = reflect.TypeOf((*SQLOperationArgs)(nil)).Elem()
+
+// ExecuteSQLOperation starts and finishes the SQL operation by emitting a dyngo
+// start and finish events. An error is returned if the query must be blocked.
+func ExecuteSQLOperation(parent dyngo.Operation, args SQLOperationArgs) error {
+	var err error
+	op := &SQLOperation{Operation: dyngo.NewOperation(parent)}
+	OnErrorData(op, func(e *BlockedSQLQueryError) {
+		err = e
+	})
+	dyngo.StartOperation(op, args)
+	dyngo.FinishOperation(op, SQLOperationRes{})
+	return err
+}
+
+// ListenedType returns the type a OnSQLOperationStart event listener listens
+// to, which is the SQLOperationArgs type.
+func (OnSQLOperationStart) ListenedType() reflect.Type { return sqlOperationArgsType }
+
+// Call the underlying event listener function by performing the type-assertion
+// on v whose type is the one returned by ListenedType().
+func (f OnSQLOperationStart) Call(op dyngo.Operation, v interface{}) {
+	f(op.(*SQLOperation), v.(SQLOperationArgs))
+}
+
+// MonitorSQLQuery starts and finishes a SQL operation for the given query and
+// database system. A call to the WAF is made to check the query against the
+// request's addresses and a *BlockedSQLQueryError is returned if the query
+// must not be run. Queries run outside of a monitored HTTP or gRPC handler are
+// ignored, so the return value is nil in that case.
+func MonitorSQLQuery(ctx context.Context, query, system string) error {
+	if parent, ok := ctx.Value(listener.ContextKey{}).(dyngo.Operation); ok {
+		return ExecuteSQLOperation(parent, SQLOperationArgs{Query: query, System: system})
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package sharedsec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/listener"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorSQLQuery(t *testing.T) {
+	const injectedQuery = "SELECT * FROM users WHERE name = '' OR 1=1 --'"
+
+	type (
+		rootArgs struct{}
+		rootRes  struct{}
+	)
+	newContext := func(t *testing.T) context.Context {
+		root := dyngo.NewOperation(nil)
+		dyngo.StartOperation(root, rootArgs{})
+		t.Cleanup(func() { dyngo.FinishOperation(root, rootRes{}) })
+		root.On(sharedsec.OnSQLOperationStart(func(op *sharedsec.SQLOperation, args sharedsec.SQLOperationArgs) {
+			require.Equal(t, "postgresql", args.System)
+			if args.Query == injectedQuery {
+				op.EmitData(&sharedsec.BlockedSQLQueryError{Query: args.Query})
+			}
+		}))
+		return context.WithValue(context.Background(), listener.ContextKey{}, root)
+	}
+
+	t.Run("blocked", func(t *testing.T) {
+		err := sharedsec.MonitorSQLQuery(newContext(t), injectedQuery, "postgresql")
+		var blocked *sharedsec.BlockedSQLQueryError
+		require.True(t, errors.As(err, &blocked))
+		require.Equal(t, injectedQuery, blocked.Query)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorSQLQuery(newContext(t), "SELECT * FROM users WHERE name = 'bob'", "postgresql"))
+	})
+
+	t.Run("unmonitored", func(t *testing.T) {
+		require.NoError(t, sharedsec.MonitorSQLQuery(context.Background(), injectedQuery, "postgresql"))
+	})
+}
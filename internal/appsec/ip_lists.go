@@ -0,0 +1,281 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	rc "github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+const (
+	// blockedIPsDataID is the ID of the rules data of the IP addresses blocked by the default rules
+	blockedIPsDataID = "blocked_ips"
+	// blockedIPsDataType is the type of the rules data of the IP addresses blocked by the default rules
+	blockedIPsDataType = "ip_with_expiration"
+)
+
+// ipLists are the IP denylist and allowlist managed locally with DenyIP, AllowIP and RemoveIP. They are kept
+// across AppSec restarts and applied to the security rules whenever the WAF handle is swapped. A zero expiration
+// time means the entry never expires.
+type ipLists struct {
+	mu      sync.Mutex
+	denied  map[netip.Prefix]time.Time
+	allowed map[netip.Prefix]time.Time
+	// expiryTimer applies the lists again once the next allowlist entry expires, so that the IP addresses it
+	// allowed get blocked again by the remote config rules data. Denylist expirations are handled by the WAF.
+	expiryTimer *time.Timer
+}
+
+var localIPLists = newIPLists()
+
+func newIPLists() *ipLists {
+	return &ipLists{
+		denied:  map[netip.Prefix]time.Time{},
+		allowed: map[netip.Prefix]time.Time{},
+	}
+}
+
+// DenyIP adds the given IP address or CIDR range to the local denylist for the given duration, or until it is
+// removed when ttl is zero, and applies it to the running WAF.
+func DenyIP(ip string, ttl time.Duration) error {
+	p, exp, err := parseIPListEntry(ip, ttl)
+	if err != nil {
+		return err
+	}
+	localIPLists.mu.Lock()
+	localIPLists.denied[p] = exp
+	localIPLists.mu.Unlock()
+	refreshIPLists()
+	return nil
+}
+
+// AllowIP adds the given IP address or CIDR range to the local allowlist for the given duration, or until it is
+// removed when ttl is zero, and applies it to the running WAF.
+func AllowIP(ip string, ttl time.Duration) error {
+	p, exp, err := parseIPListEntry(ip, ttl)
+	if err != nil {
+		return err
+	}
+	localIPLists.mu.Lock()
+	localIPLists.allowed[p] = exp
+	localIPLists.mu.Unlock()
+	refreshIPLists()
+	return nil
+}
+
+// RemoveIP removes the given IP address or CIDR range from both the local denylist and allowlist, and applies it
+// to the running WAF.
+func RemoveIP(ip string) error {
+	p, _, err := parseIPListEntry(ip, 0)
+	if err != nil {
+		return err
+	}
+	localIPLists.mu.Lock()
+	delete(localIPLists.denied, p)
+	delete(localIPLists.allowed, p)
+	localIPLists.mu.Unlock()
+	refreshIPLists()
+	return nil
+}
+
+// IPAllowed returns true when the given IP address is in the local allowlist.
+func IPAllowed(ip netip.Addr) bool {
+	return localIPLists.isAllowed(ip, time.Now())
+}
+
+// refreshIPLists swaps the WAF handle of the running AppSec so that the current state of the local IP lists gets
+// applied to its security rules.
+func refreshIPLists() {
+	mu.RLock()
+	defer mu.RUnlock()
+	a := activeAppSec
+	if a == nil || !a.started {
+		return
+	}
+	a.rulesMu.Lock()
+	defer a.rulesMu.Unlock()
+	if err := a.swapWAF(a.cfg.RulesManager.Latest); err != nil {
+		log.Error("appsec: could not apply the local IP lists: %v", err)
+	}
+}
+
+// parseIPListEntry parses the given IP address or CIDR range, and returns it along with the expiration time of the
+// given ttl.
+func parseIPListEntry(ip string, ttl time.Duration) (p netip.Prefix, exp time.Time, err error) {
+	if ttl < 0 {
+		return p, exp, errors.New("negative ttl")
+	}
+	if strings.Contains(ip, "/") {
+		p, err = netip.ParsePrefix(ip)
+		p = p.Masked()
+	} else {
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(ip)
+		addr = addr.Unmap()
+		p = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	if err != nil {
+		return p, exp, fmt.Errorf("invalid IP address or CIDR range `%s`: %w", ip, err)
+	}
+	if ttl > 0 {
+		exp = time.Now().Add(ttl)
+	}
+	return p, exp, nil
+}
+
+// ipListEntryValue returns the rules data value of the given IP list entry.
+func ipListEntryValue(p netip.Prefix) string {
+	if p.IsSingleIP() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+func expired(exp, now time.Time) bool {
+	return !exp.IsZero() && !now.Before(exp)
+}
+
+// isAllowed returns true when the given IP address is in a non-expired allowlist entry.
+func (l *ipLists) isAllowed(ip netip.Addr, now time.Time) bool {
+	if !ip.IsValid() {
+		return false
+	}
+	ip = ip.Unmap()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for p, exp := range l.allowed {
+		if p.Contains(ip) && !expired(exp, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractAllowed returns the CIDR ranges covering the given IP range without the IP addresses of the non-expired
+// allowlist entries. The range is split in halves until they are either entirely allowed or not allowed at all.
+func (l *ipLists) subtractAllowed(p netip.Prefix, now time.Time) []netip.Prefix {
+	overlaps := false
+	for allowed, exp := range l.allowed {
+		if expired(exp, now) || !allowed.Overlaps(p) {
+			continue
+		}
+		if allowed.Bits() <= p.Bits() {
+			// The whole range is allowed
+			return nil
+		}
+		overlaps = true
+	}
+	if !overlaps {
+		return []netip.Prefix{p}
+	}
+	lo, hi := splitPrefix(p)
+	return append(l.subtractAllowed(lo, now), l.subtractAllowed(hi, now)...)
+}
+
+// splitPrefix returns the two halves of the given masked IP range, which must not be a single IP address.
+func splitPrefix(p netip.Prefix) (lo, hi netip.Prefix) {
+	bits := p.Bits()
+	b := p.Addr().AsSlice()
+	b[bits/8] |= 0x80 >> (bits % 8)
+	addr, _ := netip.AddrFromSlice(b)
+	return netip.PrefixFrom(p.Addr(), bits+1), netip.PrefixFrom(addr, bits+1)
+}
+
+// apply returns the given security rules with their blocked IPs rules data merged with the local denylist, and
+// without the IP addresses of the local allowlist. Expired entries are removed from the lists.
+func (l *ipLists) apply(rules config.RulesFragment) config.RulesFragment {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.removeExpired(now)
+	l.scheduleExpiry(now)
+	if len(l.denied) == 0 && len(l.allowed) == 0 {
+		return rules
+	}
+
+	blockedIPs := config.RuleDataEntry{ID: blockedIPsDataID, Type: blockedIPsDataType}
+	rulesData := make([]config.RuleDataEntry, 0, len(rules.RulesData)+1)
+	for _, data := range rules.RulesData {
+		if data.ID == blockedIPsDataID && data.Type == blockedIPsDataType {
+			blockedIPs.Data = mergeRulesDataEntries(blockedIPs.Data, data.Data)
+			continue
+		}
+		rulesData = append(rulesData, data)
+	}
+
+	denied := make([]rc.ASMDataRuleDataEntry, 0, len(l.denied))
+	for p, exp := range l.denied {
+		entry := rc.ASMDataRuleDataEntry{Value: ipListEntryValue(p)}
+		if !exp.IsZero() {
+			entry.Expiration = exp.Unix()
+		}
+		denied = append(denied, entry)
+	}
+	blockedIPs.Data = mergeRulesDataEntries(blockedIPs.Data, denied)
+
+	// Remove the allowed IP addresses, including the ones blocked through remote config, by splitting the denied
+	// ranges around them
+	entries := make([]rc.ASMDataRuleDataEntry, 0, len(blockedIPs.Data))
+	for _, entry := range blockedIPs.Data {
+		p, _, err := parseIPListEntry(entry.Value, 0)
+		if err != nil {
+			entries = append(entries, entry)
+			continue
+		}
+		ranges := l.subtractAllowed(p, now)
+		if len(ranges) == 1 && ranges[0] == p {
+			entries = append(entries, entry)
+			continue
+		}
+		for _, r := range ranges {
+			entries = append(entries, rc.ASMDataRuleDataEntry{Value: ipListEntryValue(r), Expiration: entry.Expiration})
+		}
+	}
+	blockedIPs.Data = entries
+
+	if len(blockedIPs.Data) > 0 {
+		rulesData = append(rulesData, blockedIPs)
+	}
+	rules.RulesData = rulesData
+	return rules
+}
+
+func (l *ipLists) removeExpired(now time.Time) {
+	for _, list := range []map[netip.Prefix]time.Time{l.denied, l.allowed} {
+		for p, exp := range list {
+			if expired(exp, now) {
+				delete(list, p)
+			}
+		}
+	}
+}
+
+// scheduleExpiry schedules the refresh of the IP lists at the next allowlist entry expiration.
+func (l *ipLists) scheduleExpiry(now time.Time) {
+	if l.expiryTimer != nil {
+		l.expiryTimer.Stop()
+		l.expiryTimer = nil
+	}
+	var next time.Time
+	for _, exp := range l.allowed {
+		if !exp.IsZero() && (next.IsZero() || exp.Before(next)) {
+			next = exp
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+	l.expiryTimer = time.AfterFunc(next.Sub(now), refreshIPLists)
+}
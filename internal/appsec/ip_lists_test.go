@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	rc "github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPListEntry(t *testing.T) {
+	for _, tc := range []struct {
+		ip       string
+		ttl      time.Duration
+		expected string
+		err      bool
+	}{
+		{ip: "1.2.3.4", expected: "1.2.3.4"},
+		{ip: "::ffff:1.2.3.4", expected: "1.2.3.4"},
+		{ip: "2001:db8::1", expected: "2001:db8::1"},
+		{ip: "192.168.1.1/16", expected: "192.168.0.0/16"},
+		{ip: "1.2.3.4/32", expected: "1.2.3.4"},
+		{ip: "1.2.3.4", ttl: time.Minute, expected: "1.2.3.4"},
+		{ip: "1.2.3.4", ttl: -time.Minute, err: true},
+		{ip: "not an ip", err: true},
+		{ip: "1.2.3.4/64", err: true},
+	} {
+		t.Run(tc.ip, func(t *testing.T) {
+			p, exp, err := parseIPListEntry(tc.ip, tc.ttl)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ipListEntryValue(p))
+			require.Equal(t, tc.ttl == 0, exp.IsZero())
+		})
+	}
+}
+
+func TestIPListsApply(t *testing.T) {
+	now := time.Now()
+	rules := config.RulesFragment{
+		RulesData: []config.RuleDataEntry{
+			{
+				ID:   blockedIPsDataID,
+				Type: blockedIPsDataType,
+				Data: []rc.ASMDataRuleDataEntry{{Value: "1.1.1.1"}, {Value: "10.0.0.1", Expiration: now.Add(time.Hour).Unix()}},
+			},
+			{
+				ID:   "blocked_users",
+				Type: "data_with_expiration",
+				Data: []rc.ASMDataRuleDataEntry{{Value: "user"}},
+			},
+		},
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		l := newIPLists()
+		require.Equal(t, rules, l.apply(rules))
+	})
+
+	t.Run("denylist", func(t *testing.T) {
+		l := newIPLists()
+		l.denied[netip.MustParsePrefix("2.2.2.2/32")] = time.Time{}
+		l.denied[netip.MustParsePrefix("3.3.0.0/16")] = now.Add(time.Minute)
+		l.denied[netip.MustParsePrefix("4.4.4.4/32")] = now.Add(-time.Minute)
+
+		applied := l.apply(rules)
+		require.Len(t, applied.RulesData, 2)
+		require.Equal(t, rules.RulesData[1], applied.RulesData[0])
+		require.Equal(t, blockedIPsDataID, applied.RulesData[1].ID)
+		require.ElementsMatch(t, []rc.ASMDataRuleDataEntry{
+			{Value: "1.1.1.1"},
+			{Value: "10.0.0.1", Expiration: now.Add(time.Hour).Unix()},
+			{Value: "2.2.2.2"},
+			{Value: "3.3.0.0/16", Expiration: now.Add(time.Minute).Unix()},
+		}, applied.RulesData[1].Data)
+		// Expired entries are removed
+		require.Len(t, l.denied, 2)
+		// The given rules are left unchanged
+		require.Len(t, rules.RulesData[0].Data, 2)
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		l := newIPLists()
+		l.denied[netip.MustParsePrefix("2.2.2.2/32")] = time.Time{}
+		l.allowed[netip.MustParsePrefix("10.0.0.0/8")] = time.Time{}
+		l.allowed[netip.MustParsePrefix("2.2.2.2/32")] = now.Add(time.Hour)
+
+		applied := l.apply(rules)
+		require.NotNil(t, l.expiryTimer)
+		l.expiryTimer.Stop()
+		require.ElementsMatch(t, []rc.ASMDataRuleDataEntry{{Value: "1.1.1.1"}}, applied.RulesData[1].Data)
+
+		require.True(t, l.isAllowed(netip.MustParseAddr("10.1.2.3"), now))
+		require.True(t, l.isAllowed(netip.MustParseAddr("::ffff:10.1.2.3"), now))
+		require.True(t, l.isAllowed(netip.MustParseAddr("2.2.2.2"), now))
+		require.False(t, l.isAllowed(netip.MustParseAddr("2.2.2.2"), now.Add(2*time.Hour)))
+		require.False(t, l.isAllowed(netip.MustParseAddr("1.1.1.1"), now))
+		require.False(t, l.isAllowed(netip.Addr{}, now))
+	})
+}
+
+func TestIPListsAllowedInDeniedRange(t *testing.T) {
+	now := time.Now()
+	exp := now.Add(time.Hour).Unix()
+	rules := config.RulesFragment{
+		RulesData: []config.RuleDataEntry{{
+			ID:   blockedIPsDataID,
+			Type: blockedIPsDataType,
+			Data: []rc.ASMDataRuleDataEntry{{Value: "10.0.0.0/8", Expiration: exp}},
+		}},
+	}
+	l := newIPLists()
+	l.denied[netip.MustParsePrefix("192.168.0.0/30")] = time.Time{}
+	l.allowed[netip.MustParsePrefix("10.1.2.3/32")] = time.Time{}
+	l.allowed[netip.MustParsePrefix("192.168.0.0/31")] = time.Time{}
+
+	applied := l.apply(rules)
+	require.Len(t, applied.RulesData, 1)
+	data := applied.RulesData[0].Data
+	// 10.0.0.0/8 is split into 24 ranges around 10.1.2.3, and 192.168.0.0/30 into its half not allowed
+	require.Len(t, data, 25)
+	require.Contains(t, data, rc.ASMDataRuleDataEntry{Value: "192.168.0.2/31"})
+	require.Contains(t, data, rc.ASMDataRuleDataEntry{Value: "10.1.2.2", Expiration: exp})
+	require.Contains(t, data, rc.ASMDataRuleDataEntry{Value: "10.128.0.0/9", Expiration: exp})
+
+	var blocked []netip.Prefix
+	for _, entry := range data {
+		p, _, err := parseIPListEntry(entry.Value, 0)
+		require.NoError(t, err)
+		blocked = append(blocked, p)
+	}
+	isBlocked := func(ip string) bool {
+		for _, p := range blocked {
+			if p.Contains(netip.MustParseAddr(ip)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, ip := range []string{"10.1.2.3", "192.168.0.0", "192.168.0.1"} {
+		require.False(t, isBlocked(ip), ip)
+	}
+	for _, ip := range []string{"10.0.0.0", "10.1.2.2", "10.1.2.4", "10.255.255.255", "192.168.0.2", "192.168.0.3"} {
+		require.True(t, isBlocked(ip), ip)
+	}
+}
+
+func TestIPListsAPI(t *testing.T) {
+	defer func() { localIPLists = newIPLists() }()
+
+	require.NoError(t, DenyIP("1.2.3.4", time.Minute))
+	require.NoError(t, AllowIP("5.6.7.0/24", 0))
+	require.Error(t, DenyIP("1.2.3", 0))
+	require.Error(t, AllowIP("5.6.7.8", -time.Second))
+	require.True(t, IPAllowed(netip.MustParseAddr("5.6.7.8")))
+
+	require.NoError(t, RemoveIP("1.2.3.4"))
+	require.NoError(t, RemoveIP("5.6.7.0/24"))
+	require.Empty(t, localIPLists.denied)
+	require.False(t, IPAllowed(netip.MustParseAddr("5.6.7.8")))
+}
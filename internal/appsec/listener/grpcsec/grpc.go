@@ -29,6 +29,10 @@ const (
 	GRPCServerRequestMetadata = "grpc.server.request.metadata"
 	HTTPClientIPAddr          = httpsec.HTTPClientIPAddr
 	UserIDAddr                = httpsec.UserIDAddr
+	ServerIONetURLAddr        = httpsec.ServerIONetURLAddr
+	ServerDBStatementAddr     = httpsec.ServerDBStatementAddr
+	ServerDBSystemAddr        = httpsec.ServerDBSystemAddr
+	ServerIOFSFileAddr        = httpsec.ServerIOFSFileAddr
 )
 
 // List of gRPC rule addresses currently supported by the WAF
@@ -37,6 +41,10 @@ var supportedAddresses = map[string]struct{}{
 	GRPCServerRequestMetadata: {},
 	HTTPClientIPAddr:          {},
 	UserIDAddr:                {},
+	ServerIONetURLAddr:        {},
+	ServerDBStatementAddr:     {},
+	ServerDBSystemAddr:        {},
+	ServerIOFSFileAddr:        {},
 }
 
 func SupportedAddressCount() int {
@@ -95,6 +103,48 @@ func NewWAFEventListener(handle *waf.Handle, actions sharedsec.Actions, addresse
 			}
 		}))
 
+		if _, ok := addresses[ServerIONetURLAddr]; ok {
+			// OnOutboundRequestOperationStart happens when the handler performs an outgoing HTTP or gRPC request.
+			// The destination URL is checked against the SSRF rules and the request is blocked by returning an
+			// error to the caller.
+			op.On(sharedsec.OnOutboundRequestOperationStart(func(outboundOp *sharedsec.OutboundRequestOperation, args sharedsec.OutboundRequestOperationArgs) {
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: map[string]any{ServerIONetURLAddr: args.URL}}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(outboundOp, actions, wafResult.Actions, &sharedsec.BlockedOutboundRequestError{URL: args.URL})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a suspicious outbound request: %s", args.URL)
+				}
+			}))
+		}
+
+		if _, ok := addresses[ServerDBStatementAddr]; ok {
+			// OnSQLOperationStart happens when the handler is about to run a SQL query. The query is checked along
+			// with the request addresses for injected fragments and is blocked by returning an error to the caller.
+			op.On(sharedsec.OnSQLOperationStart(func(sqlOp *sharedsec.SQLOperation, args sharedsec.SQLOperationArgs) {
+				values := map[string]any{ServerDBStatementAddr: args.Query, ServerDBSystemAddr: args.System}
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: values}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(sqlOp, actions, wafResult.Actions, &sharedsec.BlockedSQLQueryError{Query: args.Query})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a SQL injection in query: %s", args.Query)
+				}
+			}))
+		}
+
+		if _, ok := addresses[ServerIOFSFileAddr]; ok {
+			// OnFileOpenOperationStart happens when the handler is about to open a file. The path is checked along
+			// with the request addresses for path traversals and the file opening is blocked by returning an error
+			// to the caller.
+			op.On(sharedsec.OnFileOpenOperationStart(func(fileOp *sharedsec.FileOpenOperation, args sharedsec.FileOpenOperationArgs) {
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: map[string]any{ServerIOFSFileAddr: args.Path}}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(fileOp, actions, wafResult.Actions, &sharedsec.BlockedFileOpenError{Path: args.Path})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a suspicious file path: %s", args.Path)
+				}
+			}))
+		}
+
 		// The same address is used for gRPC and http when it comes to client ip
 		values := map[string]any{}
 		for addr := range addresses {
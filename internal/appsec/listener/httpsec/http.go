@@ -34,8 +34,13 @@ const (
 	ServerRequestBodyAddr              = "server.request.body"
 	ServerResponseStatusAddr           = "server.response.status"
 	ServerResponseHeadersNoCookiesAddr = "server.response.headers.no_cookies"
+	ServerResponseBodyAddr             = "server.response.body"
 	HTTPClientIPAddr                   = "http.client_ip"
 	UserIDAddr                         = "usr.id"
+	ServerIONetURLAddr                 = "server.io.net.url"
+	ServerDBStatementAddr              = "server.db.statement"
+	ServerDBSystemAddr                 = "server.db.system"
+	ServerIOFSFileAddr                 = "server.io.fs.file"
 )
 
 // List of HTTP rule addresses currently supported by the WAF
@@ -49,8 +54,13 @@ var supportedAddresses = map[string]struct{}{
 	ServerRequestBodyAddr:              {},
 	ServerResponseStatusAddr:           {},
 	ServerResponseHeadersNoCookiesAddr: {},
+	ServerResponseBodyAddr:             {},
 	HTTPClientIPAddr:                   {},
 	UserIDAddr:                         {},
+	ServerIONetURLAddr:                 {},
+	ServerDBStatementAddr:              {},
+	ServerDBSystemAddr:                 {},
+	ServerIOFSFileAddr:                 {},
 }
 
 func SupportedAddressCount() int {
@@ -63,7 +73,7 @@ func SupportsAddress(addr string) bool {
 }
 
 // NewWAFEventListener returns the WAF event listener to register in order to enable it.
-func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses map[string]struct{}, timeout time.Duration, apiSecCfg *internal.APISecConfig, limiter limiter.Limiter) dyngo.EventListener {
+func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses map[string]struct{}, timeout time.Duration, apiSecCfg *internal.APISecConfig, maxResponseBodySize int, limiter limiter.Limiter) dyngo.EventListener {
 	var monitorRulesOnce sync.Once // per instantiation
 	// TODO: port wafDiags to telemetry metrics and logs instead of span tags (ultimately removing them from here hopefully)
 	wafDiags := handle.Diagnostics()
@@ -90,6 +100,49 @@ func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses
 			}))
 		}
 
+		if _, ok := addresses[ServerIONetURLAddr]; ok {
+			// OnOutboundRequestOperationStart happens when the handler performs an outgoing HTTP or gRPC request. The
+			// destination URL is checked against the SSRF rules and the request is blocked by returning an error to
+			// the caller, in addition to the blocking response of the handler.
+			op.On(emitter.OnOutboundRequestOperationStart(func(operation *emitter.OutboundRequestOperation, args emitter.OutboundRequestOperationArgs) {
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: map[string]any{ServerIONetURLAddr: args.URL}}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(operation, actions, wafResult.Actions, &emitter.BlockedOutboundRequestError{URL: args.URL})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a suspicious outbound request: %s", args.URL)
+				}
+			}))
+		}
+
+		if _, ok := addresses[ServerDBStatementAddr]; ok {
+			// OnSQLOperationStart happens when the handler is about to run a SQL query. The query is checked along
+			// with the request addresses for injected fragments and is blocked by returning an error to the caller,
+			// in addition to the blocking response of the handler.
+			op.On(emitter.OnSQLOperationStart(func(operation *emitter.SQLOperation, args emitter.SQLOperationArgs) {
+				values := map[string]any{ServerDBStatementAddr: args.Query, ServerDBSystemAddr: args.System}
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: values}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(operation, actions, wafResult.Actions, &emitter.BlockedSQLQueryError{Query: args.Query})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a SQL injection in query: %s", args.Query)
+				}
+			}))
+		}
+
+		if _, ok := addresses[ServerIOFSFileAddr]; ok {
+			// OnFileOpenOperationStart happens when the handler is about to open a file. The path is checked along
+			// with the request addresses for path traversals and the file opening is blocked by returning an error to
+			// the caller, in addition to the blocking response of the handler.
+			op.On(emitter.OnFileOpenOperationStart(func(operation *emitter.FileOpenOperation, args emitter.FileOpenOperationArgs) {
+				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Ephemeral: map[string]any{ServerIOFSFileAddr: args.Path}}, timeout)
+				if wafResult.HasActions() || wafResult.HasEvents() {
+					listener.ProcessBlockingActions(operation, actions, wafResult.Actions, &emitter.BlockedFileOpenError{Path: args.Path})
+					listener.AddSecurityEvents(op, limiter, wafResult.Events)
+					log.Debug("appsec: WAF detected a suspicious file path: %s", args.Path)
+				}
+			}))
+		}
+
 		values := make(map[string]any, 8)
 		for addr := range addresses {
 			switch addr {
@@ -123,12 +176,15 @@ func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses
 			// This address will be passed as persistent. The WAF will keep it in store and trigger schema extraction
 			// for each run.
 			values["waf.context.processor"] = map[string]any{"extract-schema": true}
+			// Ask for the JSON response body so that its schema gets extracted too
+			op.MonitorResponseBody(maxResponseBodySize)
+		} else if _, ok := addresses[ServerResponseBodyAddr]; ok {
+			// Ask for the JSON response body to be buffered for the rules inspecting it, such as data leak ones
+			op.MonitorResponseBody(maxResponseBodySize)
 		}
 
 		wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Persistent: values}, timeout)
-		for tag, value := range wafResult.Derivatives {
-			op.AddSerializableTag(tag, value)
-		}
+		addDerivatives(op, wafResult.Derivatives)
 		if wafResult.HasActions() || wafResult.HasEvents() {
 			interrupt := listener.ProcessActions(op, actions, wafResult.Actions)
 			listener.AddSecurityEvents(op, limiter, wafResult.Events)
@@ -142,9 +198,7 @@ func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses
 		if _, ok := addresses[ServerRequestBodyAddr]; ok {
 			op.On(httpsec.OnSDKBodyOperationStart(func(sdkBodyOp *httpsec.SDKBodyOperation, args httpsec.SDKBodyOperationArgs) {
 				wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Persistent: map[string]any{ServerRequestBodyAddr: args.Body}}, timeout)
-				for tag, value := range wafResult.Derivatives {
-					op.AddSerializableTag(tag, value)
-				}
+				addDerivatives(op, wafResult.Derivatives)
 				if wafResult.HasActions() || wafResult.HasEvents() {
 					listener.ProcessHTTPSDKAction(sdkBodyOp, actions, wafResult.Actions)
 					listener.AddSecurityEvents(op, limiter, wafResult.Events)
@@ -166,9 +220,18 @@ func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses
 				values[ServerResponseHeadersNoCookiesAddr] = res.Headers
 			}
 
-			// Run the WAF, ignoring the returned actions - if any - since blocking after the request handler's
-			// response is not supported at the moment.
+			// The response body is only provided when it was buffered, for the rules inspecting it or for the API
+			// Security schema extraction
+			if res.Body != nil {
+				values[ServerResponseBodyAddr] = res.Body
+			}
+
+			// Run the WAF and send the returned actions - if any - to the handler operation, which applies them when
+			// the response is still buffered and can be replaced.
 			wafResult := listener.RunWAF(wafCtx, waf.RunAddressData{Persistent: values}, timeout)
+			if wafResult.HasActions() {
+				listener.ProcessActions(op, actions, wafResult.Actions)
+			}
 
 			// Add WAF metrics.
 			overallRuntimeNs, internalRuntimeNs := wafCtx.TotalRuntime()
@@ -185,9 +248,7 @@ func NewWAFEventListener(handle *waf.Handle, actions emitter.Actions, addresses
 				log.Debug("appsec: attack detected by the waf")
 				listener.AddSecurityEvents(op, limiter, wafResult.Events)
 			}
-			for tag, value := range wafResult.Derivatives {
-				op.AddSerializableTag(tag, value)
-			}
+			addDerivatives(op, wafResult.Derivatives)
 		}))
 	})
 }
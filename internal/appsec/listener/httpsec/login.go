@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+// NewLoginEventListener returns the event listener detecting the outcome of
+// the login requests according to the given login rules, and setting the
+// corresponding user login success or failure events on the handler
+// operation. It doesn't depend on the security rules.
+func NewLoginEventListener(rules []config.LoginRule, mode config.UserEventsMode) dyngo.EventListener {
+	return httpsec.OnHandlerOperationStart(func(op *httpsec.Operation, args httpsec.HandlerOperationArgs) {
+		rule := matchLoginRule(rules, args.Method, args.RequestURI)
+		if rule == nil {
+			return
+		}
+		op.On(httpsec.OnHandlerOperationFinish(func(op *httpsec.Operation, res httpsec.HandlerOperationRes) {
+			uid := mode.UserID(rule.UserID(args.Headers, res.Headers))
+			switch {
+			case rule.Success(res.Status, res.Headers):
+				log.Debug("appsec: login success detected on %s %s", args.Method, rule.Path)
+				trace.SetAutoUserLoginSuccessTags(op, uid, string(mode))
+			case rule.Failure(res.Status):
+				log.Debug("appsec: login failure detected on %s %s", args.Method, rule.Path)
+				trace.SetAutoUserLoginFailureTags(op, uid, string(mode))
+			}
+		}))
+	})
+}
+
+// matchLoginRule returns the first login rule matching the request method and
+// the path of its request URI, or nil when none matches.
+func matchLoginRule(rules []config.LoginRule, method, requestURI string) *config.LoginRule {
+	path := requestURI
+	if u, err := url.ParseRequestURI(requestURI); err == nil {
+		path = u.Path
+	} else if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	for i := range rules {
+		if rules[i].Match(method, path) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+	testlib "github.com/nowfred/dd-trace-go/internal/appsec/_testlib"
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginEventListener(t *testing.T) {
+	rules := []config.LoginRule{{Method: "POST", Path: "/login", UserIDHeader: "X-User-Id"}}
+	for _, tc := range []struct {
+		name     string
+		mode     config.UserEventsMode
+		method   string
+		target   string
+		status   int
+		expected map[string]any
+	}{
+		{
+			name:   "success",
+			mode:   config.UserEventsModeExtended,
+			method: "POST",
+			target: "/login?next=/home",
+			status: http.StatusFound,
+			expected: map[string]any{
+				"appsec.events.users.login.success.track":         true,
+				"_dd.appsec.events.users.login.success.auto.mode": "extended",
+				"usr.id":                          "user",
+				"_dd.appsec.user.collection_mode": "extended",
+				ext.SamplingPriority:              ext.PriorityUserKeep,
+			},
+		},
+		{
+			name:   "failure",
+			mode:   config.UserEventsModeSafe,
+			method: "POST",
+			target: "/login",
+			status: http.StatusUnauthorized,
+			expected: map[string]any{
+				"appsec.events.users.login.failure.track":         true,
+				"_dd.appsec.events.users.login.failure.auto.mode": "safe",
+				"appsec.events.users.login.failure.usr.id":        config.UserEventsModeSafe.UserID("user"),
+				ext.SamplingPriority:                              ext.PriorityUserKeep,
+			},
+		},
+		{
+			name:   "other-status",
+			mode:   config.UserEventsModeSafe,
+			method: "POST",
+			target: "/login",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "other-route",
+			mode:   config.UserEventsModeSafe,
+			method: "GET",
+			target: "/login",
+			status: http.StatusOK,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := dyngo.NewRootOperation()
+			root.On(NewLoginEventListener(rules, tc.mode))
+			dyngo.SwapRootOperation(root)
+			defer dyngo.SwapRootOperation(dyngo.NewRootOperation())
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			})
+			span := &testlib.MockSpan{}
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			req.Header.Set("X-User-Id", "user")
+			httpsec.WrapHandler(handler, span, nil).ServeHTTP(&statusRecorder{ResponseRecorder: httptest.NewRecorder()}, req)
+
+			for k, v := range tc.expected {
+				require.Equal(t, v, span.Tags[k], k)
+			}
+			if tc.expected == nil {
+				require.NotContains(t, span.Tags, "usr.id")
+				require.NotContains(t, span.Tags, "appsec.events.users.login.success.track")
+				require.NotContains(t, span.Tags, "appsec.events.users.login.failure.track")
+			}
+		})
+	}
+}
+
+// statusRecorder is an httptest.ResponseRecorder providing the response status
+// code to httpsec.WrapHandler, as the tracing middlewares do.
+type statusRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r *statusRecorder) Status() int {
+	return r.Code
+}
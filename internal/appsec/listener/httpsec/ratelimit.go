@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/DataDog/appsec-internal-go/limiter"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+	emitter "github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
+	listener "github.com/nowfred/dd-trace-go/internal/appsec/listener/sharedsec"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+const (
+	// clientIPRateLimitRuleID is the rule ID of the security events of the rate limited client IPs
+	clientIPRateLimitRuleID = "dd-client-ip-rate-limit"
+	// maxClientIPBuckets is the maximum number of client IPs tracked by the rate limiter at once
+	maxClientIPBuckets = 10_000
+	// grpcResourceExhaustedCode is the gRPC status code of the rate limited requests
+	grpcResourceExhaustedCode = 8
+)
+
+// ClientIPRateLimiter limits the number of requests per second of each client IP with one token bucket per client
+// IP, allowing bursts of up to burst requests.
+type ClientIPRateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[netip.Addr]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewClientIPRateLimiter returns a new rate limiter of rate requests per second and per client IP, allowing bursts
+// of up to burst requests. The burst defaults to the rate when zero.
+func NewClientIPRateLimiter(rate, burst int) *ClientIPRateLimiter {
+	if burst <= 0 {
+		burst = rate
+	}
+	return &ClientIPRateLimiter{
+		rate:    float64(rate),
+		burst:   float64(burst),
+		buckets: make(map[netip.Addr]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow returns true when the given client IP is allowed to send one more request.
+func (l *ClientIPRateLimiter) Allow(ip netip.Addr) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[ip]
+	if !ok {
+		if len(l.buckets) >= maxClientIPBuckets {
+			l.evictFullBuckets(now)
+		}
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	}
+	b.refill(now, l.rate, l.burst)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+}
+
+// evictFullBuckets removes the buckets of the client IPs that had enough time to refill them, which are in the
+// same state as untracked client IPs. All the buckets are removed when none of them is full, so that the memory
+// usage remains bounded.
+func (l *ClientIPRateLimiter) evictFullBuckets(now time.Time) {
+	for ip, b := range l.buckets {
+		if b.refill(now, l.rate, l.burst); b.tokens >= l.burst {
+			delete(l.buckets, ip)
+		}
+	}
+	if len(l.buckets) >= maxClientIPBuckets {
+		l.buckets = make(map[netip.Addr]*tokenBucket)
+	}
+}
+
+// NewClientIPRateLimitEventListener returns the event listener blocking the requests of the client IPs exceeding
+// the given rate limiter with a 429 Too Many Requests response, and adding the corresponding security event to the
+// handler operation. The client IPs for which allowed returns true are never rate limited. It doesn't depend on the
+// security rules.
+func NewClientIPRateLimitEventListener(rl *ClientIPRateLimiter, allowed func(netip.Addr) bool, l limiter.Limiter) dyngo.EventListener {
+	action := emitter.NewBlockRequestAction(http.StatusTooManyRequests, grpcResourceExhaustedCode, "auto")
+	return httpsec.OnHandlerOperationStart(func(op *httpsec.Operation, args httpsec.HandlerOperationArgs) {
+		ip := args.ClientIP
+		if !ip.IsValid() || allowed(ip) || rl.Allow(ip) {
+			return
+		}
+		log.Debug("appsec: client ip %s exceeded its rate limit", ip)
+		op.EmitData(action)
+		listener.AddSecurityEvents(op, l, []any{clientIPRateLimitEvent(ip)})
+	})
+}
+
+// clientIPRateLimitEvent returns the security event of the given rate limited client IP, in the format of the WAF
+// events.
+func clientIPRateLimitEvent(ip netip.Addr) map[string]any {
+	value := ip.String()
+	return map[string]any{
+		"rule": map[string]any{
+			"id":   clientIPRateLimitRuleID,
+			"name": "Client IP rate limit exceeded",
+			"tags": map[string]any{
+				"type":     "rate_limit",
+				"category": "attack_attempt",
+			},
+			"on_match": []any{"block"},
+		},
+		"rule_matches": []any{
+			map[string]any{
+				"operator":       "rate_limit",
+				"operator_value": "",
+				"parameters": []any{
+					map[string]any{
+						"address":   HTTPClientIPAddr,
+						"key_path":  []any{},
+						"value":     value,
+						"highlight": []any{value},
+					},
+				},
+			},
+		},
+	}
+}
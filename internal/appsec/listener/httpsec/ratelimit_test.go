@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	testlib "github.com/nowfred/dd-trace-go/internal/appsec/_testlib"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/httpsec"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPRateLimiter(t *testing.T) {
+	now := time.Now()
+	rl := NewClientIPRateLimiter(2, 4)
+	rl.now = func() time.Time { return now }
+	ip1, ip2 := netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("5.6.7.8")
+
+	// The burst is allowed at once
+	for i := 0; i < 4; i++ {
+		require.True(t, rl.Allow(ip1))
+	}
+	require.False(t, rl.Allow(ip1))
+	// Other client IPs have their own bucket
+	require.True(t, rl.Allow(ip2))
+
+	// The bucket refills at the rate
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, rl.Allow(ip1))
+	require.False(t, rl.Allow(ip1))
+
+	// The bucket refills up to the burst
+	now = now.Add(time.Minute)
+	for i := 0; i < 4; i++ {
+		require.True(t, rl.Allow(ip1))
+	}
+	require.False(t, rl.Allow(ip1))
+
+	t.Run("default-burst", func(t *testing.T) {
+		rl := NewClientIPRateLimiter(1, 0)
+		rl.now = func() time.Time { return now }
+		require.True(t, rl.Allow(ip1))
+		require.False(t, rl.Allow(ip1))
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		rl := NewClientIPRateLimiter(1, 1)
+		rl.now = func() time.Time { return now }
+		require.True(t, rl.Allow(ip1))
+		for i := 0; len(rl.buckets) < maxClientIPBuckets; i++ {
+			rl.Allow(netip.AddrFrom4([4]byte{10, byte(i >> 16), byte(i >> 8), byte(i)}))
+		}
+		// The buckets are all reset when none of them is full
+		require.True(t, rl.Allow(ip2))
+		require.Len(t, rl.buckets, 1)
+		require.True(t, rl.Allow(ip1))
+	})
+}
+
+func TestClientIPRateLimitEventListener(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		allowed bool
+		blocked bool
+	}{
+		{name: "rate-limited", blocked: true},
+		{name: "allowed", allowed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rl := NewClientIPRateLimiter(1, 1)
+			allowed := func(netip.Addr) bool { return tc.allowed }
+			root := dyngo.NewRootOperation()
+			root.On(NewClientIPRateLimitEventListener(rl, allowed, allowAllLimiter{}))
+			dyngo.SwapRootOperation(root)
+			defer dyngo.SwapRootOperation(dyngo.NewRootOperation())
+
+			var calls int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusOK)
+			})
+
+			var (
+				span *testlib.MockSpan
+				rec  *httptest.ResponseRecorder
+			)
+			for i := 0; i < 2; i++ {
+				span = &testlib.MockSpan{}
+				rec = httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/", nil)
+				req.RemoteAddr = "1.2.3.4:1234"
+				httpsec.WrapHandler(handler, span, nil).ServeHTTP(rec, req)
+			}
+
+			if !tc.blocked {
+				require.Equal(t, 2, calls)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NotContains(t, span.Tags, "_dd.appsec.json")
+				return
+			}
+			require.Equal(t, 1, calls)
+			require.Equal(t, http.StatusTooManyRequests, rec.Code)
+			require.Equal(t, true, span.Tags["appsec.blocked"])
+			require.Contains(t, span.Tags["_dd.appsec.json"], clientIPRateLimitRuleID)
+		})
+	}
+}
+
+type allowAllLimiter struct{}
+
+func (allowAllLimiter) Allow() bool { return true }
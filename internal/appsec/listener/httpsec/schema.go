@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+// schemaTagPrefix is the prefix of the API Security schema tags produced by the
+// WAF schema extraction processors.
+const schemaTagPrefix = "_dd.appsec.s."
+
+type derivativesAdder interface {
+	SetTag(string, any)
+	AddSerializableTag(string, any)
+}
+
+// addDerivatives adds the WAF derivatives as tags. API Security schemas are
+// gzip-compressed and base64-encoded JSON values, as expected by the backend,
+// while the other derivatives are serialized as JSON.
+func addDerivatives(op derivativesAdder, derivatives map[string]any) {
+	for tag, value := range derivatives {
+		if !strings.HasPrefix(tag, schemaTagPrefix) {
+			op.AddSerializableTag(tag, value)
+			continue
+		}
+		encoded, err := encodeSchema(value)
+		if err != nil {
+			log.Debug("appsec: could not encode the api security schema %s: %v", tag, err)
+			continue
+		}
+		op.SetTag(tag, encoded)
+	}
+}
+
+// encodeSchema returns the base64 encoding of the gzip-compressed JSON
+// representation of the given schema.
+func encodeSchema(schema any) (string, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(raw); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package httpsec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDerivatives(t *testing.T) {
+	th := trace.NewTagsHolder()
+	addDerivatives(&th, map[string]any{
+		"_dd.appsec.s.req.params": []any{map[string]any{"param": []any{8}}},
+		"_dd.appsec.fp.http":      "fingerprint",
+	})
+	tags := th.Tags()
+
+	encoded, ok := tags["_dd.appsec.s.req.params"].(string)
+	require.True(t, ok)
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	schema, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, `[{"param":[8]}]`, string(schema))
+
+	require.Equal(t, `"fingerprint"`, tags["_dd.appsec.fp.http"])
+}
@@ -107,3 +107,18 @@ func ProcessHTTPSDKAction(op dyngo.Operation, actions sharedsec.Actions, actionI
 		}
 	}
 }
+
+// ProcessBlockingActions sends the actions to the data listeners of an operation monitoring a call made by the request
+// handler, such as an outbound request or a SQL query. The actions bubble up to the handler operation for its blocking
+// response to be sent once the handler returns, and blockErr is sent to the operation when one of them replaces the
+// response, such as a blocking or redirecting action, so that the call is not performed.
+func ProcessBlockingActions(op dyngo.Operation, actions sharedsec.Actions, actionIds []string, blockErr error) {
+	for _, id := range actionIds {
+		if action, ok := actions[id]; ok {
+			op.EmitData(action)
+			if action.Blocking() || action.HTTP() != nil {
+				op.EmitData(blockErr)
+			}
+		}
+	}
+}
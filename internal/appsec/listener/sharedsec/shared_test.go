@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	waf "github.com/DataDog/go-libddwaf/v2"
+	"github.com/nowfred/dd-trace-go/internal/appsec/dyngo"
+	"github.com/nowfred/dd-trace-go/internal/appsec/emitter/sharedsec"
 	"github.com/nowfred/dd-trace-go/internal/appsec/trace"
 	"github.com/stretchr/testify/require"
 )
@@ -36,3 +38,41 @@ func TestTagsTypes(t *testing.T) {
 		require.Contains(t, tags, tag)
 	}
 }
+
+func TestProcessBlockingActions(t *testing.T) {
+	actions := sharedsec.Actions{
+		"block":    sharedsec.NewBlockRequestAction(403, 10, "auto"),
+		"redirect": sharedsec.NewRedirectRequestAction(303, "/"),
+	}
+	for _, tc := range []struct {
+		name    string
+		ids     []string
+		blocked bool
+	}{
+		{name: "block", ids: []string{"block"}, blocked: true},
+		// the redirection replaces the response, so the call is not performed either
+		{name: "redirect", ids: []string{"redirect"}, blocked: true},
+		{name: "unknown", ids: []string{"unknown"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := dyngo.NewOperation(nil)
+			var parentActions []*sharedsec.Action
+			parent.OnData(dyngo.NewDataListener(func(a *sharedsec.Action) {
+				parentActions = append(parentActions, a)
+			}))
+			op := &sharedsec.OutboundRequestOperation{Operation: dyngo.NewOperation(parent)}
+			var err error
+			sharedsec.OnErrorData(op, func(e *sharedsec.BlockedOutboundRequestError) {
+				err = e
+			})
+
+			ProcessBlockingActions(op, actions, tc.ids, &sharedsec.BlockedOutboundRequestError{URL: "http://169.254.169.254/"})
+			require.Equal(t, tc.blocked, err != nil)
+			if tc.name == "unknown" {
+				require.Empty(t, parentActions)
+			} else {
+				require.Len(t, parentActions, 1)
+			}
+		})
+	}
+}
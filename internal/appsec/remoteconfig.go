@@ -145,6 +145,10 @@ func (a *appsec) onRCRulesUpdate(updates map[string]remoteconfig.ProductUpdate)
 		return map[string]rc.ApplyStatus{}
 	}
 
+	// Serialize with the rules file reloads
+	a.rulesMu.Lock()
+	defer a.rulesMu.Unlock()
+
 	// Create a new local RulesManager
 	r := a.cfg.RulesManager.Clone()
 	statuses, err := combineRCRulesUpdates(&r, updates)
@@ -276,7 +280,7 @@ func mergeRulesDataEntries(entries1, entries2 []rc.ASMDataRuleDataEntry) []rc.AS
 	// Replace the entry only if the new expiration timestamp goes later than the current one
 	// If no expiration timestamp was provided (default to 0), then the data doesn't expire
 	for _, entry := range entries2 {
-		if exp, ok := mergeMap[entry.Value]; !ok || entry.Expiration == 0 || entry.Expiration > exp {
+		if exp, ok := mergeMap[entry.Value]; !ok || exp != 0 && (entry.Expiration == 0 || entry.Expiration > exp) {
 			mergeMap[entry.Value] = entry.Expiration
 		}
 	}
@@ -312,6 +312,27 @@ func TestMergeRulesDataEntries(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "collision-no-expiration-first",
+			in1: []rc.ASMDataRuleDataEntry{
+				{
+					Value:      "127.0.0.1",
+					Expiration: 0,
+				},
+			},
+			in2: []rc.ASMDataRuleDataEntry{
+				{
+					Value:      "127.0.0.1",
+					Expiration: 2,
+				},
+			},
+			out: []rc.ASMDataRuleDataEntry{
+				{
+					Value:      "127.0.0.1",
+					Expiration: 0,
+				},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			res := mergeRulesDataEntries(tc.in1, tc.in2)
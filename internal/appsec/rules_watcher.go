@@ -0,0 +1,177 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	waf "github.com/DataDog/go-libddwaf/v2"
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+	"github.com/nowfred/dd-trace-go/internal/log"
+)
+
+// rulesFileWatcher periodically checks the rules file for changes, and calls
+// onChange with its new content whenever its modification time or size
+// changed. Polling the file is preferred over file system notifications so
+// that atomic file replacements, such as the symlink swaps of Kubernetes
+// ConfigMap volumes, are also detected.
+type rulesFileWatcher struct {
+	path     string
+	interval time.Duration
+	onChange func(rules []byte)
+
+	modTime time.Time
+	size    int64
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newRulesFileWatcher(path string, interval time.Duration, onChange func(rules []byte)) *rulesFileWatcher {
+	return &rulesFileWatcher{
+		path:     path,
+		interval: interval,
+		onChange: onChange,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// start starts watching the rules file. Its current state is taken as the
+// reference so that only later changes are reported.
+func (w *rulesFileWatcher) start() {
+	w.changed()
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// stop stops watching the rules file and waits for an ongoing reload to end.
+func (w *rulesFileWatcher) stop() {
+	close(w.stopCh)
+	w.wg.Wait()
+}
+
+// check reads the rules file and calls onChange when it changed.
+func (w *rulesFileWatcher) check() {
+	if !w.changed() {
+		return
+	}
+	rules, err := os.ReadFile(w.path)
+	if err != nil {
+		log.Error("appsec: could not read the rules file %s: %v", w.path, err)
+		return
+	}
+	w.onChange(rules)
+}
+
+// changed returns true when the modification time or size of the rules file
+// changed since the last call. Errors are logged and reported as unchanged so
+// that the current rules are kept while the file is being replaced.
+func (w *rulesFileWatcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		log.Debug("appsec: could not stat the rules file %s: %v", w.path, err)
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return true
+}
+
+// startRulesFileWatcher starts reloading the rules file set with
+// DD_APPSEC_RULES when DD_APPSEC_RULES_RELOAD_INTERVAL is set.
+func (a *appsec) startRulesFileWatcher() {
+	if a.cfg.RulesFile == "" || a.cfg.RulesReloadInterval <= 0 {
+		return
+	}
+	log.Debug("appsec: reloading the rules file %s every %s", a.cfg.RulesFile, a.cfg.RulesReloadInterval)
+	a.rulesWatcher = newRulesFileWatcher(a.cfg.RulesFile, a.cfg.RulesReloadInterval, func(rules []byte) {
+		if err := a.onRulesFileUpdate(rules); err != nil {
+			log.Error("appsec: could not reload the rules file %s, keeping the current security rules: %v", a.cfg.RulesFile, err)
+		}
+	})
+	a.rulesWatcher.start()
+}
+
+func (a *appsec) stopRulesFileWatcher() {
+	if a.rulesWatcher == nil {
+		return
+	}
+	a.rulesWatcher.stop()
+	a.rulesWatcher = nil
+}
+
+// onRulesFileUpdate replaces the base rules of the RulesManager with the new
+// content of the rules file, while keeping the remote config edits, and swaps
+// the WAF handle with the newly compiled rules.
+func (a *appsec) onRulesFileUpdate(rules []byte) error {
+	var f config.RulesFragment
+	if err := json.Unmarshal(rules, &f); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	a.rulesMu.Lock()
+	defer a.rulesMu.Unlock()
+
+	r := a.cfg.RulesManager.Clone()
+	r.ChangeBase(f, "")
+	r.Compile()
+	log.Debug("appsec: rules file: final compiled rules: %s", r.String())
+
+	if err := a.swapWAF(r.Latest); err != nil {
+		return err
+	}
+	// Replace the RulesManager with the new one holding the new state
+	a.cfg.RulesManager = &r
+
+	logRulesDiagnostics(a.wafHandle.Diagnostics())
+	log.Info("appsec: security rules reloaded from %s", a.cfg.RulesFile)
+	return nil
+}
+
+// logRulesDiagnostics logs the errors reported by the WAF while loading the
+// security rules, which are otherwise only reported through span tags.
+func logRulesDiagnostics(diags waf.Diagnostics) {
+	if err := diags.TopLevelError(); err != nil {
+		log.Error("appsec: the security rules could not be fully loaded: %v", err)
+	}
+	for name, entry := range map[string]*waf.DiagnosticEntry{
+		"rules":           diags.Rules,
+		"custom rules":    diags.CustomRules,
+		"exclusions":      diags.Exclusions,
+		"rules overrides": diags.RulesOverrides,
+		"rules data":      diags.RulesData,
+		"processors":      diags.Processors,
+		"scanners":        diags.Scanners,
+	} {
+		if entry == nil {
+			continue
+		}
+		if entry.Error != "" {
+			log.Error("appsec: could not load the %s: %s", name, entry.Error)
+		}
+		for msg, ids := range entry.Errors {
+			log.Error("appsec: could not load the %s %v: %s", name, ids, msg)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package appsec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	internal "github.com/DataDog/appsec-internal-go/appsec"
+	"github.com/nowfred/dd-trace-go/internal/appsec/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesFileWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":"2.2"}`), 0o600))
+
+	changes := make(chan []byte, 10)
+	w := newRulesFileWatcher(path, time.Millisecond, func(rules []byte) {
+		changes <- rules
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		require.True(t, w.changed())
+		require.False(t, w.changed())
+		w.check()
+		require.Empty(t, changes)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"version":"2.2","rules":[]}`), 0o600))
+		w.check()
+		require.Len(t, changes, 1)
+		require.Equal(t, `{"version":"2.2","rules":[]}`, string(<-changes))
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		w.check()
+		require.Empty(t, changes)
+	})
+
+	t.Run("polling", func(t *testing.T) {
+		w.start()
+		defer w.stop()
+		require.NoError(t, os.WriteFile(path, []byte(`{"version":"2.1"}`), 0o600))
+		select {
+		case rules := <-changes:
+			require.Equal(t, `{"version":"2.1"}`, string(rules))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the rules file change was not detected")
+		}
+	})
+}
+
+func TestRulesFileReload(t *testing.T) {
+	custom, err := os.ReadFile("testdata/custom_rules.json")
+	require.NoError(t, err)
+	blocking, err := os.ReadFile("testdata/blocking.json")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, custom, 0o600))
+	t.Setenv(internal.EnvRules, path)
+	t.Setenv(config.EnvRulesReloadInterval, "1ms")
+	Start()
+	defer Stop()
+	if !Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	hasRule := func(id string) bool {
+		activeAppSec.rulesMu.Lock()
+		defer activeAppSec.rulesMu.Unlock()
+		for _, rule := range activeAppSec.cfg.RulesManager.Latest.Rules {
+			if rule.(map[string]any)["id"] == id {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, hasRule("custom-001"))
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, activeAppSec.onRulesFileUpdate([]byte(`{"version":`)))
+		require.True(t, hasRule("custom-001"))
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, blocking, 0o600))
+		require.Eventually(t, func() bool {
+			return hasRule("blk-001-001")
+		}, 5*time.Second, 10*time.Millisecond)
+		require.False(t, hasRule("custom-001"))
+	})
+}
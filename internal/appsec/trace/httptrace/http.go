@@ -6,7 +6,9 @@
 package httptrace
 
 import (
+	"net"
 	"net/netip"
+	"net/textproto"
 	"os"
 	"strings"
 
@@ -18,6 +20,10 @@ import (
 const (
 	// envClientIPHeader is the name of the env var used to specify the IP header to be used for client IP collection.
 	envClientIPHeader = "DD_TRACE_CLIENT_IP_HEADER"
+	// envClientIPTrustedProxies is the name of the env var used to specify the comma-separated list of IP addresses
+	// and CIDR ranges of the trusted proxies, whose envClientIPHeader header is the only one to be used for client IP
+	// collection.
+	envClientIPTrustedProxies = "DD_TRACE_CLIENT_IP_TRUSTED_PROXIES"
 )
 
 var (
@@ -61,18 +67,125 @@ var (
 	// retrieve the public client IP address in ClientIP. This is defined at init
 	// time in dunction of the value of the envClientIPHeader environment variable.
 	monitoredClientIPHeadersCfg []string
+
+	// clientIPHeaderCfg is the IP-related header set by the trusted proxies, defined at init time out of the value
+	// of the envClientIPHeader environment variable. The client IP address is the remote one when it is empty.
+	clientIPHeaderCfg string
+
+	// trustedProxiesCfg is the list of IP ranges of the trusted proxies. When not empty, the IP-related headers are
+	// only used when the remote address is a trusted proxy. This is defined at init time in function of the value of
+	// the envClientIPTrustedProxies environment variable.
+	trustedProxiesCfg []netip.Prefix
 )
 
 // ClientIPTags returns the resulting Datadog span tags `http.client_ip`
 // containing the client IP and `network.client.ip` containing the remote IP.
 // The tags are present only if a valid ip address has been returned by
 // ClientIP().
+// When trusted proxies are configured, the client IP is resolved with
+// trustedClientIP() instead so that it cannot be spoofed with the headers.
 func ClientIPTags(headers map[string][]string, hasCanonicalHeaders bool, remoteAddr string) (tags map[string]string, clientIP netip.Addr) {
-	remoteIP, clientIP := httpsec.ClientIP(headers, hasCanonicalHeaders, remoteAddr, monitoredClientIPHeadersCfg)
+	var remoteIP netip.Addr
+	if len(trustedProxiesCfg) > 0 {
+		remoteIP, clientIP = trustedClientIP(headers, hasCanonicalHeaders, remoteAddr)
+	} else {
+		remoteIP, clientIP = httpsec.ClientIP(headers, hasCanonicalHeaders, remoteAddr, monitoredClientIPHeadersCfg)
+	}
 	tags = httpsec.ClientIPTags(remoteIP, clientIP)
 	return tags, clientIP
 }
 
+// trustedClientIP returns the client IP address according to the trusted
+// proxies. The remote IP address is the client one unless it is a trusted
+// proxy, in which case the header configured with envClientIPHeader is walked
+// from right to left, as each proxy appends the address of its peer, and the
+// first IP address not belonging to a trusted proxy is the client one. The
+// left-most valid IP address is returned when they all are trusted proxies.
+// This way, the IP addresses added by the client itself are never used. The
+// other IP-related headers are ignored, as the proxies may pass them through
+// as sent by the client, and the remote IP address is the client one when no
+// header is configured.
+func trustedClientIP(headers map[string][]string, hasCanonicalHeaders bool, remoteAddr string) (remoteIP, clientIP netip.Addr) {
+	remoteIP = parseIP(remoteAddr)
+	clientIP = remoteIP
+	if !isTrustedProxy(remoteIP) {
+		return remoteIP, clientIP
+	}
+	headerName := clientIPHeaderCfg
+	if headerName == "" {
+		return remoteIP, clientIP
+	}
+	if hasCanonicalHeaders {
+		headerName = textproto.CanonicalMIMEHeaderKey(headerName)
+	}
+	var ips []string
+	for _, v := range headers[headerName] {
+		ips = append(ips, strings.Split(v, ",")...)
+	}
+	for i := len(ips) - 1; i >= 0; i-- {
+		ip := parseIP(strings.TrimSpace(ips[i]))
+		if !ip.IsValid() {
+			continue
+		}
+		clientIP = ip
+		if !isTrustedProxy(ip) {
+			break
+		}
+	}
+	return remoteIP, clientIP
+}
+
+// isTrustedProxy returns true when the given IP address belongs to a trusted proxy.
+func isTrustedProxy(ip netip.Addr) bool {
+	if !ip.IsValid() {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range trustedProxiesCfg {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIP parses the given IP address, with or without a port.
+func parseIP(s string) netip.Addr {
+	if ip, err := netip.ParseAddr(s); err == nil {
+		return ip
+	}
+	if h, _, err := net.SplitHostPort(s); err == nil {
+		if ip, err := netip.ParseAddr(h); err == nil {
+			return ip
+		}
+	}
+	return netip.Addr{}
+}
+
+// parseTrustedProxies parses the given comma-separated list of IP addresses
+// and CIDR ranges. Invalid entries are logged and ignored.
+func parseTrustedProxies(s string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			if p, err := netip.ParsePrefix(entry); err == nil {
+				prefixes = append(prefixes, p.Masked())
+				continue
+			}
+		} else if ip, err := netip.ParseAddr(entry); err == nil {
+			ip = ip.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		log.Error("appsec: ignoring the invalid IP address or CIDR range `%s` of %s", entry, envClientIPTrustedProxies)
+	}
+	return prefixes
+}
+
 // NormalizeHTTPHeaders returns the HTTP headers following Datadog's
 // normalization format.
 func NormalizeHTTPHeaders(headers map[string][]string) (normalized map[string]string) {
@@ -113,7 +226,12 @@ func init() {
 		collectedHTTPHeaders[cfg] = struct{}{}
 		// Make this header the only one to consider in ClientIP
 		monitoredClientIPHeadersCfg = []string{cfg}
+		clientIPHeaderCfg = cfg
 	} else {
 		monitoredClientIPHeadersCfg = defaultIPHeaders
 	}
+
+	if cfg := os.Getenv(envClientIPTrustedProxies); cfg != "" {
+		trustedProxiesCfg = parseTrustedProxies(cfg)
+	}
 }
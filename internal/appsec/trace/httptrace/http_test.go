@@ -61,6 +61,112 @@ func TestClientIP(t *testing.T) {
 	}
 }
 
+func TestClientIPTrustedProxies(t *testing.T) {
+	defer func(cfg []netip.Prefix, header string) {
+		trustedProxiesCfg, clientIPHeaderCfg = cfg, header
+	}(trustedProxiesCfg, clientIPHeaderCfg)
+	trustedProxiesCfg = parseTrustedProxies("10.0.0.0/8, 2001:db8::1")
+	clientIPHeaderCfg = "x-forwarded-for"
+
+	for _, tc := range []struct {
+		name             string
+		remoteAddr       string
+		headers          map[string][]string
+		expectedClientIP string
+	}{
+		{
+			name:             "untrusted-remote-address",
+			remoteAddr:       "1.2.3.4:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"5.6.7.8"}},
+			expectedClientIP: "1.2.3.4",
+		},
+		{
+			name:             "trusted-remote-address",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"5.6.7.8"}},
+			expectedClientIP: "5.6.7.8",
+		},
+		{
+			name:             "spoofed-header",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"9.9.9.9, 5.6.7.8, 10.0.0.2"}},
+			expectedClientIP: "5.6.7.8",
+		},
+		{
+			name:             "multiple-header-values",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"9.9.9.9", "5.6.7.8"}},
+			expectedClientIP: "5.6.7.8",
+		},
+		{
+			name:             "private-client-address",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"192.168.1.1, 10.0.0.2"}},
+			expectedClientIP: "192.168.1.1",
+		},
+		{
+			name:             "only-trusted-proxies",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"10.0.0.3, invalid, 10.0.0.2"}},
+			expectedClientIP: "10.0.0.3",
+		},
+		{
+			name:             "no-header",
+			remoteAddr:       "[2001:db8::1]:6789",
+			expectedClientIP: "2001:db8::1",
+		},
+		{
+			name:             "ipv6-trusted-proxy",
+			remoteAddr:       "[2001:db8::1]:6789",
+			headers:          map[string][]string{"X-Forwarded-For": {"5.6.7.8"}},
+			expectedClientIP: "5.6.7.8",
+		},
+		{
+			name:             "other-header",
+			remoteAddr:       "10.0.0.1:6789",
+			headers:          map[string][]string{"X-Real-Ip": {"5.6.7.8"}},
+			expectedClientIP: "10.0.0.1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, clientIP := ClientIPTags(tc.headers, true, tc.remoteAddr)
+			require.Equal(t, tc.expectedClientIP, clientIP.String())
+			require.Equal(t, tc.expectedClientIP, tags["http.client_ip"])
+		})
+	}
+}
+
+func TestClientIPTrustedProxiesHeader(t *testing.T) {
+	defer func(cfg []netip.Prefix, header string) {
+		trustedProxiesCfg, clientIPHeaderCfg = cfg, header
+	}(trustedProxiesCfg, clientIPHeaderCfg)
+	trustedProxiesCfg = parseTrustedProxies("10.0.0.0/8")
+
+	// The proxy sets X-Real-Ip and passes through the X-Forwarded-For header sent by the client
+	headers := map[string][]string{
+		"X-Forwarded-For": {"9.9.9.9"},
+		"X-Real-Ip":       {"5.6.7.8"},
+	}
+
+	clientIPHeaderCfg = "x-real-ip"
+	_, clientIP := ClientIPTags(headers, true, "10.0.0.1:6789")
+	require.Equal(t, "5.6.7.8", clientIP.String())
+
+	// The remote address is used when no header is configured
+	clientIPHeaderCfg = ""
+	_, clientIP = ClientIPTags(headers, true, "10.0.0.1:6789")
+	require.Equal(t, "10.0.0.1", clientIP.String())
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	require.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("1.2.3.4/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}, parseTrustedProxies("10.1.2.3/8,, ::ffff:1.2.3.4 ,invalid,1.2.3.4/64, 2001:db8::/32"))
+	require.Empty(t, parseTrustedProxies(""))
+}
+
 func TestNormalizeHTTPHeaders(t *testing.T) {
 	for _, tc := range []struct {
 		headers  map[string][]string
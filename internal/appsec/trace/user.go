@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+package trace
+
+import (
+	"github.com/nowfred/dd-trace-go/ddtrace/ext"
+)
+
+const (
+	loginSuccessEventPrefix = "appsec.events.users.login.success."
+	loginFailureEventPrefix = "appsec.events.users.login.failure."
+	userCollectionModeTag   = "_dd.appsec.user.collection_mode"
+)
+
+// SetAutoUserLoginSuccessTags sets the service entry span tags of a user login
+// success event detected by the automated user events tracking in the given
+// mode. The user ID is expected to be anonymized according to the mode, and
+// is not set when empty.
+func SetAutoUserLoginSuccessTags(span TagSetter, uid, mode string) {
+	span.SetTag(loginSuccessEventPrefix+"track", true)
+	span.SetTag("_dd."+loginSuccessEventPrefix+"auto.mode", mode)
+	span.SetTag(ext.SamplingPriority, ext.PriorityUserKeep)
+	if uid != "" {
+		SetAutoUserTags(span, uid, mode)
+	}
+}
+
+// SetAutoUserLoginFailureTags sets the service entry span tags of a user login
+// failure event detected by the automated user events tracking in the given
+// mode. The user ID is expected to be anonymized according to the mode, and
+// is not set when empty.
+func SetAutoUserLoginFailureTags(span TagSetter, uid, mode string) {
+	span.SetTag(loginFailureEventPrefix+"track", true)
+	span.SetTag("_dd."+loginFailureEventPrefix+"auto.mode", mode)
+	span.SetTag(ext.SamplingPriority, ext.PriorityUserKeep)
+	if uid != "" {
+		span.SetTag(loginFailureEventPrefix+"usr.id", uid)
+	}
+}
+
+// SetAutoUserTags sets the service entry span tags of the authenticated user
+// detected by the automated user events tracking in the given mode. The user
+// ID is expected to be anonymized according to the mode.
+func SetAutoUserTags(span TagSetter, uid, mode string) {
+	span.SetTag("usr.id", uid)
+	span.SetTag(userCollectionModeTag, mode)
+}
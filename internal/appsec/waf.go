@@ -37,6 +37,9 @@ type wafHandle struct {
 }
 
 func (a *appsec) swapWAF(rules config.RulesFragment) (err error) {
+	// Apply the local IP lists on top of the rules data of the rules
+	rules = localIPLists.apply(rules)
+
 	// Instantiate a new WAF handle and verify its state
 	newHandle, err := newWAFHandle(rules, a.cfg)
 	if err != nil {
@@ -50,7 +53,7 @@ func (a *appsec) swapWAF(rules config.RulesFragment) (err error) {
 		}
 	}()
 
-	listeners, err := newWAFEventListeners(newHandle, a.cfg, a.limiter)
+	listeners, err := newWAFEventListeners(newHandle, a.cfg, a.limiter, a.clientIPRateLimiter)
 	if err != nil {
 		return err
 	}
@@ -115,7 +118,7 @@ func newWAFHandle(rules config.RulesFragment, cfg *config.Config) (*wafHandle, e
 	}, err
 }
 
-func newWAFEventListeners(waf *wafHandle, cfg *config.Config, l limiter.Limiter) (listeners []dyngo.EventListener, err error) {
+func newWAFEventListeners(waf *wafHandle, cfg *config.Config, l limiter.Limiter, rl *httpsec.ClientIPRateLimiter) (listeners []dyngo.EventListener, err error) {
 	// Check if there are addresses in the rule
 	ruleAddresses := waf.Addresses()
 	if len(ruleAddresses) == 0 {
@@ -163,7 +166,17 @@ func newWAFEventListeners(waf *wafHandle, cfg *config.Config, l limiter.Limiter)
 
 	if len(httpAddresses) > 0 {
 		log.Debug("appsec: creating http waf event listener of the rules addresses %v", httpAddresses)
-		listeners = append(listeners, httpsec.NewWAFEventListener(waf.Handle, waf.actions, httpAddresses, cfg.WAFTimeout, &cfg.APISec, l))
+		listeners = append(listeners, httpsec.NewWAFEventListener(waf.Handle, waf.actions, httpAddresses, cfg.WAFTimeout, &cfg.APISec, cfg.MaxResponseBodySize, l))
+	}
+
+	if cfg.UserEventsMode.Enabled() && len(cfg.LoginRules) > 0 {
+		log.Debug("appsec: creating the http login event listener of the login rules %v", cfg.LoginRules)
+		listeners = append(listeners, httpsec.NewLoginEventListener(cfg.LoginRules, cfg.UserEventsMode))
+	}
+
+	if rl != nil {
+		log.Debug("appsec: creating the http client ip rate limit event listener of %d requests per second", cfg.ClientIPRateLimit)
+		listeners = append(listeners, httpsec.NewClientIPRateLimitEventListener(rl, IPAllowed, l))
 	}
 
 	return listeners, nil
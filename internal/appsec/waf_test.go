@@ -430,6 +430,60 @@ func TestBlocking(t *testing.T) {
 }
 
 // Test that API Security schemas get collected when API security is enabled
+// TestResponseBodyBlocking validates that JSON response bodies are buffered and inspected by the WAF, and that the
+// response gets replaced by the blocking one when the WAF asks for it.
+func TestResponseBodyBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "testdata/response_body.json")
+	appsec.Start()
+	defer appsec.Stop()
+
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	// Start and trace an HTTP server
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/card", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"card":"` + r.URL.Query().Get("number") + `"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name    string
+		number  string
+		blocked bool
+	}{
+		{name: "block", number: "4111 1111 1111 1111", blocked: true},
+		{name: "no-block", number: "XXXX XXXX XXXX 1111"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			res, err := srv.Client().Get(srv.URL + "/card?number=" + url.QueryEscape(tc.number))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			if !tc.blocked {
+				require.Equal(t, http.StatusOK, res.StatusCode)
+				require.Equal(t, `{"card":"`+tc.number+`"}`, string(b))
+				require.Nil(t, spans[0].Tag("_dd.appsec.json"))
+				return
+			}
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+			require.NotContains(t, string(b), tc.number)
+			require.Contains(t, spans[0].Tag("_dd.appsec.json"), "leak-001-001")
+			require.Equal(t, true, spans[0].Tag("appsec.blocked"))
+		})
+	}
+}
+
 func TestAPISecurity(t *testing.T) {
 	// Start and trace an HTTP server
 	t.Setenv(config.EnvEnabled, "true")
@@ -439,7 +493,8 @@ func TestAPISecurity(t *testing.T) {
 	mux := httptrace.NewServeMux()
 	mux.HandleFunc("/apisec", func(w http.ResponseWriter, r *http.Request) {
 		pAppsec.MonitorParsedHTTPBody(r.Context(), "plain body")
-		w.Write([]byte("Hello World!\n"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"Hello World!"}`))
 	})
 	srv := httptest.NewServer(mux)
 	defer srv.Close()
@@ -467,6 +522,7 @@ func TestAPISecurity(t *testing.T) {
 		require.NotNil(t, spans[0].Tag("_dd.appsec.s.req.headers"))
 		require.NotNil(t, spans[0].Tag("_dd.appsec.s.req.query"))
 		require.NotNil(t, spans[0].Tag("_dd.appsec.s.req.body"))
+		require.NotNil(t, spans[0].Tag("_dd.appsec.s.res.body"))
 	})
 
 	t.Run("disabled", func(t *testing.T) {
@@ -488,5 +544,6 @@ func TestAPISecurity(t *testing.T) {
 		require.Nil(t, spans[0].Tag("_dd.appsec.s.req.headers"))
 		require.Nil(t, spans[0].Tag("_dd.appsec.s.req.query"))
 		require.Nil(t, spans[0].Tag("_dd.appsec.s.req.body"))
+		require.Nil(t, spans[0].Tag("_dd.appsec.s.res.body"))
 	})
 }
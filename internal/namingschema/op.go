@@ -27,6 +27,10 @@ const (
 	KafkaInbound
 	GCPPubSubInbound
 	GCPPubSubOutbound
+	NATSOutbound
+	NATSInbound
+	RabbitMQOutbound
+	RabbitMQInbound
 
 	// cache
 	MemcachedOutbound
@@ -69,6 +73,14 @@ func opV1(t IntegrationType) string {
 		return "gcp.pubsub.process"
 	case GCPPubSubOutbound:
 		return "gcp.pubsub.send"
+	case NATSOutbound:
+		return "nats.send"
+	case NATSInbound:
+		return "nats.process"
+	case RabbitMQOutbound:
+		return "rabbitmq.send"
+	case RabbitMQInbound:
+		return "rabbitmq.process"
 
 	// Cache
 	case MemcachedOutbound:
@@ -117,6 +129,14 @@ func opV0(t IntegrationType) string {
 		return "pubsub.receive"
 	case GCPPubSubOutbound:
 		return "pubsub.publish"
+	case NATSOutbound:
+		return "nats.publish"
+	case NATSInbound:
+		return "nats.consume"
+	case RabbitMQOutbound:
+		return "amqp.publish"
+	case RabbitMQInbound:
+		return "amqp.consume"
 	case MemcachedOutbound:
 		return "memcached.query"
 	case RedisOutbound: